@@ -10,6 +10,8 @@ import (
 
 	"github.com/user/go-boilerplate/internal/app"
 	"github.com/user/go-boilerplate/internal/config"
+	"github.com/user/go-boilerplate/internal/shared/datascope"
+	"github.com/user/go-boilerplate/internal/shared/impersonation"
 	"github.com/user/go-boilerplate/pkg/cache"
 	"github.com/user/go-boilerplate/pkg/logger"
 	"go.uber.org/zap"
@@ -111,6 +113,16 @@ func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Branch-scoped tables are filtered by the request's data scope
+	if err := db.Use(datascope.Plugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register data scope plugin: %w", err)
+	}
+
+	// Writes made while impersonating are journaled with the administrator
+	if err := db.Use(impersonation.Plugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register impersonation plugin: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
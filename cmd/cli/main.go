@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -9,10 +10,18 @@ import (
 
 	"github.com/user/go-boilerplate/internal/config"
 	"github.com/user/go-boilerplate/internal/database/migration"
+	"github.com/user/go-boilerplate/internal/database/schemacheck"
+	"github.com/user/go-boilerplate/internal/modules/auth"
 	authseeder "github.com/user/go-boilerplate/internal/modules/auth/seeder"
+	"github.com/user/go-boilerplate/internal/modules/master"
+	masterrepository "github.com/user/go-boilerplate/internal/modules/master/repository"
 	masterseeder "github.com/user/go-boilerplate/internal/modules/master/seeder"
+	masterservice "github.com/user/go-boilerplate/internal/modules/master/service"
+	"github.com/user/go-boilerplate/internal/modules/system"
 	systemseeder "github.com/user/go-boilerplate/internal/modules/system/seeder"
+	"github.com/user/go-boilerplate/internal/modules/transaction"
 	transactionseeder "github.com/user/go-boilerplate/internal/modules/transaction/seeder"
+	"github.com/user/go-boilerplate/pkg/cache"
 	"github.com/user/go-boilerplate/pkg/logger"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
@@ -29,6 +38,13 @@ var moduleMigrations = map[string]string{
 
 var migrationOrder = []string{"auth", "system", "master", "transaction"}
 
+var moduleEntities = map[string][]any{
+	"auth":        auth.Entities,
+	"master":      master.Entities,
+	"system":      system.Entities,
+	"transaction": transaction.Entities,
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
@@ -127,6 +143,53 @@ func main() {
 		}
 		fmt.Println("V Database reset completed")
 
+	case "schema:verify":
+		db_verify, err := initDatabase(cfg)
+		if err != nil {
+			logger.Log.Fatal("Failed to connect to database", zap.Error(err))
+		}
+		db_verify = db_verify.Session(&gorm.Session{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+		total := 0
+		for _, mod := range migrationOrder {
+			fmt.Printf("-> Verifying: %s\n", mod)
+			drifts, err := schemacheck.Verify(db_verify, moduleEntities[mod]...)
+			if err != nil {
+				logger.Log.Fatal("Schema verification failed", zap.String("module", mod), zap.Error(err))
+			}
+			for _, d := range drifts {
+				fmt.Printf("  %s\n", d)
+			}
+			total += len(drifts)
+		}
+		if total > 0 {
+			fmt.Printf("X Schema drift: %d difference(s) between entities and database\n", total)
+			os.Exit(1)
+		}
+		fmt.Println("V Entities match the database")
+
+	case "cache:warm":
+		db_warm, err := initDatabase(cfg)
+		if err != nil {
+			logger.Log.Fatal("Failed to connect to database", zap.Error(err))
+		}
+		redisClient := cache.NewRedisClient(cache.RedisConfig{
+			Host:     cfg.RedisHost,
+			Port:     cfg.RedisPort,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+		defer redisClient.Close()
+		if err := redisClient.Ping(context.Background()); err != nil {
+			logger.Log.Fatal("Failed to connect to Redis", zap.Error(err))
+		}
+		tables := master.Tables.All()
+		fmt.Printf("-> Warming %d master tables...\n", len(tables))
+		warmer := masterservice.NewCacheService(masterrepository.NewMasterRepository(db_warm), redisClient)
+		if err := warmer.Warm(context.Background(), tables); err != nil {
+			logger.Log.Fatal("Cache warm-up failed", zap.Error(err))
+		}
+		fmt.Println("V Master cache warmed")
+
 	case "seed":
 		db_seed, _ := initDatabase(cfg)
 		fmt.Println("-> Seeding auth...")
@@ -191,7 +254,7 @@ func showStatus(sqlDB *sql.DB, path string, tableName string) {
 }
 
 func printUsage() {
-	fmt.Println(`
+	fmt.Print(`
 Database CLI
 
 Migrations:
@@ -204,6 +267,12 @@ Migrations:
   migrate:status       Status
   migrate:fresh        Drop & re-migrate
 
+Schema:
+  schema:verify        Compare entities with the database (exit 1 on drift)
+
+Cache:
+  cache:warm           Load every master table into Redis
+
 Seeders:
   seed                 All modules
   seed:auth            Auth only
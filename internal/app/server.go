@@ -61,14 +61,14 @@ func (s *Server) Setup() {
 
 	// Initialize modules
 	healthModule := health.New(s.db)
-	authModule := auth.New(s.db, s.config)
+	authModule := auth.New(s.db, s.config, s.cache)
 	fileModule := file.New(s.config)
 	masterModule := master.New(s.db, s.config, s.cache)
 	systemModule := system.New(s.db, s.config)
 	transactionModule := transaction.New(s.db, s.config)
 
 	// JWT middleware
-	jwtMiddleware := auth.CreateJWTMiddleware(s.config)
+	jwtMiddleware := auth.CreateJWTMiddleware(authModule.Keys, authModule.Service, authModule.ServiceAccounts)
 
 	// Register module routes
 	healthModule.RegisterRoutes(s.router)
@@ -77,6 +77,8 @@ func (s *Server) Setup() {
 	// Protected API routes
 	api := s.router.Group("/api")
 	api.Use(jwtMiddleware)
+	api.Use(middleware.DataScope(authModule.DataScopes))
+	authModule.RegisterAdminRoutes(api)
 	fileModule.RegisterRoutes(api)
 	masterModule.RegisterRoutes(api)
 	systemModule.RegisterRoutes(api)
@@ -13,8 +13,92 @@ type Config struct {
 	AppEnv  string `mapstructure:"APP_ENV"`
 
 	// JWT
-	JWTSecret      string `mapstructure:"JWT_SECRET"`
-	JWTExpiryHours int    `mapstructure:"JWT_EXPIRY_HOURS"`
+	JWTSecret              string `mapstructure:"JWT_SECRET"`
+	JWTAccessExpiryMinutes int    `mapstructure:"JWT_ACCESS_EXPIRY_MINUTES"`
+	JWTRefreshExpiryHours  int    `mapstructure:"JWT_REFRESH_EXPIRY_HOURS"`
+	JWTAlgorithm           string `mapstructure:"JWT_ALGORITHM"` // HS256, RS256 or EdDSA
+	JWTPrivateKeyFile      string `mapstructure:"JWT_PRIVATE_KEY_FILE"`
+	JWTKeyID               string `mapstructure:"JWT_KEY_ID"`      // Optional: derived from the public key
+	JWTPublicKeys          string `mapstructure:"JWT_PUBLIC_KEYS"` // Extra verification keys: kid=path,kid=path
+	JWTIssuer              string `mapstructure:"JWT_ISSUER"`
+	JWTAudience            string `mapstructure:"JWT_AUDIENCE"`
+
+	// Login protection
+	LoginMaxAttempts          int `mapstructure:"LOGIN_MAX_ATTEMPTS"`
+	LoginIPMaxAttempts        int `mapstructure:"LOGIN_IP_MAX_ATTEMPTS"`
+	LoginAttemptWindowMinutes int `mapstructure:"LOGIN_ATTEMPT_WINDOW_MINUTES"`
+	LoginLockoutMinutes       int `mapstructure:"LOGIN_LOCKOUT_MINUTES"`
+	LoginLockoutMaxMinutes    int `mapstructure:"LOGIN_LOCKOUT_MAX_MINUTES"`
+
+	// Sessions
+	SessionMaxPerUser int `mapstructure:"SESSION_MAX_PER_USER"` // Negative disables the cap
+
+	// Admin impersonation
+	ImpersonationExpiryMinutes int `mapstructure:"IMPERSONATION_EXPIRY_MINUTES"`
+
+	// Two-factor authentication
+	MFAIssuer                 string `mapstructure:"MFA_ISSUER"`
+	MFAEncryptionKey          string `mapstructure:"MFA_ENCRYPTION_KEY"` // Required; must differ from JWT_SECRET
+	MFAChallengeExpiryMinutes int    `mapstructure:"MFA_CHALLENGE_EXPIRY_MINUTES"`
+
+	// Registration
+	RegistrationMode           string `mapstructure:"REGISTRATION_MODE"`            // disabled, domain or invite
+	RegistrationAllowedDomains string `mapstructure:"REGISTRATION_ALLOWED_DOMAINS"` // Comma-separated, for domain mode
+	InviteURL                  string `mapstructure:"INVITE_URL"`
+	InviteExpiryHours          int    `mapstructure:"INVITE_EXPIRY_HOURS"`
+
+	// OpenID Connect single sign-on (enabled when OIDC_ISSUER_URL is set)
+	OIDCIssuerURL    string `mapstructure:"OIDC_ISSUER_URL"`
+	OIDCClientID     string `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string `mapstructure:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL  string `mapstructure:"OIDC_REDIRECT_URL"`
+	OIDCScopes       string `mapstructure:"OIDC_SCOPES"`       // Comma-separated
+	OIDCGroupsClaim  string `mapstructure:"OIDC_GROUPS_CLAIM"` // ID token claim holding group names
+	OIDCGroupRoles   string `mapstructure:"OIDC_GROUP_ROLES"`  // group=role_id[:sub_role_id];...
+	OIDCLinkOnly     bool   `mapstructure:"OIDC_LINK_ONLY"`    // Reject emails without an existing account
+
+	// Credential backends, tried in order: local (bcrypt on sys_users) and/or ldap
+	AuthBackends string `mapstructure:"AUTH_BACKENDS"` // Comma-separated, e.g. ldap,local
+
+	// LDAP / Active Directory (used when AUTH_BACKENDS includes ldap)
+	LDAPURL                   string `mapstructure:"LDAP_URL"` // ldap://host:389 or ldaps://host:636
+	LDAPStartTLS              bool   `mapstructure:"LDAP_START_TLS"`
+	LDAPTLSCAFile             string `mapstructure:"LDAP_TLS_CA_FILE"`
+	LDAPTLSServerName         string `mapstructure:"LDAP_TLS_SERVER_NAME"`
+	LDAPTLSInsecureSkipVerify bool   `mapstructure:"LDAP_TLS_INSECURE_SKIP_VERIFY"` // Local development only
+	LDAPBindDN                string `mapstructure:"LDAP_BIND_DN"`
+	LDAPBindPassword          string `mapstructure:"LDAP_BIND_PASSWORD"`
+	LDAPBaseDN                string `mapstructure:"LDAP_BASE_DN"`
+	LDAPUserFilter            string `mapstructure:"LDAP_USER_FILTER"`   // %s is the login email
+	LDAPGroupBaseDN           string `mapstructure:"LDAP_GROUP_BASE_DN"` // Defaults to LDAP_BASE_DN
+	LDAPGroupFilter           string `mapstructure:"LDAP_GROUP_FILTER"`  // Optional, %s is the user DN
+	LDAPAttrEmail             string `mapstructure:"LDAP_ATTR_EMAIL"`
+	LDAPAttrFullName          string `mapstructure:"LDAP_ATTR_FULL_NAME"`
+	LDAPAttrEmployeeNumber    string `mapstructure:"LDAP_ATTR_EMPLOYEE_NUMBER"`
+	LDAPAttrDivision          string `mapstructure:"LDAP_ATTR_DIVISION"`
+	LDAPAttrGroups            string `mapstructure:"LDAP_ATTR_GROUPS"`
+	LDAPGroupRoles            string `mapstructure:"LDAP_GROUP_ROLES"` // group=role_id[:sub_role_id];...
+	LDAPTimeoutSeconds        int    `mapstructure:"LDAP_TIMEOUT_SECONDS"`
+
+	// Password policy
+	PasswordMinLength       int    `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordRequiredClasses string `mapstructure:"PASSWORD_REQUIRED_CLASSES"` // Comma-separated: upper,lower,digit,symbol
+	PasswordCommonListFile  string `mapstructure:"PASSWORD_COMMON_LIST_FILE"` // Optional: extends the built-in list
+	PasswordExpiryDays      int    `mapstructure:"PASSWORD_EXPIRY_DAYS"`      // Negative disables expiry
+	PasswordHistorySize     int    `mapstructure:"PASSWORD_HISTORY_SIZE"`     // Negative disables reuse checks
+
+	// Password reset
+	PasswordResetURL           string `mapstructure:"PASSWORD_RESET_URL"`
+	PasswordResetExpiryMinutes int    `mapstructure:"PASSWORD_RESET_EXPIRY_MINUTES"`
+
+	// Mail
+	MailDriver    string `mapstructure:"MAIL_DRIVER"` // smtp or file
+	MailFrom      string `mapstructure:"MAIL_FROM"`
+	MailOutboxDir string `mapstructure:"MAIL_OUTBOX_DIR"`
+	SMTPHost      string `mapstructure:"SMTP_HOST"`
+	SMTPPort      string `mapstructure:"SMTP_PORT"`
+	SMTPUsername  string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword  string `mapstructure:"SMTP_PASSWORD"`
 
 	// Logging
 	LogLevel string `mapstructure:"LOG_LEVEL"`
@@ -86,8 +170,107 @@ func setDefaults(config *Config) {
 	if config.DBSSLMode == "" {
 		config.DBSSLMode = "disable"
 	}
-	if config.JWTExpiryHours == 0 {
-		config.JWTExpiryHours = 72
+	if config.JWTAccessExpiryMinutes == 0 {
+		config.JWTAccessExpiryMinutes = 15
+	}
+	if config.JWTRefreshExpiryHours == 0 {
+		config.JWTRefreshExpiryHours = 168
+	}
+	if config.JWTAlgorithm == "" {
+		config.JWTAlgorithm = "HS256"
+	}
+	if config.JWTIssuer == "" {
+		config.JWTIssuer = "go-boilerplate"
+	}
+	if config.LoginMaxAttempts == 0 {
+		config.LoginMaxAttempts = 5
+	}
+	if config.LoginIPMaxAttempts == 0 {
+		config.LoginIPMaxAttempts = 20
+	}
+	if config.LoginAttemptWindowMinutes == 0 {
+		config.LoginAttemptWindowMinutes = 15
+	}
+	if config.LoginLockoutMinutes == 0 {
+		config.LoginLockoutMinutes = 15
+	}
+	if config.LoginLockoutMaxMinutes == 0 {
+		config.LoginLockoutMaxMinutes = 1440
+	}
+	if config.SessionMaxPerUser == 0 {
+		config.SessionMaxPerUser = 10
+	}
+	if config.ImpersonationExpiryMinutes == 0 {
+		config.ImpersonationExpiryMinutes = 15
+	}
+	if config.MFAIssuer == "" {
+		config.MFAIssuer = "Go Boilerplate"
+	}
+	if config.MFAChallengeExpiryMinutes == 0 {
+		config.MFAChallengeExpiryMinutes = 5
+	}
+	if config.RegistrationMode == "" {
+		config.RegistrationMode = "invite"
+	}
+	if config.InviteExpiryHours == 0 {
+		config.InviteExpiryHours = 72
+	}
+	if config.OIDCScopes == "" {
+		config.OIDCScopes = "openid,email,profile"
+	}
+	if config.OIDCGroupsClaim == "" {
+		config.OIDCGroupsClaim = "groups"
+	}
+	if config.AuthBackends == "" {
+		config.AuthBackends = "local"
+	}
+	if config.LDAPUserFilter == "" {
+		config.LDAPUserFilter = "(mail=%s)"
+	}
+	if config.LDAPAttrEmail == "" {
+		config.LDAPAttrEmail = "mail"
+	}
+	if config.LDAPAttrFullName == "" {
+		config.LDAPAttrFullName = "cn"
+	}
+	if config.LDAPAttrEmployeeNumber == "" {
+		config.LDAPAttrEmployeeNumber = "employeeNumber"
+	}
+	if config.LDAPAttrDivision == "" {
+		config.LDAPAttrDivision = "departmentNumber"
+	}
+	if config.LDAPAttrGroups == "" {
+		config.LDAPAttrGroups = "memberOf"
+	}
+	if config.LDAPTimeoutSeconds == 0 {
+		config.LDAPTimeoutSeconds = 10
+	}
+	if config.PasswordMinLength == 0 {
+		config.PasswordMinLength = 10
+	}
+	if config.PasswordRequiredClasses == "" {
+		config.PasswordRequiredClasses = "upper,lower,digit"
+	}
+	if config.PasswordExpiryDays == 0 {
+		config.PasswordExpiryDays = 90
+	}
+	if config.PasswordHistorySize == 0 {
+		config.PasswordHistorySize = 5
+	}
+	if config.PasswordResetExpiryMinutes == 0 {
+		config.PasswordResetExpiryMinutes = 30
+	}
+	if config.MailDriver == "" {
+		config.MailDriver = "file"
+	}
+	if config.MailFrom == "" {
+		config.MailFrom = "no-reply@example.com"
+	}
+	if config.MailOutboxDir == "" {
+		config.MailOutboxDir = "storage/outbox"
+	}
+	if config.SMTPPort == "" {
+		config.SMTPPort = "587"
 	}
 	if config.RateLimitRPS == 0 {
 		config.RateLimitRPS = 10
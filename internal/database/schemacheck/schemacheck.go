@@ -0,0 +1,173 @@
+// Package schemacheck compares GORM entities with the live Postgres schema.
+//
+// Entities are written by hand next to SQL migrations, so nothing stops the
+// two from drifting apart: a field whose column does not exist fails every
+// query, and a column without a field silently never reaches the JSON.
+//
+// USAGE:
+//
+//	drifts, err := schemacheck.Verify(db, &entity.Bank{}, &entity.Branch{})
+//	for _, d := range drifts {
+//		fmt.Println(d)
+//	}
+package schemacheck
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Kind classifies a difference between an entity and its table.
+type Kind string
+
+const (
+	MissingTable  Kind = "missing table"  // No table for the entity
+	MissingColumn Kind = "missing column" // Field without a column
+	ExtraColumn   Kind = "extra column"   // Column without a field
+	TypeMismatch  Kind = "type mismatch"  // Field type cannot hold the column
+)
+
+// Drift is one difference between an entity and its table.
+type Drift struct {
+	Entity string // Go type, e.g. "entity.Bank"
+	Table  string
+	Column string // Empty for MissingTable
+	Kind   Kind
+	Field  string // Go type of the field, when there is one
+	Type   string // Postgres type of the column, when there is one
+}
+
+func (d Drift) String() string {
+	switch d.Kind {
+	case MissingTable:
+		return fmt.Sprintf("%s: %s (%s)", d.Table, d.Kind, d.Entity)
+	case MissingColumn:
+		return fmt.Sprintf("%s.%s: %s (%s field %s)", d.Table, d.Column, d.Kind, d.Entity, d.Field)
+	case ExtraColumn:
+		return fmt.Sprintf("%s.%s: %s %s (not mapped by %s)", d.Table, d.Column, d.Kind, d.Type, d.Entity)
+	default:
+		return fmt.Sprintf("%s.%s: %s (%s field %s, column %s)", d.Table, d.Column, d.Kind, d.Entity, d.Field, d.Type)
+	}
+}
+
+type column struct {
+	ColumnName string
+	UDTName    string `gorm:"column:udt_name"`
+	DataType   string
+}
+
+// Verify reflects every model and compares its fields with the columns of
+// its table in the current schema.
+func Verify(db *gorm.DB, models ...any) ([]Drift, error) {
+	cache := &sync.Map{}
+	var drifts []Drift
+	for _, model := range models {
+		s, err := schema.Parse(model, cache, db.NamingStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %T: %w", model, err)
+		}
+
+		var columns []column
+		if err := db.Raw(`SELECT column_name, udt_name, data_type FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? ORDER BY ordinal_position`, s.Table).
+			Scan(&columns).Error; err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", s.Table, err)
+		}
+
+		name := s.ModelType.String()
+		if len(columns) == 0 {
+			drifts = append(drifts, Drift{Entity: name, Table: s.Table, Kind: MissingTable})
+			continue
+		}
+
+		byName := make(map[string]column, len(columns))
+		for _, col := range columns {
+			byName[col.ColumnName] = col
+		}
+
+		for _, dbName := range s.DBNames {
+			field := s.FieldsByDBName[dbName]
+			col, ok := byName[dbName]
+			if !ok {
+				drifts = append(drifts, Drift{Entity: name, Table: s.Table, Column: dbName, Kind: MissingColumn, Field: field.FieldType.String()})
+				continue
+			}
+			if !compatible(field, col) {
+				drifts = append(drifts, Drift{Entity: name, Table: s.Table, Column: dbName, Kind: TypeMismatch, Field: field.FieldType.String(), Type: col.UDTName})
+			}
+		}
+
+		for _, col := range columns {
+			if _, ok := s.FieldsByDBName[col.ColumnName]; !ok {
+				drifts = append(drifts, Drift{Entity: name, Table: s.Table, Column: col.ColumnName, Kind: ExtraColumn, Type: col.UDTName})
+			}
+		}
+	}
+	return drifts, nil
+}
+
+// columnTypes lists the Postgres types (udt_name) each GORM data type can
+// be scanned from and written to.
+var columnTypes = map[schema.DataType][]string{
+	schema.Bool:   {"bool"},
+	schema.Int:    {"int2", "int4", "int8"},
+	schema.Uint:   {"int2", "int4", "int8"},
+	schema.Float:  {"numeric", "float4", "float8"},
+	schema.String: {"varchar", "bpchar", "text", "citext", "uuid", "inet"},
+	schema.Time:   {"timestamptz", "timestamp", "date"},
+	schema.Bytes:  {"bytea", "json", "jsonb"},
+}
+
+// typeAliases maps SQL type names used in gorm type: tags to udt_name.
+var typeAliases = map[string]string{
+	"boolean":                     "bool",
+	"smallint":                    "int2",
+	"integer":                     "int4",
+	"int":                         "int4",
+	"bigint":                      "int8",
+	"decimal":                     "numeric",
+	"real":                        "float4",
+	"double precision":            "float8",
+	"character varying":           "varchar",
+	"char":                        "bpchar",
+	"character":                   "bpchar",
+	"timestamp with time zone":    "timestamptz",
+	"timestamp without time zone": "timestamp",
+}
+
+func compatible(field *schema.Field, col column) bool {
+	// An explicit type: tag must name the column type exactly.
+	if tag := field.TagSettings["TYPE"]; tag != "" {
+		return normalize(tag) == col.UDTName
+	}
+
+	allowed, ok := columnTypes[field.DataType]
+	if !ok {
+		// Custom types (gorm:"type" via GormDataType) name their SQL type.
+		return normalize(string(field.DataType)) == col.UDTName
+	}
+	for _, t := range allowed {
+		if t == col.UDTName {
+			return true
+		}
+	}
+	// Postgres enums hold text.
+	return field.DataType == schema.String && col.DataType == "USER-DEFINED"
+}
+
+// normalize lowercases a SQL type and drops its length or precision, so
+// "VARCHAR(50)" and "numeric(18,2)" compare as varchar and numeric.
+func normalize(sqlType string) string {
+	t := strings.ToLower(strings.TrimSpace(sqlType))
+	if i := strings.IndexByte(t, '('); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+	if alias, ok := typeAliases[t]; ok {
+		return alias
+	}
+	return t
+}
@@ -2,26 +2,57 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/user/go-boilerplate/internal/shared/impersonation"
 	"github.com/user/go-boilerplate/pkg/apperror"
 	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/token"
+	"go.uber.org/zap"
 )
 
-// JWTClaims represents the JWT claims structure
-type JWTClaims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	jwt.RegisteredClaims
+// RevocationChecker reports whether a token has been revoked server-side,
+// either individually (by jti) or through its refresh token family.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, tokenID, familyID string) (bool, error)
+}
+
+// SessionValidator reports whether the login session behind a token is
+// still active. It is expected to answer from a fast cache.
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, sessionID, userID string) (bool, error)
+}
+
+// APIKeyPrincipal is the service account behind a valid API key.
+type APIKeyPrincipal struct {
+	ServiceAccountID string
+	KeyID            string
+	Scopes           []string
+}
+
+// APIKeyAuthenticator resolves an X-API-Key header value. It returns nil
+// for keys that are unknown, revoked, expired or belong to an inactive account.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*APIKeyPrincipal, error)
 }
 
 // JWTConfig holds JWT middleware configuration
 type JWTConfig struct {
-	Secret    string
-	SkipPaths []string
+	Keys        *token.KeySet
+	SkipPaths   []string
+	Revocations RevocationChecker   // Optional: rejects revoked tokens when set
+	Sessions    SessionValidator    // Optional: rejects tokens whose session has ended
+	Permissions PermissionResolver  // Optional: loads the caller's permissions into the context
+	APIKeys     APIKeyAuthenticator // Optional: accepts X-API-Key when no bearer token is sent
+	// PasswordChangePaths are the only paths open to tokens issued while a
+	// password change is pending (expired or admin-set password).
+	PasswordChangePaths []string
+	// ImpersonationPaths are the non-read paths open to read-only
+	// impersonation tokens, such as ending the impersonation.
+	ImpersonationPaths []string
 }
 
 // JWT returns a JWT authentication middleware
@@ -38,6 +69,12 @@ func JWT(config JWTConfig) gin.HandlerFunc {
 
 		// Extract token from Authorization header
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && config.APIKeys != nil {
+			if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+				authenticateAPIKey(c, config.APIKeys, apiKey)
+				return
+			}
+		}
 		if authHeader == "" {
 			respondError(c, apperror.Unauthorized("Missing authorization header"))
 			return
@@ -51,16 +88,10 @@ func JWT(config JWTConfig) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		// Parse and validate token
-		token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (any, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, apperror.Unauthorized("Invalid signing method")
-			}
-			return []byte(config.Secret), nil
-		})
-
+		// Parse and validate token (signature by kid, expiry, issuer, audience)
+		claims, err := config.Keys.Parse(tokenString)
 		if err != nil {
-			if strings.Contains(err.Error(), "expired") {
+			if errors.Is(err, token.ErrExpired) {
 				respondError(c, apperror.New(apperror.ErrCodeTokenExpired, "Token has expired", http.StatusUnauthorized))
 				return
 			}
@@ -68,22 +99,136 @@ func JWT(config JWTConfig) gin.HandlerFunc {
 			return
 		}
 
-		claims, ok := token.Claims.(*JWTClaims)
-		if !ok || !token.Valid {
-			respondError(c, apperror.Unauthorized("Invalid token claims"))
+		// Only access tokens authenticate requests; refresh and MFA challenge
+		// tokens are accepted solely by their dedicated /auth endpoints
+		if claims.TokenType != "" && claims.TokenType != token.TypeAccess {
+			respondError(c, apperror.New(apperror.ErrCodeInvalidToken, "Invalid token", http.StatusUnauthorized))
 			return
 		}
 
+		if claims.PasswordChange && !containsPath(config.PasswordChangePaths, path) {
+			respondError(c, apperror.New(apperror.ErrCodePasswordChangeRequired, "Password change required", http.StatusForbidden))
+			return
+		}
+
+		if claims.Actor != nil && !claims.Actor.Write && !readOnlyMethod(c.Request.Method) && !containsPath(config.ImpersonationPaths, path) {
+			respondError(c, apperror.New(apperror.ErrCodeImpersonationReadOnly, "Changes are not allowed while impersonating", http.StatusForbidden))
+			return
+		}
+
+		if config.Revocations != nil {
+			revoked, err := config.Revocations.IsRevoked(c.Request.Context(), claims.ID, claims.FamilyID)
+			if err != nil {
+				logger.Error(c.Request.Context(), "Token revocation check failed", zap.Error(err))
+				respondError(c, apperror.New(apperror.ErrCodeServiceUnavailable, "Unable to verify token", http.StatusServiceUnavailable))
+				return
+			}
+			if revoked {
+				respondError(c, apperror.New(apperror.ErrCodeTokenRevoked, "Token has been revoked", http.StatusUnauthorized))
+				return
+			}
+		}
+
+		if config.Sessions != nil {
+			// An impersonation lives inside the administrator's own session
+			sessionOwner := claims.UserID
+			if claims.Actor != nil {
+				sessionOwner = claims.Actor.UserID
+			}
+			active, err := config.Sessions.ValidateSession(c.Request.Context(), claims.FamilyID, sessionOwner)
+			if err != nil {
+				logger.Error(c.Request.Context(), "Session check failed", zap.Error(err))
+				respondError(c, apperror.New(apperror.ErrCodeServiceUnavailable, "Unable to verify session", http.StatusServiceUnavailable))
+				return
+			}
+			if !active {
+				respondError(c, apperror.New(apperror.ErrCodeTokenRevoked, "Session has ended", http.StatusUnauthorized))
+				return
+			}
+		}
+
+		var permissions []string
+		if config.Permissions != nil && claims.RoleID != "" {
+			permissions, err = config.Permissions.ResolvePermissions(c.Request.Context(), claims.RoleID, claims.SubRoleID)
+			if err != nil {
+				logger.Error(c.Request.Context(), "Permission resolution failed", zap.Error(err))
+				respondError(c, apperror.New(apperror.ErrCodeServiceUnavailable, "Unable to resolve permissions", http.StatusServiceUnavailable))
+				return
+			}
+		}
+
 		// Add user info to context
 		ctx := context.WithValue(c.Request.Context(), logger.UserIDKey, claims.UserID)
+		if claims.Actor != nil {
+			ctx = context.WithValue(ctx, logger.ActorIDKey, claims.Actor.UserID)
+			ctx = impersonation.WithSession(ctx, &impersonation.Session{
+				ID:        claims.ID,
+				ActorID:   claims.Actor.UserID,
+				SubjectID: claims.UserID,
+			})
+			c.Set("actor_id", claims.Actor.UserID)
+			c.Set("actor_email", claims.Actor.Email)
+		}
 		c.Request = c.Request.WithContext(ctx)
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
+		c.Set("role_id", claims.RoleID)
+		c.Set("sub_role_id", claims.SubRoleID)
+		c.Set("branch_id", claims.BranchID)
+		c.Set("permissions", permissions)
+		c.Set("token_id", claims.ID)
+		c.Set("token_family", claims.FamilyID)
+		if claims.ExpiresAt != nil {
+			c.Set("token_expires_at", claims.ExpiresAt.Time)
+		}
 
 		c.Next()
 	}
 }
 
+// authenticateAPIKey authenticates a service account. Its key scopes become
+// the caller's permissions; user_id is the service account ID so audit
+// columns record which integration made the change.
+func authenticateAPIKey(c *gin.Context, authenticator APIKeyAuthenticator, apiKey string) {
+	principal, err := authenticator.AuthenticateAPIKey(c.Request.Context(), apiKey)
+	if err != nil {
+		logger.Error(c.Request.Context(), "API key check failed", zap.Error(err))
+		respondError(c, apperror.New(apperror.ErrCodeServiceUnavailable, "Unable to verify API key", http.StatusServiceUnavailable))
+		return
+	}
+	if principal == nil {
+		respondError(c, apperror.New(apperror.ErrCodeInvalidToken, "Invalid API key", http.StatusUnauthorized))
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), logger.UserIDKey, principal.ServiceAccountID)
+	c.Request = c.Request.WithContext(ctx)
+	c.Set("user_id", principal.ServiceAccountID)
+	c.Set("service_account_id", principal.ServiceAccountID)
+	c.Set("api_key_id", principal.KeyID)
+	c.Set("permissions", principal.Scopes)
+
+	c.Next()
+}
+
+// readOnlyMethod reports whether the HTTP method does not change state.
+func readOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func containsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
 func respondError(c *gin.Context, appErr *apperror.AppError) {
 	c.AbortWithStatusJSON(appErr.HTTPStatus, gin.H{
 		"error": gin.H{
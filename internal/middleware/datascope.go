@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/shared/datascope"
+	"github.com/user/go-boilerplate/internal/shared/permission"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DataScopeHeader requests the head-office override when set to "all".
+const DataScopeHeader = "X-Data-Scope"
+
+// DataScopeResolver computes the branches visible from the caller's branch
+// and records head-office overrides. Resolution is expected to be cached.
+type DataScopeResolver interface {
+	ResolveScope(ctx context.Context, branchID string, area bool) (*datascope.Scope, error)
+	RecordScopeOverride(ctx context.Context, userID, email, ipAddress, userAgent, target string)
+}
+
+// DataScope puts the caller's datascope.Scope into the request context so
+// queries on branch-scoped tables only see the caller's branches.
+// It must run after the JWT middleware.
+//
+// Callers holding data.scope.override may send "X-Data-Scope: all" to see
+// every branch; each such request is recorded.
+func DataScope(resolver DataScopeResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		branchID := c.GetString("branch_id")
+
+		var scope *datascope.Scope
+		if strings.EqualFold(c.GetHeader(DataScopeHeader), "all") {
+			// Service accounts have no branch and are never head office
+			if c.GetString("service_account_id") != "" || !HasPermission(c, permission.DataScopeOverride) {
+				respondError(c, apperror.Forbidden("You are not allowed to override the data scope"))
+				return
+			}
+			scope = &datascope.Scope{All: true, HomeBranchID: branchID, Override: true}
+			resolver.RecordScopeOverride(ctx, c.GetString("user_id"), c.GetString("email"), c.ClientIP(), c.Request.UserAgent(), c.Request.Method+" "+c.Request.URL.Path)
+		} else {
+			var err error
+			scope, err = resolver.ResolveScope(ctx, branchID, HasPermission(c, permission.DataScopeArea))
+			if err != nil {
+				logger.Error(ctx, "Data scope resolution failed", zap.Error(err))
+				respondError(c, apperror.New(apperror.ErrCodeServiceUnavailable, "Unable to resolve data scope", http.StatusServiceUnavailable))
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(datascope.WithScope(ctx, scope))
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/pkg/apperror"
+)
+
+// IsImpersonating reports whether the request is made by an administrator
+// acting as another user.
+func IsImpersonating(c *gin.Context) bool {
+	return c.GetString("actor_id") != ""
+}
+
+// NoImpersonation closes a route to impersonation tokens, even those that
+// allow writes. It guards the impersonated user's own credentials and
+// devices (password, two-factor, sessions). It must run after the JWT
+// middleware.
+func NoImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsImpersonating(c) {
+			respondError(c, apperror.Forbidden("Not available while impersonating"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/pkg/apperror"
+)
+
+// PermissionResolver maps a role/sub-role pair to its effective permission codes.
+// Implementations are expected to cache, as it is called on every authenticated request.
+type PermissionResolver interface {
+	ResolvePermissions(ctx context.Context, roleID, subRoleID string) ([]string, error)
+}
+
+// RequirePermission returns a guard that aborts with 403 unless the
+// authenticated caller holds the given permission.
+// It must run after the JWT middleware.
+func RequirePermission(code string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, code) {
+			respondError(c, apperror.Forbidden("You do not have permission to perform this action"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// HasPermission reports whether the authenticated caller holds the given permission.
+func HasPermission(c *gin.Context, code string) bool {
+	for _, p := range c.GetStringSlice("permissions") {
+		if p == code {
+			return true
+		}
+	}
+	return false
+}
@@ -1,28 +1,111 @@
 package dto
 
+import "time"
+
+// ClientInfo describes the HTTP client behind a request.
+// It is filled by handlers, never bound from the request body.
+type ClientInfo struct {
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
 // LoginRequest is the payload for user authentication.
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
+	ClientInfo
 }
 
 // RegisterRequest is the payload for new user registration.
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required"` // Checked against the password policy
 	FullName string `json:"full_name" validate:"required,min=2,max=100"`
+	ClientInfo
+}
+
+// RefreshRequest is the payload for rotating a refresh token.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+	ClientInfo
+}
+
+// LogoutRequest identifies the session being terminated.
+// The fields are filled from the authenticated access token.
+type LogoutRequest struct {
+	TokenID   string    `json:"-"`
+	FamilyID  string    `json:"-"`
+	ExpiresAt time.Time `json:"-"`
 }
 
 // AuthResponse is returned after successful authentication.
 type AuthResponse struct {
-	Token     string `json:"token"`
-	ExpiresAt int64  `json:"expires_at"`
+	Token            string `json:"token"`
+	TokenType        string `json:"token_type"`
+	ExpiresAt        int64  `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at"`
+
+	// PasswordChangeRequired means the access token only allows
+	// /auth/password/change until the password is changed and the tokens refreshed.
+	PasswordChangeRequired bool `json:"password_change_required,omitempty"`
 }
 
 // UserResponse represents user data in API responses.
 type UserResponse struct {
-	ID       string `json:"id"`
-	Email    string `json:"email"`
-	FullName string `json:"full_name"`
-	IsActive bool   `json:"is_active"`
+	ID          string   `json:"id"`
+	Email       string   `json:"email"`
+	FullName    string   `json:"full_name"`
+	IsActive    bool     `json:"is_active"`
+	RoleID      *string  `json:"role_id,omitempty"`
+	SubRoleID   *string  `json:"sub_role_id,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	MFAEnabled  bool     `json:"mfa_enabled"`
+
+	PasswordChangeRequired bool       `json:"password_change_required"`
+	PasswordExpiresAt      *time.Time `json:"password_expires_at,omitempty"`
+}
+
+// ForgotPasswordRequest is the payload for requesting a password reset email.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	ClientInfo
+}
+
+// ResetPasswordRequest is the payload for setting a new password with a reset token.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"` // Checked against the password policy
+	ClientInfo
+}
+
+// ChangePasswordRequest is the payload for changing the current user's password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,nefield=CurrentPassword"` // Checked against the password policy
+	SessionID       string `json:"-"`                                                        // Kept signed in
+	ClientInfo
+}
+
+// UnlockRequest is the payload for lifting a login lockout.
+type UnlockRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	ClientInfo
+}
+
+// LoginResponse is returned by POST /auth/login. It carries either a token
+// pair or, for users with two-factor authentication, an MFA challenge.
+type LoginResponse struct {
+	*AuthResponse
+	*MFAChallengeResponse
+}
+
+// MFAChallengeResponse asks the client to complete the second login step.
+// When MFASetupRequired is set the user's role forces 2FA and the user must
+// enroll through /auth/mfa/setup before a token pair is issued.
+type MFAChallengeResponse struct {
+	MFARequired      bool   `json:"mfa_required"`
+	MFASetupRequired bool   `json:"mfa_setup_required,omitempty"`
+	MFAToken         string `json:"mfa_token"`
+	MFAExpiresAt     int64  `json:"mfa_expires_at"`
 }
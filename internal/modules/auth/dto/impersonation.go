@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/user/go-boilerplate/internal/shared/query"
+)
+
+// ImpersonateRequest is the payload for acting as another user.
+type ImpersonateRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=255"` // e.g. the support ticket
+
+	// Filled from the administrator's access token
+	ActorID          string   `json:"-"`
+	ActorEmail       string   `json:"-"`
+	ActorRoleID      string   `json:"-"`
+	ActorSessionID   string   `json:"-"`
+	ActorPermissions []string `json:"-"`
+	ClientInfo
+}
+
+// StopImpersonationRequest identifies the impersonation being ended.
+// The fields are filled from the impersonation token.
+type StopImpersonationRequest struct {
+	ImpersonationID string    `json:"-"`
+	ActorID         string    `json:"-"`
+	SubjectID       string    `json:"-"`
+	SubjectEmail    string    `json:"-"`
+	ExpiresAt       time.Time `json:"-"`
+	ClientInfo
+}
+
+// ImpersonationQuery holds the filters for listing impersonations.
+type ImpersonationQuery struct {
+	ActorID   string `form:"actor_id"`
+	SubjectID string `form:"subject_id"`
+}
+
+// ImpersonationList exposes sys_user_impersonations columns to the list query grammar.
+var ImpersonationList = query.Allowlist{
+	Filter: map[string]query.Type{
+		"actor_id":    query.UUID,
+		"subject_id":  query.UUID,
+		"allow_write": query.Bool,
+		"expires_at":  query.Time,
+		"created_at":  query.Time,
+	},
+	Sort:   []string{"expires_at", "ended_at", "created_at"},
+	Search: []string{"reason"},
+	Fields: []string{"actor_id", "subject_id", "reason", "allow_write", "ip_address", "user_agent", "started_at", "expires_at", "ended_at", "active"},
+	Order:  "created_at DESC",
+}
+
+// ImpersonationTokenResponse is returned when an impersonation starts. The
+// token has no refresh token; a new impersonation is needed once it expires.
+type ImpersonationTokenResponse struct {
+	ImpersonationID string           `json:"impersonation_id"`
+	Token           string           `json:"token"`
+	TokenType       string           `json:"token_type"`
+	ExpiresAt       int64            `json:"expires_at"`
+	AllowWrite      bool             `json:"allow_write"`
+	Subject         ImpersonatedUser `json:"subject"`
+}
+
+// ImpersonatedUser identifies the user being impersonated.
+type ImpersonatedUser struct {
+	ID       string  `json:"id"`
+	Email    string  `json:"email"`
+	FullName string  `json:"full_name"`
+	BranchID *string `json:"branch_id,omitempty"`
+}
+
+// ImpersonationResponse represents an impersonation in the audit API.
+type ImpersonationResponse struct {
+	ID         string     `json:"id"`
+	ActorID    string     `json:"actor_id"`
+	SubjectID  string     `json:"subject_id"`
+	Reason     string     `json:"reason"`
+	AllowWrite bool       `json:"allow_write"`
+	IPAddress  string     `json:"ip_address"`
+	UserAgent  string     `json:"user_agent"`
+	StartedAt  time.Time  `json:"started_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	Active     bool       `json:"active"`
+
+	Writes []ImpersonationWriteResponse `json:"writes,omitempty"` // Only on GET /api/security/impersonations/:id
+}
+
+// ImpersonationWriteResponse is a row written during an impersonation.
+type ImpersonationWriteResponse struct {
+	Table        string    `json:"table"`
+	Operation    string    `json:"operation"`
+	RowID        *string   `json:"row_id,omitempty"`
+	RowsAffected int64     `json:"rows_affected"`
+	RequestID    string    `json:"request_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/user/go-boilerplate/internal/shared/query"
+)
+
+// InviteQuery holds the filters for listing invites.
+type InviteQuery struct {
+	Email  string `form:"email"`
+	Status string `form:"status" validate:"omitempty,oneof=pending accepted revoked expired"`
+}
+
+// InviteList exposes sys_user_invites columns to the list query grammar.
+var InviteList = query.Allowlist{
+	Filter: map[string]query.Type{
+		"email":       query.Text,
+		"role_id":     query.UUID,
+		"sub_role_id": query.UUID,
+		"branch_id":   query.UUID,
+		"created_by":  query.UUID,
+		"expires_at":  query.Time,
+		"created_at":  query.Time,
+	},
+	Sort:   []string{"email", "expires_at", "created_at"},
+	Search: []string{"email", "full_name"},
+	Fields: []string{
+		"email", "full_name", "role_id", "sub_role_id", "branch_id", "status", "expires_at",
+		"accepted_at", "accepted_user_id", "revoked_at", "created_by", "created_at",
+	},
+	Order: "created_at DESC, id ASC",
+}
+
+// CreateInviteRequest is the payload for inviting a user. The role,
+// sub-role and branch are assigned to the account when the invite is accepted.
+type CreateInviteRequest struct {
+	Email          string  `json:"email" validate:"required,email,max=255"`
+	FullName       *string `json:"full_name" validate:"omitempty,min=2,max=255"`
+	RoleID         *string `json:"role_id" validate:"omitempty,uuid"`
+	SubRoleID      *string `json:"sub_role_id" validate:"omitempty,uuid"`
+	BranchID       *string `json:"branch_id" validate:"omitempty,uuid"`
+	ExpiresInHours int     `json:"expires_in_hours" validate:"min=0,max=720"` // 0 = INVITE_EXPIRY_HOURS
+
+	ActorPermissions []string `json:"-"` // Set by the handler from the caller's token
+}
+
+// AcceptInviteRequest is the payload for redeeming an invite.
+type AcceptInviteRequest struct {
+	Token    string `json:"token" validate:"required"`
+	FullName string `json:"full_name" validate:"required,min=2,max=255"`
+	Password string `json:"password" validate:"required"` // Checked against the password policy
+	ClientInfo
+}
+
+// InviteResponse represents an invite in the administration API.
+type InviteResponse struct {
+	ID             string     `json:"id"`
+	Email          string     `json:"email"`
+	FullName       *string    `json:"full_name"`
+	RoleID         *string    `json:"role_id"`
+	SubRoleID      *string    `json:"sub_role_id"`
+	BranchID       *string    `json:"branch_id"`
+	Status         string     `json:"status"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	AcceptedUserID *string    `json:"accepted_user_id,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy      *string    `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// IssuedInviteResponse is returned once when an invite is created. The
+// token is also emailed to the invitee and cannot be retrieved again.
+type IssuedInviteResponse struct {
+	*InviteResponse
+	Token string `json:"token"`
+	URL   string `json:"url,omitempty"`
+}
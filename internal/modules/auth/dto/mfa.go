@@ -0,0 +1,53 @@
+package dto
+
+// MFAVerifyRequest completes a login with a TOTP code or a recovery code.
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+	ClientInfo
+}
+
+// MFASetupRequest starts a forced enrollment using the login challenge token.
+type MFASetupRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// MFASetupConfirmRequest finishes a forced enrollment and signs the user in.
+type MFASetupConfirmRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,len=6,numeric"`
+	ClientInfo
+}
+
+// MFACodeRequest carries a TOTP code from the authenticator app.
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+	ClientInfo
+}
+
+// MFADisableRequest turns off 2FA; it requires the password and a current code.
+type MFADisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,len=6,numeric"`
+	ClientInfo
+}
+
+// MFAEnrollmentResponse holds the provisioning data for an authenticator app.
+type MFAEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	QRCode          string `json:"qr_code"` // PNG data URL of the provisioning URI
+}
+
+// MFARecoveryCodesResponse returns freshly generated recovery codes.
+// They are shown once and cannot be retrieved again.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFASetupResponse is returned when a forced enrollment completes.
+type MFASetupResponse struct {
+	*AuthResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
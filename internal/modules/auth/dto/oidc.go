@@ -0,0 +1,20 @@
+package dto
+
+// OIDCLoginResponse starts a single sign-on login. The client sends the
+// user to AuthorizationURL; the identity provider returns to the configured
+// redirect URL with code and state.
+type OIDCLoginResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresAt        int64  `json:"expires_at"`
+}
+
+// OIDCCallbackRequest carries the identity provider's redirect parameters,
+// from the query string (GET) or a JSON body (POST).
+type OIDCCallbackRequest struct {
+	Code             string `form:"code" json:"code" validate:"required_without=Error"`
+	State            string `form:"state" json:"state" validate:"required"`
+	Error            string `form:"error" json:"error"`
+	ErrorDescription string `form:"error_description" json:"error_description"`
+	ClientInfo
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/user/go-boilerplate/internal/shared/query"
+)
+
+// SecurityEventQuery holds the filters for listing security events.
+type SecurityEventQuery struct {
+	EventType string     `form:"event_type"`
+	UserID    string     `form:"user_id"`
+	Email     string     `form:"email"`
+	IPAddress string     `form:"ip_address"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// SecurityEventList exposes sys_security_events columns to the list query grammar.
+var SecurityEventList = query.Allowlist{
+	Filter: map[string]query.Type{
+		"event_type": query.Text,
+		"user_id":    query.UUID,
+		"actor_id":   query.UUID,
+		"email":      query.Text,
+		"ip_address": query.Text,
+		"created_at": query.Time,
+	},
+	Sort:   []string{"event_type", "email", "created_at"},
+	Search: []string{"email", "reason"},
+	Fields: []string{"event_type", "reason", "user_id", "email", "actor_id", "ip_address", "user_agent", "request_id", "created_at"},
+	Order:  "created_at DESC",
+}
@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/user/go-boilerplate/internal/shared/query"
+)
+
+// ServiceAccountList exposes sys_service_accounts columns to the list query
+// grammar; q matches name or description.
+var ServiceAccountList = query.Allowlist{
+	Filter: map[string]query.Type{
+		"name":       query.Text,
+		"is_active":  query.Bool,
+		"created_at": query.Time,
+	},
+	Sort:   []string{"name", "created_at", "updated_at"},
+	Search: []string{"name", "description"},
+	Fields: []string{"name", "description", "is_active", "created_at", "updated_at", "created_by", "updated_by"},
+	Order:  "name ASC, id ASC",
+}
+
+// CreateServiceAccountRequest is the payload for creating a service account.
+type CreateServiceAccountRequest struct {
+	Name        string  `json:"name" validate:"required,min=2,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=255"`
+}
+
+// UpdateServiceAccountRequest is a partial update of a service account.
+type UpdateServiceAccountRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=2,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=255"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// CreateAPIKeyRequest is the payload for issuing an API key.
+// Scopes are permission codes; callers can only grant scopes they hold.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,min=2,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// RotateAPIKeyRequest is the payload for replacing an API key.
+// The old key keeps working for GraceMinutes (0 = revoked immediately).
+// The new key inherits the old key's lifetime unless ExpiresAt is given.
+type RotateAPIKeyRequest struct {
+	GraceMinutes int        `json:"grace_minutes" validate:"min=0,max=10080"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
+
+// StaleAPIKeyQuery selects keys unused for a number of days.
+type StaleAPIKeyQuery struct {
+	UnusedDays int `form:"unused_days"` // Default: 90
+}
+
+// StaleAPIKeyList exposes sys_api_keys columns to the list query grammar.
+var StaleAPIKeyList = query.Allowlist{
+	Filter: map[string]query.Type{
+		"service_account_id": query.UUID,
+		"expires_at":         query.Time,
+		"created_at":         query.Time,
+	},
+	Sort:   []string{"name", "last_used_at", "expires_at", "created_at"},
+	Search: []string{"name", "prefix"},
+	Fields: []string{"service_account_id", "name", "prefix", "scopes", "expires_at", "last_used_at", "created_by", "created_at"},
+	Order:  "COALESCE(last_used_at, created_at) ASC, id ASC",
+}
+
+// ServiceAccountResponse represents a service account in the administration API.
+type ServiceAccountResponse struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description *string           `json:"description"`
+	IsActive    bool              `json:"is_active"`
+	Keys        []*APIKeyResponse `json:"keys,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CreatedBy   *string           `json:"created_by"`
+	UpdatedBy   *string           `json:"updated_by"`
+}
+
+// APIKeyResponse describes an API key without its secret.
+type APIKeyResponse struct {
+	ID               string     `json:"id"`
+	ServiceAccountID string     `json:"service_account_id"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	Scopes           []string   `json:"scopes"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	LastUsedAt       *time.Time `json:"last_used_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RotatedFrom      *string    `json:"rotated_from,omitempty"`
+	CreatedBy        *string    `json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// IssuedAPIKeyResponse is returned once when a key is created or rotated.
+// The plaintext key cannot be retrieved again.
+type IssuedAPIKeyResponse struct {
+	*APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// SessionResponse describes a signed-in device.
+type SessionResponse struct {
+	ID         string     `json:"id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	IssuedAt   time.Time  `json:"issued_at"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Current    bool       `json:"current"`
+}
+
+// RevokeSessionsResponse reports how many sessions were ended.
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
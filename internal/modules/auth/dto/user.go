@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/user/go-boilerplate/internal/shared/query"
+)
+
+// UserQuery holds the filters for searching users.
+type UserQuery struct {
+	RoleID     string `form:"role_id"`
+	SubRoleID  string `form:"sub_role_id"`
+	BranchID   string `form:"branch_id"`
+	DivisionID string `form:"division_id"`
+	IsActive   *bool  `form:"is_active"`
+}
+
+// UserList exposes sys_users columns to the list query grammar; q matches
+// email, full name or employee number.
+var UserList = query.Allowlist{
+	Filter: map[string]query.Type{
+		"email":                query.Text,
+		"full_name":            query.Text,
+		"employee_number":      query.Text,
+		"branch_id":            query.UUID,
+		"division_id":          query.UUID,
+		"role_id":              query.UUID,
+		"sub_role_id":          query.UUID,
+		"is_active":            query.Bool,
+		"mfa_enabled":          query.Bool,
+		"must_change_password": query.Bool,
+		"password_changed_at":  query.Time,
+		"created_at":           query.Time,
+	},
+	Sort:   []string{"email", "full_name", "employee_number", "password_changed_at", "created_at", "updated_at"},
+	Search: []string{"email", "full_name", "employee_number"},
+	Fields: []string{
+		"email", "full_name", "employee_number", "branch_id", "division_id", "division_name",
+		"role_id", "sub_role_id", "is_active", "mfa_enabled", "must_change_password",
+		"password_changed_at", "created_at", "updated_at", "created_by", "updated_by",
+	},
+	Order: "full_name ASC, id ASC",
+}
+
+// CreateUserRequest is the payload for creating a staff account.
+type CreateUserRequest struct {
+	Email          string  `json:"email" validate:"required,email,max=255"`
+	Password       string  `json:"password" validate:"required"` // Checked against the password policy
+	FullName       string  `json:"full_name" validate:"required,min=2,max=255"`
+	EmployeeNumber *string `json:"employee_number" validate:"omitempty,max=100"`
+	BranchID       *string `json:"branch_id" validate:"omitempty,uuid"`
+	DivisionID     *string `json:"division_id" validate:"omitempty,uuid"`
+	DivisionName   *string `json:"division_name" validate:"omitempty,max=100"`
+	RoleID         *string `json:"role_id" validate:"omitempty,uuid"`
+	SubRoleID      *string `json:"sub_role_id" validate:"omitempty,uuid"`
+	IsActive       *bool   `json:"is_active"`
+	// MustChangePassword forces a change at first login (default: true).
+	MustChangePassword *bool `json:"must_change_password"`
+
+	ActorPermissions []string `json:"-"` // Set by the handler from the caller's token
+}
+
+// UpdateUserRequest is a partial update of a staff account.
+// Omitted fields are left unchanged; an empty string clears an optional
+// assignment (e.g. "branch_id": "").
+type UpdateUserRequest struct {
+	Email          *string `json:"email" validate:"omitempty,email,max=255"`
+	FullName       *string `json:"full_name" validate:"omitempty,min=2,max=255"`
+	EmployeeNumber *string `json:"employee_number" validate:"omitempty,max=100"`
+	BranchID       *string `json:"branch_id" validate:"omitempty,uuid|len=0"`
+	DivisionID     *string `json:"division_id" validate:"omitempty,uuid|len=0"`
+	DivisionName   *string `json:"division_name" validate:"omitempty,max=100"`
+	RoleID         *string `json:"role_id" validate:"omitempty,uuid|len=0"`
+	SubRoleID      *string `json:"sub_role_id" validate:"omitempty,uuid|len=0"`
+
+	ActorPermissions []string `json:"-"` // Set by the handler from the caller's token
+}
+
+// SetUserStatusRequest activates or deactivates a user.
+type SetUserStatusRequest struct {
+	IsActive *bool `json:"is_active" validate:"required"`
+
+	ActorPermissions []string `json:"-"` // Set by the handler from the caller's token
+}
+
+// AdminUserResponse represents a user in the administration API.
+type AdminUserResponse struct {
+	ID                 string     `json:"id"`
+	Email              string     `json:"email"`
+	FullName           string     `json:"full_name"`
+	EmployeeNumber     *string    `json:"employee_number"`
+	BranchID           *string    `json:"branch_id"`
+	DivisionID         *string    `json:"division_id"`
+	DivisionName       *string    `json:"division_name"`
+	RoleID             *string    `json:"role_id"`
+	SubRoleID          *string    `json:"sub_role_id"`
+	IsActive           bool       `json:"is_active"`
+	MFAEnabled         bool       `json:"mfa_enabled"`
+	MustChangePassword bool       `json:"must_change_password"`
+	PasswordChangedAt  *time.Time `json:"password_changed_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	CreatedBy          *string    `json:"created_by"`
+	UpdatedBy          *string    `json:"updated_by"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+package auth
+
+import "github.com/user/go-boilerplate/internal/modules/auth/entity"
+
+// Entities lists the models of the auth tables, checked against the database
+// by cli schema:verify.
+var Entities = []any{
+	&entity.User{},
+	&entity.Session{},
+	&entity.UserIdentity{},
+	&entity.PasswordHistory{},
+	&entity.RecoveryCode{},
+	&entity.SecurityEvent{},
+	&entity.Invite{},
+	&entity.ServiceAccount{},
+	&entity.APIKey{},
+	&entity.Impersonation{},
+	&entity.ImpersonationWrite{},
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// UserIdentity links a user to an account at an external identity provider.
+type UserIdentity struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      string     `json:"user_id" gorm:"type:uuid"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       *string    `json:"email,omitempty"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (UserIdentity) TableName() string {
+	return "sys_user_identities"
+}
@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// Impersonation is an administrator acting as another user. Its ID is the
+// jti of the impersonation token.
+type Impersonation struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:uuid"`
+	ActorID    string     `json:"actor_id" gorm:"type:uuid"`
+	SubjectID  string     `json:"subject_id" gorm:"type:uuid"`
+	SessionID  *string    `json:"session_id,omitempty" gorm:"type:uuid"`
+	Reason     string     `json:"reason"`
+	AllowWrite bool       `json:"allow_write"`
+	IPAddress  string     `json:"ip_address"`
+	UserAgent  string     `json:"user_agent"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (Impersonation) TableName() string {
+	return "sys_user_impersonations"
+}
+
+// ImpersonationWrite is a journaled row written during an impersonation.
+// Rows are inserted by the impersonation GORM plugin.
+type ImpersonationWrite struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ImpersonationID string    `json:"impersonation_id" gorm:"type:uuid"`
+	ActorID         string    `json:"actor_id" gorm:"type:uuid"`
+	SubjectID       string    `json:"subject_id" gorm:"type:uuid"`
+	TargetTable     string    `json:"target_table"`
+	Operation       string    `json:"operation"`
+	RowID           *string   `json:"row_id,omitempty"`
+	RowsAffected    int64     `json:"rows_affected"`
+	RequestID       string    `json:"request_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (ImpersonationWrite) TableName() string {
+	return "sys_impersonation_writes"
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// Invite is an admin-issued invitation to create an account with a
+// preassigned role, sub-role and branch.
+type Invite struct {
+	ID             string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Email          string     `json:"email"`
+	FullName       *string    `json:"full_name,omitempty"`
+	RoleID         *string    `json:"role_id,omitempty" gorm:"type:uuid"`
+	SubRoleID      *string    `json:"sub_role_id,omitempty" gorm:"type:uuid"`
+	BranchID       *string    `json:"branch_id,omitempty" gorm:"type:uuid"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	AcceptedUserID *string    `json:"accepted_user_id,omitempty" gorm:"type:uuid"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy      *string    `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (Invite) TableName() string {
+	return "sys_user_invites"
+}
+
+// Invite statuses derived from the timestamps.
+const (
+	InviteStatusPending  = "pending"
+	InviteStatusAccepted = "accepted"
+	InviteStatusRevoked  = "revoked"
+	InviteStatusExpired  = "expired"
+)
+
+// Status returns the invite's state at now.
+func (i *Invite) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InviteStatusAccepted
+	case i.RevokedAt != nil:
+		return InviteStatusRevoked
+	case !i.ExpiresAt.After(now):
+		return InviteStatusExpired
+	default:
+		return InviteStatusPending
+	}
+}
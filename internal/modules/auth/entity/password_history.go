@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// PasswordHistory is a previously used password hash.
+type PasswordHistory struct {
+	ID           string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID       string    `json:"user_id" gorm:"type:uuid"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (PasswordHistory) TableName() string {
+	return "sys_user_password_history"
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// RecoveryCode is a single-use two-factor recovery code. Only its hash is stored.
+type RecoveryCode struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string     `json:"user_id" gorm:"type:uuid"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (RecoveryCode) TableName() string {
+	return "sys_user_recovery_codes"
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// Security event types recorded in sys_security_events.
+const (
+	SecurityEventLoginSuccess    = "login_success"
+	SecurityEventLoginFailure    = "login_failure"
+	SecurityEventAccountLocked   = "account_locked"
+	SecurityEventAccountUnlocked = "account_unlocked"
+
+	SecurityEventPasswordResetRequested = "password_reset_requested"
+	SecurityEventPasswordReset          = "password_reset"
+	SecurityEventPasswordChanged        = "password_changed"
+
+	SecurityEventMFAEnabled         = "mfa_enabled"
+	SecurityEventMFADisabled        = "mfa_disabled"
+	SecurityEventMFAChallengeFailed = "mfa_challenge_failed"
+	SecurityEventRecoveryCodeUsed   = "recovery_code_used"
+	SecurityEventRecoveryCodesReset = "recovery_codes_regenerated"
+
+	SecurityEventUserRegistered = "user_registered"
+	SecurityEventInviteAccepted = "invite_accepted"
+
+	SecurityEventSessionRevoked  = "session_revoked"
+	SecurityEventSessionsRevoked = "sessions_revoked"
+
+	SecurityEventDataScopeOverride = "data_scope_override"
+
+	SecurityEventImpersonationStarted = "impersonation_started"
+	SecurityEventImpersonationStopped = "impersonation_stopped"
+)
+
+// SecurityEvent is an append-only audit record of an authentication event.
+type SecurityEvent struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	EventType string    `json:"event_type"`
+	Reason    *string   `json:"reason,omitempty"`
+	UserID    *string   `json:"user_id,omitempty" gorm:"type:uuid"`
+	Email     *string   `json:"email,omitempty"`
+	ActorID   *string   `json:"actor_id,omitempty" gorm:"type:uuid"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	RequestID string    `json:"request_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (SecurityEvent) TableName() string {
+	return "sys_security_events"
+}
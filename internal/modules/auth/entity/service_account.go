@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+// ServiceAccount is a non-human API caller authenticating with API keys.
+type ServiceAccount struct {
+	sharedentity.Base
+	Name        string  `json:"name" gorm:"not null"`
+	Description *string `json:"description,omitempty"`
+	IsActive    bool    `json:"is_active" gorm:"default:true"`
+}
+
+// TableName returns the database table name.
+func (ServiceAccount) TableName() string {
+	return "sys_service_accounts"
+}
+
+// APIKey is a credential of a service account. Only its hash is stored.
+type APIKey struct {
+	ID               string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ServiceAccountID string     `json:"service_account_id" gorm:"type:uuid"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	KeyHash          string     `json:"-"`
+	Scopes           string     `json:"-"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RotatedFrom      *string    `json:"rotated_from,omitempty" gorm:"type:uuid"`
+	CreatedBy        *string    `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (APIKey) TableName() string {
+	return "sys_api_keys"
+}
+
+// ScopeList returns the key's permission codes.
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// Usable reports whether the key is neither revoked nor expired.
+func (k *APIKey) Usable(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
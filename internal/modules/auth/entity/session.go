@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Session reasons stored in revoked_reason.
+const (
+	SessionRevokedLogout       = "logout"
+	SessionRevokedByUser       = "revoked"
+	SessionRevokedSignOutAll   = "sign_out_all"
+	SessionRevokedLimit        = "session_limit"
+	SessionRevokedRefreshReuse = "refresh_reuse"
+	// The account was deactivated, deleted or given another role or branch
+	SessionRevokedAccountChanged = "account_changed"
+	// The password was reset or changed
+	SessionRevokedPasswordChanged = "password_changed"
+)
+
+// Session is a signed-in device. Its ID is the token family shared by every
+// access and refresh token issued from one login.
+type Session struct {
+	ID            string     `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID        string     `json:"user_id" gorm:"type:uuid"`
+	TokenID       string     `json:"-"`
+	UserAgent     string     `json:"user_agent"`
+	IPAddress     string     `json:"ip_address"`
+	LastSeenAt    *time.Time `json:"last_seen_at,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason *string    `json:"revoked_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName returns the database table name.
+func (Session) TableName() string {
+	return "sys_user_sessions"
+}
@@ -1,9 +1,17 @@
 package entity
 
 import (
+	"time"
+
 	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
 )
 
+// Credential sources stored in credential_source.
+const (
+	CredentialSourceLocal = "local" // bcrypt hash in sys_users
+	CredentialSourceLDAP  = "ldap"  // Directory bind; no local password
+)
+
 // User represents a system user for authentication.
 type User struct {
 	sharedentity.Base
@@ -18,7 +26,19 @@ type User struct {
 	SubRoleID      *string `json:"sub_role_id,omitempty" gorm:"type:uuid"`
 	ProfileID      *string `json:"profile_id,omitempty"`
 	CompanyProfID  *string `json:"company_profile_id,omitempty" gorm:"column:company_profile_id"`
-	IsActive       bool    `json:"is_active" gorm:"default:true"`
+	IsActive       bool    `json:"is_active"` // Always written, so inactive accounts can be created
+
+	// CredentialSource is the backend owning the password
+	CredentialSource string `json:"credential_source" gorm:"default:local"`
+
+	// Password age
+	PasswordChangedAt  *time.Time `json:"password_changed_at,omitempty"`
+	MustChangePassword bool       `json:"must_change_password"`
+
+	// Two-factor authentication
+	MFAEnabled     bool       `json:"mfa_enabled" gorm:"column:mfa_enabled"`
+	MFASecret      *string    `json:"-" gorm:"column:mfa_secret"`
+	MFAConfirmedAt *time.Time `json:"mfa_confirmed_at,omitempty" gorm:"column:mfa_confirmed_at"`
 }
 
 // TableName returns the database table name.
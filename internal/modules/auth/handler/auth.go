@@ -36,6 +36,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	req.ClientInfo = clientInfo(c)
+
 	resp, err := h.service.Login(c.Request.Context(), &req)
 	if err != nil {
 		if appErr, ok := err.(*apperror.AppError); ok {
@@ -47,12 +49,17 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if resp.MFAChallengeResponse != nil {
+		response.Success(c, http.StatusOK, "Two-factor authentication required", resp)
+		return
+	}
+
 	response.Success(c, http.StatusOK, "Login successful", resp)
 }
 
-// Register handles POST /auth/register requests.
-func (h *AuthHandler) Register(c *gin.Context) {
-	var req dto.RegisterRequest
+// Refresh handles POST /auth/refresh requests.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		respondError(c, apperror.BadRequest("Invalid request body"))
 		return
@@ -63,18 +70,41 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.Register(c.Request.Context(), &req)
+	req.ClientInfo = clientInfo(c)
+
+	resp, err := h.service.Refresh(c.Request.Context(), &req)
 	if err != nil {
 		if appErr, ok := err.(*apperror.AppError); ok {
 			respondError(c, appErr)
 			return
 		}
-		logger.Error(c.Request.Context(), "Registration failed", zap.Error(err))
-		respondError(c, apperror.Internal("Registration failed"))
+		logger.Error(c.Request.Context(), "Token refresh failed", zap.Error(err))
+		respondError(c, apperror.Internal("Token refresh failed"))
 		return
 	}
 
-	response.Success(c, http.StatusCreated, "User registered successfully", resp)
+	response.Success(c, http.StatusOK, "Token refreshed", resp)
+}
+
+// Logout handles POST /auth/logout requests.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	req := dto.LogoutRequest{
+		TokenID:   c.GetString("token_id"),
+		FamilyID:  c.GetString("token_family"),
+		ExpiresAt: c.GetTime("token_expires_at"),
+	}
+
+	if err := h.service.Logout(c.Request.Context(), &req); err != nil {
+		if appErr, ok := err.(*apperror.AppError); ok {
+			respondError(c, appErr)
+			return
+		}
+		logger.Error(c.Request.Context(), "Logout failed", zap.Error(err))
+		respondError(c, apperror.Internal("Logout failed"))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Logout successful", nil)
 }
 
 // GetMe handles GET /auth/me requests.
@@ -102,3 +132,10 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 func respondError(c *gin.Context, appErr *apperror.AppError) {
 	response.Error(c, appErr.HTTPStatus, string(appErr.Code), appErr.Message, appErr.Details)
 }
+
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
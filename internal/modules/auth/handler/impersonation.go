@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/service"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+)
+
+// ImpersonationHandler handles HTTP requests for admin impersonation.
+type ImpersonationHandler struct {
+	service service.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new impersonation handler.
+func NewImpersonationHandler(svc service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{service: svc}
+}
+
+// Start handles POST /api/system/users/:id/impersonate requests.
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+	var req dto.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ActorID = c.GetString("user_id")
+	req.ActorEmail = c.GetString("email")
+	req.ActorRoleID = c.GetString("role_id")
+	req.ActorSessionID = c.GetString("token_family")
+	req.ActorPermissions = c.GetStringSlice("permissions")
+	req.ClientInfo = clientInfo(c)
+
+	resp, err := h.service.Start(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to start impersonation")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Impersonation started", resp)
+}
+
+// Stop handles POST /auth/impersonation/stop requests made with the
+// impersonation token.
+func (h *ImpersonationHandler) Stop(c *gin.Context) {
+	req := dto.StopImpersonationRequest{
+		ImpersonationID: c.GetString("token_id"),
+		ActorID:         c.GetString("actor_id"),
+		SubjectID:       c.GetString("user_id"),
+		SubjectEmail:    c.GetString("email"),
+		ExpiresAt:       c.GetTime("token_expires_at"),
+		ClientInfo:      clientInfo(c),
+	}
+	if req.ActorID == "" {
+		respondError(c, apperror.BadRequest("Not impersonating"))
+		return
+	}
+
+	if err := h.service.Stop(c.Request.Context(), &req); err != nil {
+		handleServiceError(c, err, "Failed to stop impersonation")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Impersonation stopped", nil)
+}
+
+// List handles GET /api/security/impersonations requests.
+// Optional filters: actor_id, subject_id, plus the list query grammar.
+func (h *ImpersonationHandler) List(c *gin.Context) {
+	params, appErr := query.Parse(c, dto.ImpersonationList)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var query dto.ImpersonationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, apperror.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	impersonations, total, err := h.service.List(c.Request.Context(), &query, params)
+	if err != nil {
+		handleServiceError(c, err, "Failed to list impersonations")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, impersonations, total, params)
+}
+
+// Get handles GET /api/security/impersonations/:id requests, including the
+// rows written during the impersonation.
+func (h *ImpersonationHandler) Get(c *gin.Context) {
+	impersonation, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleServiceError(c, err, "Failed to get impersonation")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Success", impersonation)
+}
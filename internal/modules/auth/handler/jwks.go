@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/pkg/token"
+)
+
+// JWKSHandler publishes the public token verification keys.
+type JWKSHandler struct {
+	keys *token.KeySet
+}
+
+// NewJWKSHandler creates a new JWKS handler.
+func NewJWKSHandler(keys *token.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// JWKS handles GET /.well-known/jwks.json requests.
+// The body is a bare RFC 7517 key set, not wrapped in the API envelope,
+// so standard JWT libraries can consume it directly.
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// VerifyMFA handles POST /auth/login/mfa requests.
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req dto.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	resp, err := h.service.VerifyMFA(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err, "Two-factor verification failed")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Login successful", resp)
+}
+
+// SetupMFA handles POST /auth/mfa/setup requests (forced enrollment).
+func (h *AuthHandler) SetupMFA(c *gin.Context) {
+	var req dto.MFASetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	resp, err := h.service.SetupMFA(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err, "Two-factor setup failed")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Scan the QR code with your authenticator app", resp)
+}
+
+// ConfirmMFASetup handles POST /auth/mfa/setup/confirm requests (forced enrollment).
+func (h *AuthHandler) ConfirmMFASetup(c *gin.Context) {
+	var req dto.MFASetupConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	resp, err := h.service.ConfirmMFASetup(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err, "Two-factor setup failed")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor authentication enabled", resp)
+}
+
+// BeginMFAEnrollment handles POST /auth/mfa/enroll requests.
+func (h *AuthHandler) BeginMFAEnrollment(c *gin.Context) {
+	resp, err := h.service.BeginMFAEnrollment(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		handleServiceError(c, err, "Two-factor enrollment failed")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Scan the QR code with your authenticator app", resp)
+}
+
+// ConfirmMFAEnrollment handles POST /auth/mfa/confirm requests.
+func (h *AuthHandler) ConfirmMFAEnrollment(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	resp, err := h.service.ConfirmMFAEnrollment(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Two-factor enrollment failed")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor authentication enabled", resp)
+}
+
+// DisableMFA handles POST /auth/mfa/disable requests.
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	var req dto.MFADisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	if err := h.service.DisableMFA(c.Request.Context(), c.GetString("user_id"), &req); err != nil {
+		handleServiceError(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes handles POST /auth/mfa/recovery-codes requests.
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	resp, err := h.service.RegenerateRecoveryCodes(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to regenerate recovery codes")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Recovery codes regenerated", resp)
+}
+
+// handleServiceError responds with the service's AppError, or logs and
+// responds with a generic internal error.
+func handleServiceError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*apperror.AppError); ok {
+		respondError(c, appErr)
+		return
+	}
+	logger.Error(c.Request.Context(), message, zap.Error(err))
+	respondError(c, apperror.Internal(message))
+}
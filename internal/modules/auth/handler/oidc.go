@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/service"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+)
+
+// OIDCHandler handles HTTP requests for OpenID Connect single sign-on.
+type OIDCHandler struct {
+	service service.OIDCService
+}
+
+// NewOIDCHandler creates a new OIDC handler.
+func NewOIDCHandler(svc service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{service: svc}
+}
+
+// Login handles GET /auth/oidc/login requests.
+// It returns the authorization URL, or redirects to it with ?redirect=true.
+func (h *OIDCHandler) Login(c *gin.Context) {
+	resp, err := h.service.BeginLogin(c.Request.Context())
+	if err != nil {
+		handleServiceError(c, err, "Failed to start single sign-on")
+		return
+	}
+
+	if c.Query("redirect") == "true" {
+		c.Redirect(http.StatusFound, resp.AuthorizationURL)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Success", resp)
+}
+
+// Callback handles GET and POST /auth/oidc/callback requests.
+// GET serves the provider's redirect directly; POST lets a frontend that
+// owns the redirect URL forward code and state as JSON.
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	resp, err := h.service.Callback(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err, "Single sign-on failed")
+		return
+	}
+
+	if resp.MFAChallengeResponse != nil {
+		response.Success(c, http.StatusOK, "Two-factor authentication required", resp)
+		return
+	}
+	response.Success(c, http.StatusOK, "Login successful", resp)
+}
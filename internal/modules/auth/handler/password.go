@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/service"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// PasswordHandler handles HTTP requests for password recovery and change.
+type PasswordHandler struct {
+	service service.PasswordService
+}
+
+// NewPasswordHandler creates a new password handler.
+func NewPasswordHandler(svc service.PasswordService) *PasswordHandler {
+	return &PasswordHandler{service: svc}
+}
+
+// Forgot handles POST /auth/password/forgot requests.
+// It always answers 200 so registered emails cannot be discovered.
+func (h *PasswordHandler) Forgot(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	if err := h.service.Forgot(c.Request.Context(), &req); err != nil {
+		if appErr, ok := err.(*apperror.AppError); ok {
+			respondError(c, appErr)
+			return
+		}
+		logger.Error(c.Request.Context(), "Password reset request failed", zap.Error(err))
+		respondError(c, apperror.Internal("Password reset request failed"))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "If the email is registered, a reset link has been sent", nil)
+}
+
+// Reset handles POST /auth/password/reset requests.
+func (h *PasswordHandler) Reset(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	if err := h.service.Reset(c.Request.Context(), &req); err != nil {
+		if appErr, ok := err.(*apperror.AppError); ok {
+			respondError(c, appErr)
+			return
+		}
+		logger.Error(c.Request.Context(), "Password reset failed", zap.Error(err))
+		respondError(c, apperror.Internal("Password reset failed"))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Password has been reset", nil)
+}
+
+// Change handles POST /auth/password/change requests.
+func (h *PasswordHandler) Change(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+	req.SessionID = c.GetString("token_family")
+
+	if err := h.service.Change(c.Request.Context(), c.GetString("user_id"), &req); err != nil {
+		if appErr, ok := err.(*apperror.AppError); ok {
+			respondError(c, appErr)
+			return
+		}
+		logger.Error(c.Request.Context(), "Password change failed", zap.Error(err))
+		respondError(c, apperror.Internal("Password change failed"))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Password changed", nil)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/service"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+)
+
+// RegistrationHandler handles HTTP requests for self-registration and invites.
+type RegistrationHandler struct {
+	service service.RegistrationService
+}
+
+// NewRegistrationHandler creates a new registration handler.
+func NewRegistrationHandler(svc service.RegistrationService) *RegistrationHandler {
+	return &RegistrationHandler{service: svc}
+}
+
+// Register handles POST /auth/register requests.
+// The account is created inactive and waits for an admin to activate it.
+func (h *RegistrationHandler) Register(c *gin.Context) {
+	var req dto.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	user, err := h.service.Register(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err, "Registration failed")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Registration received, the account awaits approval", user)
+}
+
+// AcceptInvite handles POST /auth/invite/accept requests.
+func (h *RegistrationHandler) AcceptInvite(c *gin.Context) {
+	var req dto.AcceptInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	user, err := h.service.AcceptInvite(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to accept invite")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Account created", user)
+}
+
+// ListInvites handles GET /api/system/invites requests.
+// Optional filters: email, status, plus the list query grammar.
+func (h *RegistrationHandler) ListInvites(c *gin.Context) {
+	params, appErr := query.Parse(c, dto.InviteList)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var query dto.InviteQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, apperror.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	if appErr := validator.Validate(&query); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	invites, total, err := h.service.ListInvites(c.Request.Context(), &query, params)
+	if err != nil {
+		handleServiceError(c, err, "Failed to list invites")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, invites, total, params)
+}
+
+// CreateInvite handles POST /api/system/invites requests.
+// The invite token is only returned in this response and in the email.
+func (h *RegistrationHandler) CreateInvite(c *gin.Context) {
+	var req dto.CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ActorPermissions = c.GetStringSlice("permissions")
+	invite, err := h.service.CreateInvite(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to create invite")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Invite sent", invite)
+}
+
+// RevokeInvite handles DELETE /api/system/invites/:id requests.
+func (h *RegistrationHandler) RevokeInvite(c *gin.Context) {
+	if err := h.service.RevokeInvite(c.Request.Context(), c.Param("id")); err != nil {
+		handleServiceError(c, err, "Failed to revoke invite")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invite revoked", nil)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/service"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// SecurityHandler handles HTTP requests for security administration.
+type SecurityHandler struct {
+	service service.SecurityService
+}
+
+// NewSecurityHandler creates a new security handler.
+func NewSecurityHandler(svc service.SecurityService) *SecurityHandler {
+	return &SecurityHandler{service: svc}
+}
+
+// ListEvents handles GET /api/security/events requests.
+// Optional filters: event_type, user_id, email, ip_address, from, to (RFC 3339),
+// plus the list query grammar.
+func (h *SecurityHandler) ListEvents(c *gin.Context) {
+	params, appErr := query.Parse(c, dto.SecurityEventList)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var query dto.SecurityEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, apperror.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	events, total, err := h.service.ListEvents(c.Request.Context(), &query, params)
+	if err != nil {
+		if appErr, ok := err.(*apperror.AppError); ok {
+			respondError(c, appErr)
+			return
+		}
+		logger.Error(c.Request.Context(), "Failed to list security events", zap.Error(err))
+		respondError(c, apperror.Internal("Failed to list security events"))
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, events, total, params)
+}
+
+// Unlock handles POST /api/security/unlock requests.
+func (h *SecurityHandler) Unlock(c *gin.Context) {
+	var req dto.UnlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ClientInfo = clientInfo(c)
+
+	if err := h.service.Unlock(c.Request.Context(), c.GetString("user_id"), &req); err != nil {
+		if appErr, ok := err.(*apperror.AppError); ok {
+			respondError(c, appErr)
+			return
+		}
+		logger.Error(c.Request.Context(), "Failed to unlock account", zap.Error(err))
+		respondError(c, apperror.Internal("Failed to unlock account"))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Account unlocked", nil)
+}
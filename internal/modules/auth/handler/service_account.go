@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/service"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+)
+
+// ServiceAccountHandler handles HTTP requests for service account administration.
+type ServiceAccountHandler struct {
+	service service.ServiceAccountService
+}
+
+// NewServiceAccountHandler creates a new service account handler.
+func NewServiceAccountHandler(svc service.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{service: svc}
+}
+
+// List handles GET /api/system/service-accounts requests.
+// Supports the list query grammar (q matches name or description).
+func (h *ServiceAccountHandler) List(c *gin.Context) {
+	params, appErr := query.Parse(c, dto.ServiceAccountList)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	accounts, total, err := h.service.List(c.Request.Context(), params)
+	if err != nil {
+		handleServiceError(c, err, "Failed to list service accounts")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, accounts, total, params)
+}
+
+// Get handles GET /api/system/service-accounts/:id requests.
+// The response includes every key of the account, revoked ones too.
+func (h *ServiceAccountHandler) Get(c *gin.Context) {
+	account, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleServiceError(c, err, "Failed to get service account")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Success", account)
+}
+
+// Create handles POST /api/system/service-accounts requests.
+func (h *ServiceAccountHandler) Create(c *gin.Context) {
+	var req dto.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	account, err := h.service.Create(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to create service account")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Service account created", account)
+}
+
+// Update handles PATCH /api/system/service-accounts/:id requests.
+func (h *ServiceAccountHandler) Update(c *gin.Context) {
+	var req dto.UpdateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	account, err := h.service.Update(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to update service account")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service account updated", account)
+}
+
+// Delete handles DELETE /api/system/service-accounts/:id requests.
+func (h *ServiceAccountHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.GetString("user_id"), c.Param("id")); err != nil {
+		handleServiceError(c, err, "Failed to delete service account")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service account deleted", nil)
+}
+
+// CreateKey handles POST /api/system/service-accounts/:id/keys requests.
+// The plaintext key is only returned in this response.
+func (h *ServiceAccountHandler) CreateKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	key, err := h.service.CreateKey(c.Request.Context(), c.GetString("user_id"), c.GetStringSlice("permissions"), c.Param("id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to create API key")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "API key created, store it now: it cannot be shown again", key)
+}
+
+// RotateKey handles POST /api/system/service-accounts/:id/keys/:keyId/rotate requests.
+func (h *ServiceAccountHandler) RotateKey(c *gin.Context) {
+	var req dto.RotateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	key, err := h.service.RotateKey(c.Request.Context(), c.GetString("user_id"), c.GetStringSlice("permissions"), c.Param("id"), c.Param("keyId"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to rotate API key")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "API key rotated, store it now: it cannot be shown again", key)
+}
+
+// RevokeKey handles DELETE /api/system/service-accounts/:id/keys/:keyId requests.
+func (h *ServiceAccountHandler) RevokeKey(c *gin.Context) {
+	if err := h.service.RevokeKey(c.Request.Context(), c.Param("id"), c.Param("keyId")); err != nil {
+		handleServiceError(c, err, "Failed to revoke API key")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "API key revoked", nil)
+}
+
+// ListStaleKeys handles GET /api/system/api-keys/stale requests.
+// Optional filter: unused_days (default 90), plus the list query grammar.
+func (h *ServiceAccountHandler) ListStaleKeys(c *gin.Context) {
+	params, appErr := query.Parse(c, dto.StaleAPIKeyList)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var query dto.StaleAPIKeyQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, apperror.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	keys, total, err := h.service.ListStaleKeys(c.Request.Context(), &query, params)
+	if err != nil {
+		handleServiceError(c, err, "Failed to list API keys")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, keys, total, params)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/shared/response"
+)
+
+// ListSessions handles GET /auth/sessions requests.
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.service.ListSessions(c.Request.Context(), c.GetString("user_id"), c.GetString("token_family"))
+	if err != nil {
+		handleServiceError(c, err, "Failed to list sessions")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Sessions retrieved", sessions)
+}
+
+// RevokeSession handles DELETE /auth/sessions/:id requests.
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	if err := h.service.RevokeSession(c.Request.Context(), c.GetString("user_id"), c.Param("id"), clientInfo(c)); err != nil {
+		handleServiceError(c, err, "Failed to revoke session")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Session revoked", nil)
+}
+
+// ListUserSessions handles GET /api/system/users/:id/sessions requests.
+func (h *AuthHandler) ListUserSessions(c *gin.Context) {
+	sessions, err := h.service.ListSessions(c.Request.Context(), c.Param("id"), "")
+	if err != nil {
+		handleServiceError(c, err, "Failed to list sessions")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Sessions retrieved", sessions)
+}
+
+// RevokeUserSessions handles DELETE /api/system/users/:id/sessions requests,
+// signing the user out on every device.
+func (h *AuthHandler) RevokeUserSessions(c *gin.Context) {
+	resp, err := h.service.RevokeAllSessions(c.Request.Context(), c.GetString("user_id"), c.Param("id"), clientInfo(c))
+	if err != nil {
+		handleServiceError(c, err, "Failed to revoke sessions")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User signed out everywhere", resp)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/service"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+)
+
+// UserHandler handles HTTP requests for user administration.
+type UserHandler struct {
+	service service.UserService
+}
+
+// NewUserHandler creates a new user handler.
+func NewUserHandler(svc service.UserService) *UserHandler {
+	return &UserHandler{service: svc}
+}
+
+// List handles GET /api/system/users requests.
+// Optional filters: role_id, sub_role_id, branch_id, division_id, is_active,
+// plus the list query grammar (q matches email, full name or employee number).
+func (h *UserHandler) List(c *gin.Context) {
+	params, appErr := query.Parse(c, dto.UserList)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var query dto.UserQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, apperror.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	users, total, err := h.service.List(c.Request.Context(), &query, params)
+	if err != nil {
+		handleServiceError(c, err, "Failed to list users")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, users, total, params)
+}
+
+// Get handles GET /api/system/users/:id requests.
+func (h *UserHandler) Get(c *gin.Context) {
+	user, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleServiceError(c, err, "Failed to get user")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Success", user)
+}
+
+// Create handles POST /api/system/users requests.
+func (h *UserHandler) Create(c *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ActorPermissions = c.GetStringSlice("permissions")
+	user, err := h.service.Create(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to create user")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "User created", user)
+}
+
+// Update handles PATCH /api/system/users/:id requests.
+func (h *UserHandler) Update(c *gin.Context) {
+	var req dto.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ActorPermissions = c.GetStringSlice("permissions")
+	user, err := h.service.Update(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to update user")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User updated", user)
+}
+
+// SetStatus handles PATCH /api/system/users/:id/status requests.
+func (h *UserHandler) SetStatus(c *gin.Context) {
+	var req dto.SetUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	req.ActorPermissions = c.GetStringSlice("permissions")
+	user, err := h.service.SetActive(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to update user status")
+		return
+	}
+
+	message := "User deactivated"
+	if user.IsActive {
+		message = "User activated"
+	}
+	response.Success(c, http.StatusOK, message, user)
+}
+
+// Delete handles DELETE /api/system/users/:id requests (soft delete).
+func (h *UserHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.GetString("user_id"), c.Param("id"), c.GetStringSlice("permissions")); err != nil {
+		handleServiceError(c, err, "Failed to delete user")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User deleted", nil)
+}
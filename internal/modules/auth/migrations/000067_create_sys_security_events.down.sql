@@ -0,0 +1,2 @@
+-- Drop sys_security_events table
+DROP TABLE IF EXISTS sys_security_events;
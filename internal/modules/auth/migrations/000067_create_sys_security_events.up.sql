@@ -0,0 +1,29 @@
+-- Create sys_security_events table
+-- Append-only audit log of authentication and account security events
+CREATE TABLE IF NOT EXISTS sys_security_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    
+    -- Event
+    event_type VARCHAR(50) NOT NULL, -- Event type (e.g., login_success, login_failure, account_locked, account_unlocked)
+    reason VARCHAR(100),             -- Machine readable reason (e.g., invalid_password, account_locked)
+    
+    -- Subject and actor
+    user_id UUID,          -- Affected user (NULL when the email is unknown)
+    email VARCHAR(255),    -- Email presented or affected by the event
+    actor_id UUID,         -- User who performed the action (e.g., admin unlocking an account)
+    
+    -- Request context
+    ip_address VARCHAR(45), -- Client IP address (IPv4 or IPv6)
+    user_agent TEXT,        -- Client user agent
+    request_id VARCHAR(100), -- X-Request-ID of the originating request
+    
+    -- Audit fields
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- Timestamp when the event occurred
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sys_security_events_event_type ON sys_security_events(event_type);
+CREATE INDEX IF NOT EXISTS idx_sys_security_events_user_id ON sys_security_events(user_id);
+CREATE INDEX IF NOT EXISTS idx_sys_security_events_email ON sys_security_events(email);
+CREATE INDEX IF NOT EXISTS idx_sys_security_events_ip_address ON sys_security_events(ip_address);
+CREATE INDEX IF NOT EXISTS idx_sys_security_events_created_at ON sys_security_events(created_at);
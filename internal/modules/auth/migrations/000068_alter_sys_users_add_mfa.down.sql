@@ -0,0 +1,5 @@
+-- Revert sys_users two-factor authentication columns
+ALTER TABLE sys_users
+    DROP COLUMN IF EXISTS mfa_confirmed_at,
+    DROP COLUMN IF EXISTS mfa_secret,
+    DROP COLUMN IF EXISTS mfa_enabled;
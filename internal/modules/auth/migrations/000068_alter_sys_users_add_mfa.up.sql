@@ -0,0 +1,6 @@
+-- Alter sys_users table
+-- Adds TOTP (RFC 6238) two-factor authentication state
+ALTER TABLE sys_users
+    ADD COLUMN IF NOT EXISTS mfa_enabled BOOLEAN NOT NULL DEFAULT false, -- TOTP confirmed and required at login
+    ADD COLUMN IF NOT EXISTS mfa_secret TEXT,                            -- Encrypted TOTP secret (set at enrollment, before confirmation)
+    ADD COLUMN IF NOT EXISTS mfa_confirmed_at TIMESTAMP WITH TIME ZONE;  -- When the current secret was confirmed
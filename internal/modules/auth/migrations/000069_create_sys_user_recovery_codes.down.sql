@@ -0,0 +1,2 @@
+-- Drop sys_user_recovery_codes table
+DROP TABLE IF EXISTS sys_user_recovery_codes;
@@ -0,0 +1,15 @@
+-- Create sys_user_recovery_codes table
+-- Single-use two-factor recovery codes; only hashes are stored
+CREATE TABLE IF NOT EXISTS sys_user_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    
+    user_id UUID NOT NULL REFERENCES sys_users(id) ON DELETE CASCADE, -- Owner of the code
+    code_hash VARCHAR(64) NOT NULL,                                   -- SHA-256 hex of the normalized code
+    used_at TIMESTAMP WITH TIME ZONE,                                 -- When the code was redeemed (NULL = unused)
+    
+    -- Audit fields
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- Timestamp when the code was generated
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sys_user_recovery_codes_user_hash ON sys_user_recovery_codes(user_id, code_hash);
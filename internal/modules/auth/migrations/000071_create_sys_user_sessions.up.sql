@@ -0,0 +1,20 @@
+-- Create sys_user_sessions table
+-- One row per login; the id is the token family carried in the JWT "fid" claim
+CREATE TABLE IF NOT EXISTS sys_user_sessions (
+    id UUID PRIMARY KEY,                                              -- Token family ID
+    
+    user_id UUID NOT NULL REFERENCES sys_users(id) ON DELETE CASCADE, -- Session owner
+    token_id VARCHAR(64),                                             -- jti of the latest access token
+    user_agent VARCHAR(512),                                          -- Client user agent at login / last refresh
+    ip_address VARCHAR(45),                                           -- Client IP at login / last refresh
+    last_seen_at TIMESTAMP WITH TIME ZONE,                            -- Last authenticated request (minute precision)
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,                     -- Refresh token expiry, extended on refresh
+    revoked_at TIMESTAMP WITH TIME ZONE,                              -- When the session was ended (NULL = active)
+    revoked_reason VARCHAR(50),                                       -- logout, revoked, sign_out_all, session_limit, refresh_reuse
+    
+    -- Audit fields
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- Login time
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sys_user_sessions_user_active ON sys_user_sessions(user_id, expires_at) WHERE revoked_at IS NULL;
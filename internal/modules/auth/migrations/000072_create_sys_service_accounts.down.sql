@@ -0,0 +1,2 @@
+-- Drop sys_service_accounts table
+DROP TABLE IF EXISTS sys_service_accounts;
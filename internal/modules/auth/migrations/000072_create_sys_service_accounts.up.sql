@@ -0,0 +1,20 @@
+-- Create sys_service_accounts table
+-- Non-human callers (batch jobs, partner integrations) authenticating with API keys
+CREATE TABLE IF NOT EXISTS sys_service_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    
+    name VARCHAR(100) NOT NULL,              -- Display name, e.g. "InvestPro batch"
+    description VARCHAR(255),                -- Purpose / owner contact
+    is_active BOOLEAN NOT NULL DEFAULT true, -- Inactive accounts reject all of their keys
+    
+    -- Audit fields
+    created_by UUID,    -- User who created this record
+    updated_by UUID,    -- User who last updated this record
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, -- Timestamp when record was created
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, -- Timestamp when record was last updated
+    deleted_at TIMESTAMP WITH TIME ZONE -- Timestamp for soft deletion
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sys_service_accounts_name ON sys_service_accounts(LOWER(name)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_sys_service_accounts_deleted_at ON sys_service_accounts(deleted_at);
@@ -0,0 +1,2 @@
+-- Drop sys_api_keys table
+DROP TABLE IF EXISTS sys_api_keys;
@@ -0,0 +1,23 @@
+-- Create sys_api_keys table
+-- API keys of service accounts; only a SHA-256 hash of each key is stored
+CREATE TABLE IF NOT EXISTS sys_api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    
+    service_account_id UUID NOT NULL REFERENCES sys_service_accounts(id) ON DELETE CASCADE, -- Owning account
+    name VARCHAR(100) NOT NULL,                 -- Label, e.g. "production"
+    prefix VARCHAR(16) NOT NULL,                -- Public lookup part of the key
+    key_hash VARCHAR(64) NOT NULL,              -- SHA-256 hex of the full key
+    scopes TEXT NOT NULL DEFAULT '',            -- Space-separated permission codes
+    expires_at TIMESTAMP WITH TIME ZONE,        -- Optional expiry (NULL = never)
+    last_used_at TIMESTAMP WITH TIME ZONE,      -- Last authenticated request (minute precision)
+    revoked_at TIMESTAMP WITH TIME ZONE,        -- When the key was revoked (NULL = active)
+    rotated_from UUID,                          -- Key this one replaced
+    
+    -- Audit fields
+    created_by UUID,    -- User who issued the key
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- Timestamp when the key was issued
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sys_api_keys_prefix ON sys_api_keys(prefix);
+CREATE INDEX IF NOT EXISTS idx_sys_api_keys_service_account_id ON sys_api_keys(service_account_id);
@@ -0,0 +1,4 @@
+-- Revert sys_users password expiry columns
+ALTER TABLE sys_users
+    DROP COLUMN IF EXISTS must_change_password,
+    DROP COLUMN IF EXISTS password_changed_at;
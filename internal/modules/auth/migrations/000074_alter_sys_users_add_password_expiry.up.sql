@@ -0,0 +1,5 @@
+-- Alter sys_users table
+-- Adds password age tracking and a forced-change flag
+ALTER TABLE sys_users
+    ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, -- Start of the expiry clock (existing users start now)
+    ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT false;                    -- Force a change at next login (e.g. admin-set password)
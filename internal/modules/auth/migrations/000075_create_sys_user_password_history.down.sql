@@ -0,0 +1,2 @@
+-- Drop sys_user_password_history table
+DROP TABLE IF EXISTS sys_user_password_history;
@@ -0,0 +1,18 @@
+-- Create sys_user_password_history table
+-- Previous password hashes, used to block reuse of the last N passwords
+CREATE TABLE IF NOT EXISTS sys_user_password_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    
+    user_id UUID NOT NULL REFERENCES sys_users(id) ON DELETE CASCADE, -- Owner of the password
+    password_hash VARCHAR(255) NOT NULL,                              -- Bcrypt hash, same format as sys_users.password
+    
+    -- Audit fields
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- When the password was set
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sys_user_password_history_user ON sys_user_password_history(user_id, created_at DESC);
+
+-- Seed history with every user's current password
+INSERT INTO sys_user_password_history (user_id, password_hash, created_at)
+SELECT id, password, COALESCE(password_changed_at, CURRENT_TIMESTAMP) FROM sys_users;
@@ -0,0 +1,2 @@
+-- Drop sys_user_invites table
+DROP TABLE IF EXISTS sys_user_invites;
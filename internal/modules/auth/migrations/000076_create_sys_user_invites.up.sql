@@ -0,0 +1,23 @@
+-- Create sys_user_invites table
+-- Admin-issued invitations; the invitee redeems the signed invite token to set a password
+CREATE TABLE IF NOT EXISTS sys_user_invites (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID, also the invite token's jti
+    
+    email VARCHAR(255) NOT NULL,            -- Invitee email, becomes the account email
+    full_name VARCHAR(255),                 -- Suggested name, the invitee may change it
+    role_id UUID,                           -- Preassigned role (sys_roles)
+    sub_role_id UUID,                       -- Preassigned sub-role (sys_sub_roles)
+    branch_id UUID,                         -- Preassigned branch (mst_branches)
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL, -- Invite token expiry
+    accepted_at TIMESTAMP WITH TIME ZONE,   -- Set when redeemed
+    accepted_user_id UUID REFERENCES sys_users(id), -- Account created from this invite
+    revoked_at TIMESTAMP WITH TIME ZONE,    -- Set when withdrawn or replaced by a newer invite
+    
+    -- Audit fields
+    created_by UUID,    -- Admin who issued the invite
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- Timestamp when record was created
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sys_user_invites_email ON sys_user_invites(LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_sys_user_invites_pending ON sys_user_invites(expires_at) WHERE accepted_at IS NULL AND revoked_at IS NULL;
@@ -0,0 +1,18 @@
+-- Create sys_user_identities table
+-- Links sys_users to accounts at external identity providers (OIDC)
+CREATE TABLE IF NOT EXISTS sys_user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    
+    user_id UUID NOT NULL REFERENCES sys_users(id) ON DELETE CASCADE, -- Linked local account
+    provider VARCHAR(255) NOT NULL,       -- Provider identifier, the OIDC issuer URL
+    subject VARCHAR(255) NOT NULL,        -- Stable user ID at the provider (sub claim)
+    email VARCHAR(255),                   -- Email reported at the last sign-in
+    last_login_at TIMESTAMP WITH TIME ZONE, -- Last sign-in through this identity
+    
+    -- Audit fields
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- When the identity was linked
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sys_user_identities_subject ON sys_user_identities(provider, subject);
+CREATE INDEX IF NOT EXISTS idx_sys_user_identities_user ON sys_user_identities(user_id);
@@ -0,0 +1,22 @@
+-- Create sys_user_impersonations table
+-- One row per admin impersonation; the id is the jti of the impersonation token
+CREATE TABLE IF NOT EXISTS sys_user_impersonations (
+    id UUID PRIMARY KEY,                                  -- Impersonation token jti
+    
+    actor_id UUID NOT NULL REFERENCES sys_users(id),      -- Administrator acting as the subject
+    subject_id UUID NOT NULL REFERENCES sys_users(id),    -- Impersonated user
+    session_id UUID,                                      -- Administrator's session (sys_user_sessions), ends the impersonation with it
+    reason VARCHAR(255) NOT NULL,                         -- Why, e.g. the support ticket
+    allow_write BOOLEAN NOT NULL DEFAULT false,           -- Changes allowed (actor role's allow_impersonation_write)
+    ip_address VARCHAR(45),                               -- Administrator IP at start
+    user_agent VARCHAR(512),                              -- Administrator user agent at start
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,         -- Impersonation token expiry
+    ended_at TIMESTAMP WITH TIME ZONE,                    -- When it was stopped (NULL = running until expires_at)
+    
+    -- Audit fields
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- Start time
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sys_user_impersonations_actor ON sys_user_impersonations(actor_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_sys_user_impersonations_subject ON sys_user_impersonations(subject_id, created_at);
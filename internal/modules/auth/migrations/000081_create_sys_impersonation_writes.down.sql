@@ -0,0 +1,2 @@
+-- Drop sys_impersonation_writes table
+DROP TABLE IF EXISTS sys_impersonation_writes;
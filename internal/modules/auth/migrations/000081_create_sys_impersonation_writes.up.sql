@@ -0,0 +1,22 @@
+-- Create sys_impersonation_writes table
+-- Append-only journal of rows written while impersonating; created_by/updated_by
+-- on those rows name the subject, this journal names the administrator
+CREATE TABLE IF NOT EXISTS sys_impersonation_writes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    
+    impersonation_id UUID NOT NULL REFERENCES sys_user_impersonations(id), -- Impersonation the write was made under
+    actor_id UUID NOT NULL,                -- Administrator who made the change
+    subject_id UUID NOT NULL,              -- Impersonated user stamped in created_by/updated_by
+    target_table VARCHAR(100) NOT NULL,    -- Written table
+    operation VARCHAR(10) NOT NULL,        -- create, update or delete
+    row_id VARCHAR(100),                   -- Primary key of the row, NULL for writes by condition
+    rows_affected BIGINT NOT NULL,         -- Rows changed by the statement
+    request_id VARCHAR(100),               -- X-Request-ID of the API request
+    
+    -- Audit fields
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- When the write was made
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sys_impersonation_writes_impersonation ON sys_impersonation_writes(impersonation_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_sys_impersonation_writes_row ON sys_impersonation_writes(target_table, row_id);
@@ -0,0 +1,3 @@
+-- Revert sys_users credential source column
+ALTER TABLE sys_users
+    DROP COLUMN IF EXISTS credential_source;
@@ -0,0 +1,10 @@
+-- Alter sys_users table
+-- Records which backend owns the account's password
+ALTER TABLE sys_users
+    ADD COLUMN IF NOT EXISTS credential_source VARCHAR(20) NOT NULL DEFAULT 'local'; -- local (bcrypt in sys_users) or ldap (directory bind, no local password)
+
+-- Accounts provisioned from the directory before this column have no local
+-- password and no OIDC identity
+UPDATE sys_users u SET credential_source = 'ldap'
+WHERE u.password = ''
+  AND NOT EXISTS (SELECT 1 FROM sys_user_identities i WHERE i.user_id = u.id);
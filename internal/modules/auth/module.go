@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,40 +10,319 @@ import (
 	"github.com/user/go-boilerplate/internal/modules/auth/handler"
 	"github.com/user/go-boilerplate/internal/modules/auth/repository"
 	"github.com/user/go-boilerplate/internal/modules/auth/service"
+	"github.com/user/go-boilerplate/internal/shared/permission"
+	"github.com/user/go-boilerplate/pkg/cache"
+	"github.com/user/go-boilerplate/pkg/directory"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/mailer"
+	"github.com/user/go-boilerplate/pkg/password"
+	"github.com/user/go-boilerplate/pkg/token"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 // Module represents the auth module.
 type Module struct {
-	Handler *handler.AuthHandler
-	Service service.AuthService
+	Handler         *handler.AuthHandler
+	Service         service.AuthService
+	Keys            *token.KeySet
+	ServiceAccounts service.ServiceAccountService
+	DataScopes      service.DataScopeService
+	jwksHandler     *handler.JWKSHandler
+	securityHandler *handler.SecurityHandler
+	passwordHandler *handler.PasswordHandler
+	userHandler     *handler.UserHandler
+	accountHandler  *handler.ServiceAccountHandler
+	registration    *handler.RegistrationHandler
+	impersonation   *handler.ImpersonationHandler
+	oidcHandler     *handler.OIDCHandler // Nil when single sign-on is not configured
 }
 
 // New creates and initializes the auth module.
-func New(db *gorm.DB, cfg *config.Config) *Module {
+func New(db *gorm.DB, cfg *config.Config, cache *cache.Client) *Module {
 	repo := repository.NewUserRepository(db)
-	svc := service.NewAuthService(repo, cfg.JWTSecret, time.Duration(cfg.JWTExpiryHours)*time.Hour)
+	tokenRepo := repository.NewTokenRepository(cache)
+	permissionRepo := repository.NewPermissionRepository(db, cache)
+	attemptRepo := repository.NewLoginAttemptRepository(cache)
+	eventRepo := repository.NewSecurityEventRepository(db)
+	mfaRepo := repository.NewMFARepository(db, cache)
+	sessionRepo := repository.NewSessionRepository(db, cache)
+
+	keys, err := token.NewKeySet(token.Config{
+		Algorithm:      cfg.JWTAlgorithm,
+		Secret:         cfg.JWTSecret,
+		PrivateKeyFile: cfg.JWTPrivateKeyFile,
+		KeyID:          cfg.JWTKeyID,
+		PublicKeyFiles: splitList(cfg.JWTPublicKeys),
+		Issuer:         cfg.JWTIssuer,
+		Audience:       cfg.JWTAudience,
+	})
+	if err != nil {
+		logger.Log.Fatal("Failed to load JWT keys", zap.Error(err))
+	}
+
+	strength, err := password.NewPolicy(password.Config{
+		MinLength:       cfg.PasswordMinLength,
+		RequiredClasses: splitList(cfg.PasswordRequiredClasses),
+		CommonListFile:  cfg.PasswordCommonListFile,
+	})
+	if err != nil {
+		logger.Log.Fatal("Invalid password policy", zap.Error(err))
+	}
+	if cfg.MFAEncryptionKey == "" || cfg.MFAEncryptionKey == cfg.JWTSecret {
+		logger.Log.Fatal("MFA_ENCRYPTION_KEY must be set and differ from JWT_SECRET")
+	}
+	passwords := service.NewPasswordManager(strength, repository.NewPasswordHistoryRepository(db), service.PasswordPolicy{
+		MaxAge:      time.Duration(cfg.PasswordExpiryDays) * 24 * time.Hour,
+		HistorySize: cfg.PasswordHistorySize,
+	})
+
+	svc := service.NewAuthService(repo, tokenRepo, permissionRepo, attemptRepo, eventRepo, mfaRepo, sessionRepo, passwords, newCredentialVerifier(cfg, repo), service.TokenConfig{
+		Keys:          keys,
+		AccessExpiry:  time.Duration(cfg.JWTAccessExpiryMinutes) * time.Minute,
+		RefreshExpiry: time.Duration(cfg.JWTRefreshExpiryHours) * time.Hour,
+	}, service.LoginPolicy{
+		MaxAttempts:   cfg.LoginMaxAttempts,
+		IPMaxAttempts: cfg.LoginIPMaxAttempts,
+		Window:        time.Duration(cfg.LoginAttemptWindowMinutes) * time.Minute,
+		Lockout:       time.Duration(cfg.LoginLockoutMinutes) * time.Minute,
+		MaxLockout:    time.Duration(cfg.LoginLockoutMaxMinutes) * time.Minute,
+	}, service.MFAConfig{
+		Issuer:          cfg.MFAIssuer,
+		EncryptionKey:   cfg.MFAEncryptionKey,
+		ChallengeExpiry: time.Duration(cfg.MFAChallengeExpiryMinutes) * time.Minute,
+	}, service.SessionPolicy{
+		MaxPerUser: cfg.SessionMaxPerUser,
+	})
 	h := handler.NewAuthHandler(svc)
+	securitySvc := service.NewSecurityService(attemptRepo, eventRepo)
+
+	mail, err := mailer.New(mailer.Config{
+		Driver:    cfg.MailDriver,
+		From:      cfg.MailFrom,
+		Host:      cfg.SMTPHost,
+		Port:      cfg.SMTPPort,
+		Username:  cfg.SMTPUsername,
+		Password:  cfg.SMTPPassword,
+		OutboxDir: cfg.MailOutboxDir,
+	})
+	if err != nil {
+		logger.Log.Warn("Invalid mail configuration, falling back to file outbox", zap.Error(err))
+		mail = mailer.NewFileSender(cfg.MailFrom, cfg.MailOutboxDir)
+	}
+	passwordSvc := service.NewPasswordService(repo, repository.NewPasswordResetRepository(cache), attemptRepo, eventRepo, svc, mail, passwords, service.PasswordResetConfig{
+		URL:    cfg.PasswordResetURL,
+		Expiry: time.Duration(cfg.PasswordResetExpiryMinutes) * time.Minute,
+	})
+
+	if !service.ValidRegistrationMode(cfg.RegistrationMode) {
+		logger.Log.Fatal("Invalid REGISTRATION_MODE", zap.String("mode", cfg.RegistrationMode))
+	}
+	assignmentRepo := repository.NewAssignmentRepository(db)
+	registrationSvc := service.NewRegistrationService(repo, assignmentRepo, permissionRepo, repository.NewInviteRepository(db), eventRepo, passwords, keys, mail, service.RegistrationConfig{
+		Mode:           cfg.RegistrationMode,
+		AllowedDomains: splitList(strings.ToLower(cfg.RegistrationAllowedDomains)),
+		InviteURL:      cfg.InviteURL,
+		InviteExpiry:   time.Duration(cfg.InviteExpiryHours) * time.Hour,
+	})
+
+	var oidcHandler *handler.OIDCHandler
+	if cfg.OIDCIssuerURL != "" {
+		roleMappings, err := service.ParseRoleMappings(cfg.OIDCGroupRoles)
+		if err != nil {
+			logger.Log.Fatal("Invalid OIDC_GROUP_ROLES", zap.Error(err))
+		}
+		oidcHandler = handler.NewOIDCHandler(service.NewOIDCService(svc, repo, repository.NewIdentityRepository(db, cache), service.OIDCConfig{
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       splitList(cfg.OIDCScopes),
+			GroupsClaim:  cfg.OIDCGroupsClaim,
+			RoleMappings: roleMappings,
+			LinkOnly:     cfg.OIDCLinkOnly,
+		}))
+	}
+
+	accountSvc := service.NewServiceAccountService(repository.NewServiceAccountRepository(db, cache))
+	impersonationSvc := service.NewImpersonationService(repo, repository.NewImpersonationRepository(db), permissionRepo, tokenRepo, eventRepo, service.ImpersonationConfig{
+		Keys:   keys,
+		Expiry: time.Duration(cfg.ImpersonationExpiryMinutes) * time.Minute,
+	})
 
 	return &Module{
-		Handler: h,
-		Service: svc,
+		Handler:         h,
+		Service:         svc,
+		Keys:            keys,
+		ServiceAccounts: accountSvc,
+		DataScopes:      service.NewDataScopeService(repository.NewBranchScopeRepository(db, cache), eventRepo),
+		jwksHandler:     handler.NewJWKSHandler(keys),
+		securityHandler: handler.NewSecurityHandler(securitySvc),
+		passwordHandler: handler.NewPasswordHandler(passwordSvc),
+		userHandler:     handler.NewUserHandler(service.NewUserService(repo, assignmentRepo, permissionRepo, svc, passwords)),
+		accountHandler:  handler.NewServiceAccountHandler(accountSvc),
+		registration:    handler.NewRegistrationHandler(registrationSvc),
+		impersonation:   handler.NewImpersonationHandler(impersonationSvc),
+		oidcHandler:     oidcHandler,
 	}
 }
 
 // RegisterRoutes registers all auth routes.
 func (m *Module) RegisterRoutes(r *gin.Engine, jwtMiddleware gin.HandlerFunc) {
+	r.GET("/.well-known/jwks.json", m.jwksHandler.JWKS)
+
 	auth := r.Group("/auth")
 	auth.POST("/login", m.Handler.Login)
-	auth.POST("/register", m.Handler.Register)
+	auth.POST("/register", m.registration.Register)
+	auth.POST("/invite/accept", m.registration.AcceptInvite)
+	auth.POST("/refresh", m.Handler.Refresh)
+	auth.POST("/password/forgot", m.passwordHandler.Forgot)
+	auth.POST("/password/reset", m.passwordHandler.Reset)
+	auth.POST("/login/mfa", m.Handler.VerifyMFA)
+	auth.POST("/mfa/setup", m.Handler.SetupMFA)
+	auth.POST("/mfa/setup/confirm", m.Handler.ConfirmMFASetup)
 
+	if m.oidcHandler != nil {
+		auth.GET("/oidc/login", m.oidcHandler.Login)
+		auth.GET("/oidc/callback", m.oidcHandler.Callback)
+		auth.POST("/oidc/callback", m.oidcHandler.Callback)
+	}
+
+	// An impersonation token acts as the user, but never on their own
+	// credentials and devices; it ends through /auth/impersonation/stop
+	self := middleware.NoImpersonation()
+	r.POST("/auth/logout", jwtMiddleware, self, m.Handler.Logout)
+	r.POST("/auth/password/change", jwtMiddleware, self, m.passwordHandler.Change)
 	r.GET("/auth/me", jwtMiddleware, m.Handler.GetMe)
+	r.GET("/auth/sessions", jwtMiddleware, m.Handler.ListSessions)
+	r.DELETE("/auth/sessions/:id", jwtMiddleware, self, m.Handler.RevokeSession)
+	r.POST("/auth/impersonation/stop", jwtMiddleware, m.impersonation.Stop)
+
+	mfa := r.Group("/auth/mfa", jwtMiddleware, self)
+	mfa.POST("/enroll", m.Handler.BeginMFAEnrollment)
+	mfa.POST("/confirm", m.Handler.ConfirmMFAEnrollment)
+	mfa.POST("/disable", m.Handler.DisableMFA)
+	mfa.POST("/recovery-codes", m.Handler.RegenerateRecoveryCodes)
+}
+
+// RegisterAdminRoutes registers security, user, invite and service account administration routes
+// under the authenticated API group.
+func (m *Module) RegisterAdminRoutes(api *gin.RouterGroup) {
+	security := api.Group("/security")
+	security.GET("/events", middleware.RequirePermission(permission.SecurityEventsRead), m.securityHandler.ListEvents)
+	security.POST("/unlock", middleware.RequirePermission(permission.SecurityLockoutsManage), m.securityHandler.Unlock)
+	security.GET("/impersonations", middleware.RequirePermission(permission.SecurityEventsRead), m.impersonation.List)
+	security.GET("/impersonations/:id", middleware.RequirePermission(permission.SecurityEventsRead), m.impersonation.Get)
+
+	read := middleware.RequirePermission(permission.SystemUsersRead)
+	manage := middleware.RequirePermission(permission.SystemUsersManage)
+
+	users := api.Group("/system/users")
+	users.GET("", read, m.userHandler.List)
+	users.GET("/:id", read, m.userHandler.Get)
+	users.POST("", manage, m.userHandler.Create)
+	users.PATCH("/:id", manage, m.userHandler.Update)
+	users.PATCH("/:id/status", manage, m.userHandler.SetStatus)
+	users.DELETE("/:id", manage, m.userHandler.Delete)
+	users.GET("/:id/sessions", read, m.Handler.ListUserSessions)
+	users.DELETE("/:id/sessions", manage, m.Handler.RevokeUserSessions)
+	users.POST("/:id/impersonate", middleware.RequirePermission(permission.SystemUsersImpersonate), middleware.NoImpersonation(), m.impersonation.Start)
+
+	invites := api.Group("/system/invites")
+	invites.GET("", read, m.registration.ListInvites)
+	invites.POST("", manage, m.registration.CreateInvite)
+	invites.DELETE("/:id", manage, m.registration.RevokeInvite)
+
+	readAccounts := middleware.RequirePermission(permission.SystemServiceAccountsRead)
+	manageAccounts := middleware.RequirePermission(permission.SystemServiceAccountsManage)
+
+	accounts := api.Group("/system/service-accounts")
+	accounts.GET("", readAccounts, m.accountHandler.List)
+	accounts.GET("/:id", readAccounts, m.accountHandler.Get)
+	accounts.POST("", manageAccounts, m.accountHandler.Create)
+	accounts.PATCH("/:id", manageAccounts, m.accountHandler.Update)
+	accounts.DELETE("/:id", manageAccounts, m.accountHandler.Delete)
+	accounts.POST("/:id/keys", manageAccounts, m.accountHandler.CreateKey)
+	accounts.POST("/:id/keys/:keyId/rotate", manageAccounts, m.accountHandler.RotateKey)
+	accounts.DELETE("/:id/keys/:keyId", manageAccounts, m.accountHandler.RevokeKey)
+	api.GET("/system/api-keys/stale", readAccounts, m.accountHandler.ListStaleKeys)
 }
 
 // CreateJWTMiddleware creates the JWT middleware for this module.
-func CreateJWTMiddleware(cfg *config.Config) gin.HandlerFunc {
+// The auth service rejects revoked tokens and ended sessions and resolves
+// the caller's permissions; service accounts authenticate with X-API-Key.
+// Tokens flagged for a password change only reach the change endpoint;
+// impersonation tokens are read-only unless the administrator's role allows
+// writes.
+func CreateJWTMiddleware(keys *token.KeySet, svc service.AuthService, apiKeys service.ServiceAccountService) gin.HandlerFunc {
 	return middleware.JWT(middleware.JWTConfig{
-		Secret:    cfg.JWTSecret,
-		SkipPaths: []string{"/health", "/ready", "/auth/login", "/auth/register"},
+		Keys:        keys,
+		SkipPaths:   []string{"/health", "/.well-known/jwks.json", "/ready", "/auth/login", "/auth/register", "/auth/invite/accept", "/auth/refresh", "/auth/password/forgot", "/auth/password/reset", "/auth/login/mfa", "/auth/mfa/setup", "/auth/oidc/"},
+		Revocations: svc,
+		Sessions:    svc,
+		Permissions: svc,
+		APIKeys:     apiKeys,
+		// A pending password change still allows changing it, reading the
+		// profile and signing out
+		PasswordChangePaths: []string{"/auth/password/change", "/auth/me", "/auth/logout"},
+		ImpersonationPaths:  []string{"/auth/impersonation/stop"},
 	})
 }
+
+// newCredentialVerifier builds the AUTH_BACKENDS chain.
+func newCredentialVerifier(cfg *config.Config, repo repository.UserRepository) service.CredentialVerifier {
+	backends := splitList(strings.ToLower(cfg.AuthBackends))
+	if err := service.ValidateBackends(backends); err != nil {
+		logger.Log.Fatal("Invalid AUTH_BACKENDS", zap.Error(err))
+	}
+
+	verifiers := make([]service.CredentialVerifier, 0, len(backends))
+	for _, backend := range backends {
+		switch backend {
+		case service.BackendLocal:
+			verifiers = append(verifiers, service.NewLocalVerifier(repo))
+		case service.BackendLDAP:
+			client, err := directory.New(directory.Config{
+				URL:                     cfg.LDAPURL,
+				StartTLS:                cfg.LDAPStartTLS,
+				CACertFile:              cfg.LDAPTLSCAFile,
+				ServerName:              cfg.LDAPTLSServerName,
+				InsecureSkipVerify:      cfg.LDAPTLSInsecureSkipVerify,
+				BindDN:                  cfg.LDAPBindDN,
+				BindPassword:            cfg.LDAPBindPassword,
+				BaseDN:                  cfg.LDAPBaseDN,
+				UserFilter:              cfg.LDAPUserFilter,
+				EmailAttribute:          cfg.LDAPAttrEmail,
+				FullNameAttribute:       cfg.LDAPAttrFullName,
+				EmployeeNumberAttribute: cfg.LDAPAttrEmployeeNumber,
+				DivisionAttribute:       cfg.LDAPAttrDivision,
+				GroupAttribute:          cfg.LDAPAttrGroups,
+				GroupBaseDN:             cfg.LDAPGroupBaseDN,
+				GroupFilter:             cfg.LDAPGroupFilter,
+				Timeout:                 time.Duration(cfg.LDAPTimeoutSeconds) * time.Second,
+			})
+			if err != nil {
+				logger.Log.Fatal("Invalid LDAP configuration", zap.Error(err))
+			}
+			roleMappings, err := service.ParseRoleMappings(cfg.LDAPGroupRoles)
+			if err != nil {
+				logger.Log.Fatal("Invalid LDAP_GROUP_ROLES", zap.Error(err))
+			}
+			verifiers = append(verifiers, service.NewLDAPVerifier(client, repo, service.LDAPConfig{
+				RoleMappings: roleMappings,
+			}))
+		}
+	}
+	return service.NewChainVerifier(verifiers...)
+}
+
+// splitList splits a comma-separated config value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// AssignmentRepository checks the organizational references a user can be
+// assigned to. Lookups go straight to the owning tables so the auth module
+// does not depend on the system or master modules.
+type AssignmentRepository interface {
+	RoleExists(ctx context.Context, roleID string) (bool, error)
+	SubRoleBelongsTo(ctx context.Context, subRoleID, roleID string) (bool, error)
+	BranchExists(ctx context.Context, branchID string) (bool, error)
+}
+
+type assignmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAssignmentRepository creates a new assignment repository.
+func NewAssignmentRepository(db *gorm.DB) AssignmentRepository {
+	return &assignmentRepository{db: db}
+}
+
+func (r *assignmentRepository) RoleExists(ctx context.Context, roleID string) (bool, error) {
+	return r.exists(ctx, "sys_roles", "id = ? AND is_active", roleID)
+}
+
+func (r *assignmentRepository) SubRoleBelongsTo(ctx context.Context, subRoleID, roleID string) (bool, error) {
+	return r.exists(ctx, "sys_sub_roles", "id = ? AND role_id = ? AND is_active", subRoleID, roleID)
+}
+
+func (r *assignmentRepository) BranchExists(ctx context.Context, branchID string) (bool, error) {
+	return r.exists(ctx, "mst_branches", "id = ?", branchID)
+}
+
+func (r *assignmentRepository) exists(ctx context.Context, table, where string, args ...any) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table(table).
+		Where(where, args...).
+		Where("deleted_at IS NULL").
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/pkg/cache"
+	"gorm.io/gorm"
+)
+
+const (
+	// Master writes to mst_branches delete these keys (see master.Tables).
+	branchScopeCachePrefix = "auth:branch_scope"
+	branchScopeCacheTTL    = 5 * time.Minute
+
+	// branchTypeHeadOffice is mst_branches.branch_type for the head office.
+	branchTypeHeadOffice = 1
+)
+
+// BranchScope is the branch hierarchy seen from one branch.
+type BranchScope struct {
+	HeadOffice bool     `json:"head_office"`
+	Subtree    []string `json:"subtree"` // The branch and every branch below it via main_branch_id
+	Area       []string `json:"area"`    // Every branch in the same area
+}
+
+// BranchScopeRepository resolves branch hierarchies from mst_branches.
+type BranchScopeRepository interface {
+	// Get returns nil for an unknown or deleted branch.
+	Get(ctx context.Context, branchID string) (*BranchScope, error)
+}
+
+type branchScopeRepository struct {
+	db    *gorm.DB
+	cache *cache.Client
+}
+
+// NewBranchScopeRepository creates a new branch scope repository.
+// Hierarchies are cached in Redis per branch, as they are resolved on
+// every authenticated request.
+func NewBranchScopeRepository(db *gorm.DB, cache *cache.Client) BranchScopeRepository {
+	return &branchScopeRepository{db: db, cache: cache}
+}
+
+func (r *branchScopeRepository) Get(ctx context.Context, branchID string) (*BranchScope, error) {
+	cacheKey := cache.CacheKey(branchScopeCachePrefix, branchID)
+
+	var scope BranchScope
+	if r.cache != nil {
+		if found, err := r.cache.Get(ctx, cacheKey, &scope); err == nil && found {
+			return &scope, nil
+		}
+	}
+
+	var branch struct {
+		BranchType int
+		AreaID     string
+	}
+	err := r.db.WithContext(ctx).
+		Table("mst_branches").
+		Select("branch_type, area_id").
+		Where("id = ? AND deleted_at IS NULL", branchID).
+		Take(&branch).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scope = BranchScope{HeadOffice: branch.BranchType == branchTypeHeadOffice}
+
+	// UNION rather than UNION ALL stops on cycles in main_branch_id
+	if err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM mst_branches WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT b.id FROM mst_branches b JOIN subtree s ON b.main_branch_id = s.id WHERE b.deleted_at IS NULL
+		)
+		SELECT id FROM subtree ORDER BY id`, branchID).Scan(&scope.Subtree).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).
+		Table("mst_branches").
+		Where("area_id = ? AND deleted_at IS NULL", branch.AreaID).
+		Order("id").
+		Pluck("id", &scope.Area).Error; err != nil {
+		return nil, err
+	}
+
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, scope, branchScopeCacheTTL)
+	}
+
+	return &scope, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/pkg/cache"
+	"gorm.io/gorm"
+)
+
+const (
+	oidcStatePrefix     = "auth:oidc:state"
+	oidcStateUsedPrefix = "auth:oidc:state:used"
+)
+
+// OIDCState is the per-login secret kept between the authorization redirect
+// and the callback.
+type OIDCState struct {
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+}
+
+// IdentityRepository defines the interface for external identity data access
+// and OIDC login state.
+type IdentityRepository interface {
+	GetBySubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	Link(ctx context.Context, identity *entity.UserIdentity) error
+	Provision(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error
+	Touch(ctx context.Context, id, email string, at time.Time) error
+
+	SaveState(ctx context.Context, state string, value *OIDCState, ttl time.Duration) error
+	ConsumeState(ctx context.Context, state string, ttl time.Duration) (*OIDCState, error)
+}
+
+type identityRepository struct {
+	db    *gorm.DB
+	cache *cache.Client
+}
+
+// NewIdentityRepository creates a new identity repository. Links live in
+// the database; pending OIDC logins are kept in Redis.
+func NewIdentityRepository(db *gorm.DB, cache *cache.Client) IdentityRepository {
+	return &identityRepository{db: db, cache: cache}
+}
+
+func (r *identityRepository) GetBySubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	var identity entity.UserIdentity
+	if err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) Link(ctx context.Context, identity *entity.UserIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+// Provision creates the user and its identity link in one transaction.
+func (r *identityRepository) Provision(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
+
+func (r *identityRepository) Touch(ctx context.Context, id, email string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.UserIdentity{}).Where("id = ?", id).
+		Updates(map[string]any{"email": email, "last_login_at": at}).Error
+}
+
+func (r *identityRepository) SaveState(ctx context.Context, state string, value *OIDCState, ttl time.Duration) error {
+	return r.cache.Set(ctx, cache.CacheKey(oidcStatePrefix, state), value, ttl)
+}
+
+// ConsumeState returns the login state exactly once. Nil means the state is
+// unknown, expired or already used.
+func (r *identityRepository) ConsumeState(ctx context.Context, state string, ttl time.Duration) (*OIDCState, error) {
+	var value OIDCState
+	found, err := r.cache.Get(ctx, cache.CacheKey(oidcStatePrefix, state), &value)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	fresh, err := r.cache.SetNX(ctx, cache.CacheKey(oidcStateUsedPrefix, state), true, ttl)
+	if err != nil || !fresh {
+		return nil, err
+	}
+
+	if err := r.cache.Delete(ctx, cache.CacheKey(oidcStatePrefix, state)); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
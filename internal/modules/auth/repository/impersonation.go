@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"gorm.io/gorm"
+)
+
+// ImpersonationFilter narrows an impersonation search. Empty fields are ignored.
+type ImpersonationFilter struct {
+	ActorID   string
+	SubjectID string
+}
+
+// ImpersonationRepository defines the interface for impersonation data access.
+type ImpersonationRepository interface {
+	Create(ctx context.Context, impersonation *entity.Impersonation) error
+	GetByID(ctx context.Context, id string) (*entity.Impersonation, error)
+	// End stamps ended_at and reports false if it had already ended.
+	End(ctx context.Context, id string, at time.Time) (bool, error)
+	List(ctx context.Context, filter ImpersonationFilter, params *query.Params) ([]*entity.Impersonation, int64, error)
+	ListWrites(ctx context.Context, impersonationID string) ([]*entity.ImpersonationWrite, error)
+	// RoleAllowsWrite reports whether the role may make changes while impersonating.
+	RoleAllowsWrite(ctx context.Context, roleID string) (bool, error)
+}
+
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+// NewImpersonationRepository creates a new impersonation repository.
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+func (r *impersonationRepository) Create(ctx context.Context, impersonation *entity.Impersonation) error {
+	return r.db.WithContext(ctx).Create(impersonation).Error
+}
+
+func (r *impersonationRepository) GetByID(ctx context.Context, id string) (*entity.Impersonation, error) {
+	var impersonation entity.Impersonation
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&impersonation).Error; err != nil {
+		return nil, err
+	}
+	return &impersonation, nil
+}
+
+func (r *impersonationRepository) End(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Impersonation{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *impersonationRepository) List(ctx context.Context, filter ImpersonationFilter, params *query.Params) ([]*entity.Impersonation, int64, error) {
+	var impersonations []*entity.Impersonation
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Impersonation{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.SubjectID != "" {
+		query = query.Where("subject_id = ?", filter.SubjectID)
+	}
+
+	if err := params.Count(query, &total); err != nil {
+		return nil, 0, err
+	}
+
+	if err := params.Find(query, &impersonations); err != nil {
+		return nil, 0, err
+	}
+
+	return impersonations, total, nil
+}
+
+func (r *impersonationRepository) ListWrites(ctx context.Context, impersonationID string) ([]*entity.ImpersonationWrite, error) {
+	var writes []*entity.ImpersonationWrite
+	err := r.db.WithContext(ctx).
+		Where("impersonation_id = ?", impersonationID).
+		Order("created_at").
+		Find(&writes).Error
+	return writes, err
+}
+
+func (r *impersonationRepository) RoleAllowsWrite(ctx context.Context, roleID string) (bool, error) {
+	var allowed []bool
+	err := r.db.WithContext(ctx).Table("sys_roles").
+		Where("id = ? AND deleted_at IS NULL", roleID).
+		Pluck("allow_impersonation_write", &allowed).Error
+	if err != nil || len(allowed) == 0 {
+		return false, err
+	}
+	return allowed[0], nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"gorm.io/gorm"
+)
+
+// ErrInviteUnavailable is returned by Redeem when the invite was accepted,
+// revoked or expired in the meantime.
+var ErrInviteUnavailable = errors.New("invite is no longer available")
+
+// InviteFilter narrows an invite listing.
+type InviteFilter struct {
+	Email  string
+	Status string // pending, accepted, revoked or expired
+}
+
+// InviteRepository defines the interface for invite data access.
+type InviteRepository interface {
+	Create(ctx context.Context, invite *entity.Invite) error
+	GetByID(ctx context.Context, id string) (*entity.Invite, error)
+	List(ctx context.Context, filter InviteFilter, params *query.Params) ([]*entity.Invite, int64, error)
+	Revoke(ctx context.Context, id string) (bool, error)
+	Redeem(ctx context.Context, inviteID string, user *entity.User) error
+}
+
+type inviteRepository struct {
+	db *gorm.DB
+}
+
+// NewInviteRepository creates a new invite repository.
+func NewInviteRepository(db *gorm.DB) InviteRepository {
+	return &inviteRepository{db: db}
+}
+
+// Create stores the invite and revokes earlier pending invites for the same
+// email, so only the latest link works.
+func (r *inviteRepository) Create(ctx context.Context, invite *entity.Invite) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Invite{}).
+			Where("LOWER(email) = LOWER(?) AND accepted_at IS NULL AND revoked_at IS NULL", invite.Email).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(invite).Error
+	})
+}
+
+func (r *inviteRepository) GetByID(ctx context.Context, id string) (*entity.Invite, error) {
+	var invite entity.Invite
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// List returns invites, newest first.
+func (r *inviteRepository) List(ctx context.Context, filter InviteFilter, params *query.Params) ([]*entity.Invite, int64, error) {
+	var invites []*entity.Invite
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Invite{})
+	if filter.Email != "" {
+		query = query.Where("LOWER(email) LIKE ?", "%"+filter.Email+"%")
+	}
+
+	now := time.Now()
+	switch filter.Status {
+	case entity.InviteStatusPending:
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", now)
+	case entity.InviteStatusAccepted:
+		query = query.Where("accepted_at IS NOT NULL")
+	case entity.InviteStatusRevoked:
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NOT NULL")
+	case entity.InviteStatusExpired:
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= ?", now)
+	}
+
+	if err := params.Count(query, &total); err != nil {
+		return nil, 0, err
+	}
+
+	if err := params.Find(query, &invites); err != nil {
+		return nil, 0, err
+	}
+
+	return invites, total, nil
+}
+
+// Revoke withdraws a pending invite. It reports false if the invite was
+// already accepted or revoked.
+func (r *inviteRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Invite{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// Redeem creates the user and marks the invite accepted in one transaction.
+// Concurrent redemptions of the same invite create at most one account.
+func (r *inviteRepository) Redeem(ctx context.Context, inviteID string, user *entity.User) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.Invite{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", inviteID, time.Now()).
+			Updates(map[string]any{"accepted_at": time.Now(), "accepted_user_id": user.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInviteUnavailable
+		}
+		return nil
+	})
+}
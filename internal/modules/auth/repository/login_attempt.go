@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/pkg/cache"
+)
+
+const (
+	emailFailuresPrefix = "auth:login:failures:email"
+	ipFailuresPrefix    = "auth:login:failures:ip"
+	lockoutPrefix       = "auth:login:lockout"
+	lockoutCountPrefix  = "auth:login:lockouts"
+)
+
+// LoginAttemptRepository defines the interface for failed-login bookkeeping.
+type LoginAttemptRepository interface {
+	IncrementEmailFailures(ctx context.Context, email string, window time.Duration) (int64, error)
+	IncrementIPFailures(ctx context.Context, ip string, window time.Duration) (int64, error)
+	GetIPFailures(ctx context.Context, ip string) (int64, error)
+	ResetEmailFailures(ctx context.Context, email string) error
+	ResetIPFailures(ctx context.Context, ip string) error
+	GetLockedUntil(ctx context.Context, email string) (*time.Time, error)
+	Lock(ctx context.Context, email string, until time.Time) error
+	IncrementLockouts(ctx context.Context, email string, window time.Duration) (int64, error)
+	Unlock(ctx context.Context, email string) error
+}
+
+type loginAttemptRepository struct {
+	cache *cache.Client
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository backed by Redis.
+func NewLoginAttemptRepository(cache *cache.Client) LoginAttemptRepository {
+	return &loginAttemptRepository{cache: cache}
+}
+
+func (r *loginAttemptRepository) IncrementEmailFailures(ctx context.Context, email string, window time.Duration) (int64, error) {
+	return r.cache.Incr(ctx, cache.CacheKey(emailFailuresPrefix, email), window)
+}
+
+func (r *loginAttemptRepository) IncrementIPFailures(ctx context.Context, ip string, window time.Duration) (int64, error) {
+	return r.cache.Incr(ctx, cache.CacheKey(ipFailuresPrefix, ip), window)
+}
+
+func (r *loginAttemptRepository) GetIPFailures(ctx context.Context, ip string) (int64, error) {
+	var n int64
+	if _, err := r.cache.Get(ctx, cache.CacheKey(ipFailuresPrefix, ip), &n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *loginAttemptRepository) ResetEmailFailures(ctx context.Context, email string) error {
+	return r.cache.Delete(ctx, cache.CacheKey(emailFailuresPrefix, email))
+}
+
+func (r *loginAttemptRepository) ResetIPFailures(ctx context.Context, ip string) error {
+	return r.cache.Delete(ctx, cache.CacheKey(ipFailuresPrefix, ip))
+}
+
+func (r *loginAttemptRepository) GetLockedUntil(ctx context.Context, email string) (*time.Time, error) {
+	var until time.Time
+	found, err := r.cache.Get(ctx, cache.CacheKey(lockoutPrefix, email), &until)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &until, nil
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, email string, until time.Time) error {
+	return r.cache.Set(ctx, cache.CacheKey(lockoutPrefix, email), until, time.Until(until))
+}
+
+// IncrementLockouts counts lockouts within the window; the count drives
+// the escalating lockout duration.
+func (r *loginAttemptRepository) IncrementLockouts(ctx context.Context, email string, window time.Duration) (int64, error) {
+	return r.cache.Incr(ctx, cache.CacheKey(lockoutCountPrefix, email), window)
+}
+
+func (r *loginAttemptRepository) Unlock(ctx context.Context, email string) error {
+	return r.cache.Delete(ctx,
+		cache.CacheKey(lockoutPrefix, email),
+		cache.CacheKey(lockoutCountPrefix, email),
+		cache.CacheKey(emailFailuresPrefix, email),
+	)
+}
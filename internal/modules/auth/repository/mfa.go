@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/pkg/cache"
+	"gorm.io/gorm"
+)
+
+const (
+	mfaChallengeFailuresPrefix = "auth:mfa:challenge:failures"
+	mfaChallengeUsedPrefix     = "auth:mfa:challenge:used"
+	mfaCodeUsedPrefix          = "auth:mfa:code:used"
+)
+
+// MFARepository defines the interface for two-factor authentication data access.
+type MFARepository interface {
+	SaveSecret(ctx context.Context, userID, encryptedSecret string) error
+	Enable(ctx context.Context, userID string, hashes []string) error
+	Disable(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID string) (int64, error)
+	RoleRequiresMFA(ctx context.Context, roleID string) (bool, error)
+
+	IncrementChallengeFailures(ctx context.Context, challengeID string, ttl time.Duration) (int64, error)
+	MarkChallengeUsed(ctx context.Context, challengeID string, ttl time.Duration) (bool, error)
+	IsChallengeUsed(ctx context.Context, challengeID string) (bool, error)
+	MarkCodeUsed(ctx context.Context, userID, code string, ttl time.Duration) (bool, error)
+}
+
+type mfaRepository struct {
+	db    *gorm.DB
+	cache *cache.Client
+}
+
+// NewMFARepository creates a new MFA repository.
+// Enrollment state lives in the database; challenge and replay markers in Redis.
+func NewMFARepository(db *gorm.DB, cache *cache.Client) MFARepository {
+	return &mfaRepository{db: db, cache: cache}
+}
+
+// SaveSecret stores a pending secret. It only takes effect once Enable is called.
+func (r *mfaRepository) SaveSecret(ctx context.Context, userID, encryptedSecret string) error {
+	return r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"mfa_secret": encryptedSecret,
+		"updated_by": userID,
+	}).Error
+}
+
+// Enable turns on 2FA for the user and replaces their recovery codes in one transaction.
+func (r *mfaRepository) Enable(ctx context.Context, userID string, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.User{}).Where("id = ?", userID).Updates(map[string]any{
+			"mfa_enabled":      true,
+			"mfa_confirmed_at": time.Now(),
+			"updated_by":       userID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, hashes)
+	})
+}
+
+// Disable turns off 2FA, clears the secret and deletes all recovery codes.
+func (r *mfaRepository) Disable(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.User{}).Where("id = ?", userID).Updates(map[string]any{
+			"mfa_enabled":      false,
+			"mfa_secret":       nil,
+			"mfa_confirmed_at": nil,
+			"updated_by":       userID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error
+	})
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, hashes)
+	})
+}
+
+// UseRecoveryCode redeems an unused code. It returns false if the code is
+// unknown or was already used.
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *mfaRepository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// RoleRequiresMFA reports whether the role's policy forces 2FA.
+func (r *mfaRepository) RoleRequiresMFA(ctx context.Context, roleID string) (bool, error) {
+	var required []bool
+	err := r.db.WithContext(ctx).Table("sys_roles").
+		Where("id = ? AND deleted_at IS NULL", roleID).
+		Pluck("require_mfa", &required).Error
+	if err != nil || len(required) == 0 {
+		return false, err
+	}
+	return required[0], nil
+}
+
+func (r *mfaRepository) IncrementChallengeFailures(ctx context.Context, challengeID string, ttl time.Duration) (int64, error) {
+	return r.cache.Incr(ctx, cache.CacheKey(mfaChallengeFailuresPrefix, challengeID), ttl)
+}
+
+// MarkChallengeUsed atomically consumes a challenge token.
+// It returns false if the challenge had already been used.
+func (r *mfaRepository) MarkChallengeUsed(ctx context.Context, challengeID string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return r.cache.SetNX(ctx, cache.CacheKey(mfaChallengeUsedPrefix, challengeID), true, ttl)
+}
+
+func (r *mfaRepository) IsChallengeUsed(ctx context.Context, challengeID string) (bool, error) {
+	return r.cache.Exists(ctx, cache.CacheKey(mfaChallengeUsedPrefix, challengeID))
+}
+
+// MarkCodeUsed records an accepted TOTP code so it cannot be replayed within
+// its validity window. It returns false if the code was already used.
+func (r *mfaRepository) MarkCodeUsed(ctx context.Context, userID, code string, ttl time.Duration) (bool, error) {
+	return r.cache.SetNX(ctx, cache.CacheKey(mfaCodeUsedPrefix, userID+":"+code), true, ttl)
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID string, hashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]entity.RecoveryCode, len(hashes))
+	for i, hash := range hashes {
+		codes[i] = entity.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
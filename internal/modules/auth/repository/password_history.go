@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"gorm.io/gorm"
+)
+
+// PasswordHistoryRepository defines the interface for password history data access.
+type PasswordHistoryRepository interface {
+	Recent(ctx context.Context, userID string, limit int) ([]string, error)
+	Add(ctx context.Context, userID, hash string, keep int) error
+}
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepository creates a new password history repository.
+func NewPasswordHistoryRepository(db *gorm.DB) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+// Recent returns the user's latest password hashes, newest first.
+func (r *passwordHistoryRepository) Recent(ctx context.Context, userID string, limit int) ([]string, error) {
+	var hashes []string
+	err := r.db.WithContext(ctx).Model(&entity.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	return hashes, err
+}
+
+// Add records a new password hash and prunes all but the latest keep entries.
+func (r *passwordHistoryRepository) Add(ctx context.Context, userID, hash string, keep int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&entity.PasswordHistory{UserID: userID, PasswordHash: hash}).Error; err != nil {
+			return err
+		}
+
+		keepIDs := tx.Model(&entity.PasswordHistory{}).
+			Select("id").
+			Where("user_id = ?", userID).
+			Order("created_at DESC").
+			Limit(keep)
+		return tx.Where("user_id = ? AND id NOT IN (?)", userID, keepIDs).Delete(&entity.PasswordHistory{}).Error
+	})
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/pkg/cache"
+)
+
+const (
+	passwordResetPrefix     = "auth:password_reset:token"
+	passwordResetUserPrefix = "auth:password_reset:user"
+	passwordResetUsedPrefix = "auth:password_reset:used"
+)
+
+// PasswordResetRepository defines the interface for password reset tokens.
+// Only a hash of each token is stored.
+type PasswordResetRepository interface {
+	Save(ctx context.Context, tokenHash, userID string, ttl time.Duration) error
+	Consume(ctx context.Context, tokenHash string, ttl time.Duration) (string, error)
+}
+
+type passwordResetRepository struct {
+	cache *cache.Client
+}
+
+// NewPasswordResetRepository creates a new password reset repository backed by Redis.
+func NewPasswordResetRepository(cache *cache.Client) PasswordResetRepository {
+	return &passwordResetRepository{cache: cache}
+}
+
+// Save stores a reset token for the user. Only the latest token per user
+// stays valid; issuing a new one supersedes the previous.
+func (r *passwordResetRepository) Save(ctx context.Context, tokenHash, userID string, ttl time.Duration) error {
+	if err := r.cache.Set(ctx, cache.CacheKey(passwordResetPrefix, tokenHash), userID, ttl); err != nil {
+		return err
+	}
+	return r.cache.Set(ctx, cache.CacheKey(passwordResetUserPrefix, userID), tokenHash, ttl)
+}
+
+// Consume redeems a reset token exactly once and returns its user ID.
+// An empty user ID means the token is unknown, expired, superseded or
+// already used.
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenHash string, ttl time.Duration) (string, error) {
+	var userID string
+	found, err := r.cache.Get(ctx, cache.CacheKey(passwordResetPrefix, tokenHash), &userID)
+	if err != nil || !found {
+		return "", err
+	}
+
+	var latest string
+	found, err = r.cache.Get(ctx, cache.CacheKey(passwordResetUserPrefix, userID), &latest)
+	if err != nil || !found || latest != tokenHash {
+		return "", err
+	}
+
+	fresh, err := r.cache.SetNX(ctx, cache.CacheKey(passwordResetUsedPrefix, tokenHash), true, ttl)
+	if err != nil || !fresh {
+		return "", err
+	}
+
+	if err := r.cache.Delete(ctx,
+		cache.CacheKey(passwordResetPrefix, tokenHash),
+		cache.CacheKey(passwordResetUserPrefix, userID),
+	); err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/pkg/cache"
+	"gorm.io/gorm"
+)
+
+const (
+	permissionCachePrefix = "auth:permissions"
+	permissionCacheTTL    = 5 * time.Minute
+)
+
+// PermissionRepository defines the interface for resolving role permissions.
+type PermissionRepository interface {
+	ListCodesByRole(ctx context.Context, roleID, subRoleID string) ([]string, error)
+}
+
+type permissionRepository struct {
+	db    *gorm.DB
+	cache *cache.Client
+}
+
+// NewPermissionRepository creates a new permission repository.
+// Results are cached in Redis per role/sub-role pair so the guard
+// doesn't hit the database on every request.
+func NewPermissionRepository(db *gorm.DB, cache *cache.Client) PermissionRepository {
+	return &permissionRepository{db: db, cache: cache}
+}
+
+func (r *permissionRepository) ListCodesByRole(ctx context.Context, roleID, subRoleID string) ([]string, error) {
+	cacheKey := cache.CacheKey(permissionCachePrefix, roleID+":"+subRoleID)
+
+	var codes []string
+	if r.cache != nil {
+		if found, err := r.cache.Get(ctx, cacheKey, &codes); err == nil && found {
+			return codes, nil
+		}
+	}
+
+	query := r.db.WithContext(ctx).
+		Table("sys_role_permissions rp").
+		Joins("JOIN sys_permissions p ON p.id = rp.permission_id AND p.deleted_at IS NULL").
+		Joins("JOIN sys_roles r ON r.id = rp.role_id AND r.deleted_at IS NULL AND r.is_active").
+		Where("rp.role_id = ?", roleID)
+
+	if subRoleID != "" {
+		query = query.Where("rp.sub_role_id IS NULL OR rp.sub_role_id = ?", subRoleID)
+	} else {
+		query = query.Where("rp.sub_role_id IS NULL")
+	}
+
+	codes = []string{}
+	if err := query.Distinct().Order("p.code").Pluck("p.code", &codes).Error; err != nil {
+		return nil, err
+	}
+
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, codes, permissionCacheTTL)
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"gorm.io/gorm"
+)
+
+// SecurityEventFilter narrows a security event search. Empty fields are ignored.
+type SecurityEventFilter struct {
+	EventType string
+	UserID    string
+	Email     string
+	IPAddress string
+	From      *time.Time
+	To        *time.Time
+}
+
+// SecurityEventRepository defines the interface for security event data access.
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *entity.SecurityEvent) error
+	List(ctx context.Context, filter SecurityEventFilter, params *query.Params) ([]*entity.SecurityEvent, int64, error)
+}
+
+type securityEventRepository struct {
+	db *gorm.DB
+}
+
+// NewSecurityEventRepository creates a new security event repository.
+func NewSecurityEventRepository(db *gorm.DB) SecurityEventRepository {
+	return &securityEventRepository{db: db}
+}
+
+func (r *securityEventRepository) Create(ctx context.Context, event *entity.SecurityEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *securityEventRepository) List(ctx context.Context, filter SecurityEventFilter, params *query.Params) ([]*entity.SecurityEvent, int64, error) {
+	var events []*entity.SecurityEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.SecurityEvent{})
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Email != "" {
+		query = query.Where("email = ?", filter.Email)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	if err := params.Count(query, &total); err != nil {
+		return nil, 0, err
+	}
+
+	if err := params.Find(query, &events); err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/pkg/cache"
+	"gorm.io/gorm"
+)
+
+const (
+	apiKeyPrefix     = "auth:apikey"
+	apiKeyUsedPrefix = "auth:apikey:used"
+)
+
+// CachedAPIKey is the subset of an active key needed to authenticate a request.
+type CachedAPIKey struct {
+	ID               string     `json:"id"`
+	ServiceAccountID string     `json:"service_account_id"`
+	KeyHash          string     `json:"key_hash"`
+	Scopes           []string   `json:"scopes"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+}
+
+// ServiceAccountRepository defines the interface for service account and API key data access.
+type ServiceAccountRepository interface {
+	Create(ctx context.Context, account *entity.ServiceAccount) error
+	GetByID(ctx context.Context, id string) (*entity.ServiceAccount, error)
+	List(ctx context.Context, params *query.Params) ([]*entity.ServiceAccount, int64, error)
+	Update(ctx context.Context, account *entity.ServiceAccount) error
+	Delete(ctx context.Context, id, deletedBy string) error
+	NameTaken(ctx context.Context, name, excludeID string) (bool, error)
+
+	CreateKey(ctx context.Context, key *entity.APIKey) error
+	RotateKey(ctx context.Context, key *entity.APIKey, oldKeyID string, oldExpiresAt time.Time) error
+	GetKey(ctx context.Context, accountID, keyID string) (*entity.APIKey, error)
+	GetKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+	ListKeys(ctx context.Context, accountID string) ([]*entity.APIKey, error)
+	ListStaleKeys(ctx context.Context, unusedSince time.Time, params *query.Params) ([]*entity.APIKey, int64, error)
+	RevokeKey(ctx context.Context, keyID string) error
+	TouchKey(ctx context.Context, keyID string, at time.Time) error
+
+	GetCachedKey(ctx context.Context, prefix string) (*CachedAPIKey, bool, error)
+	CacheActiveKey(ctx context.Context, prefix string, key *CachedAPIKey, ttl time.Duration) error
+	UncacheKeys(ctx context.Context, prefixes ...string) error
+	ShouldTouchKey(ctx context.Context, keyID string, interval time.Duration) (bool, error)
+}
+
+type serviceAccountRepository struct {
+	db    *gorm.DB
+	cache *cache.Client
+}
+
+// NewServiceAccountRepository creates a new service account repository.
+// Accounts and keys live in the database; active keys are cached in Redis
+// by prefix because they are looked up on every API key request.
+func NewServiceAccountRepository(db *gorm.DB, cache *cache.Client) ServiceAccountRepository {
+	return &serviceAccountRepository{db: db, cache: cache}
+}
+
+func (r *serviceAccountRepository) Create(ctx context.Context, account *entity.ServiceAccount) error {
+	return r.db.WithContext(ctx).Create(account).Error
+}
+
+func (r *serviceAccountRepository) GetByID(ctx context.Context, id string) (*entity.ServiceAccount, error) {
+	var account entity.ServiceAccount
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *serviceAccountRepository) List(ctx context.Context, params *query.Params) ([]*entity.ServiceAccount, int64, error) {
+	var accounts []*entity.ServiceAccount
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.ServiceAccount{})
+
+	if err := params.Count(query, &total); err != nil {
+		return nil, 0, err
+	}
+
+	if err := params.Find(query, &accounts); err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, total, nil
+}
+
+func (r *serviceAccountRepository) Update(ctx context.Context, account *entity.ServiceAccount) error {
+	return r.db.WithContext(ctx).Save(account).Error
+}
+
+// Delete soft deletes the account, stamping updated_by, and revokes all of its keys.
+func (r *serviceAccountRepository) Delete(ctx context.Context, id, deletedBy string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.ServiceAccount{}).Where("id = ?", id).Update("updated_by", deletedBy).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.APIKey{}).
+			Where("service_account_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&entity.ServiceAccount{}).Error
+	})
+}
+
+// NameTaken reports whether another active account already uses the name.
+func (r *serviceAccountRepository) NameTaken(ctx context.Context, name, excludeID string) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&entity.ServiceAccount{}).Where("LOWER(name) = LOWER(?)", name)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *serviceAccountRepository) CreateKey(ctx context.Context, key *entity.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// RotateKey stores the replacement key and shortens the old key's lifetime
+// to the grace period in one transaction.
+func (r *serviceAccountRepository) RotateKey(ctx context.Context, key *entity.APIKey, oldKeyID string, oldExpiresAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]any{"expires_at": oldExpiresAt}
+		if !oldExpiresAt.After(time.Now()) {
+			updates["revoked_at"] = time.Now()
+		}
+		return tx.Model(&entity.APIKey{}).Where("id = ?", oldKeyID).Updates(updates).Error
+	})
+}
+
+func (r *serviceAccountRepository) GetKey(ctx context.Context, accountID, keyID string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	if err := r.db.WithContext(ctx).Where("id = ? AND service_account_id = ?", keyID, accountID).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *serviceAccountRepository) GetKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListKeys returns all keys of the account, newest first, including revoked ones.
+func (r *serviceAccountRepository) ListKeys(ctx context.Context, accountID string) ([]*entity.APIKey, error) {
+	var keys []*entity.APIKey
+	err := r.db.WithContext(ctx).
+		Where("service_account_id = ?", accountID).
+		Order("created_at DESC, id ASC").
+		Find(&keys).Error
+	return keys, err
+}
+
+// ListStaleKeys returns unrevoked keys not used since the given time
+// (or never used and issued before it), least recently used first.
+func (r *serviceAccountRepository) ListStaleKeys(ctx context.Context, unusedSince time.Time, params *query.Params) ([]*entity.APIKey, int64, error) {
+	var keys []*entity.APIKey
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.APIKey{}).
+		Where("revoked_at IS NULL AND COALESCE(last_used_at, created_at) < ?", unusedSince)
+
+	if err := params.Count(query, &total); err != nil {
+		return nil, 0, err
+	}
+
+	if err := params.Find(query, &keys); err != nil {
+		return nil, 0, err
+	}
+
+	return keys, total, nil
+}
+
+func (r *serviceAccountRepository) RevokeKey(ctx context.Context, keyID string) error {
+	return r.db.WithContext(ctx).Model(&entity.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *serviceAccountRepository) TouchKey(ctx context.Context, keyID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.APIKey{}).Where("id = ?", keyID).Update("last_used_at", at).Error
+}
+
+func (r *serviceAccountRepository) GetCachedKey(ctx context.Context, prefix string) (*CachedAPIKey, bool, error) {
+	var key CachedAPIKey
+	found, err := r.cache.Get(ctx, cache.CacheKey(apiKeyPrefix, prefix), &key)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	return &key, true, nil
+}
+
+func (r *serviceAccountRepository) CacheActiveKey(ctx context.Context, prefix string, key *CachedAPIKey, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return r.cache.Set(ctx, cache.CacheKey(apiKeyPrefix, prefix), key, ttl)
+}
+
+func (r *serviceAccountRepository) UncacheKeys(ctx context.Context, prefixes ...string) error {
+	if len(prefixes) == 0 {
+		return nil
+	}
+	keys := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		keys[i] = cache.CacheKey(apiKeyPrefix, prefix)
+	}
+	return r.cache.Delete(ctx, keys...)
+}
+
+// ShouldTouchKey throttles last_used_at writes to one per interval per key.
+func (r *serviceAccountRepository) ShouldTouchKey(ctx context.Context, keyID string, interval time.Duration) (bool, error) {
+	return r.cache.SetNX(ctx, cache.CacheKey(apiKeyUsedPrefix, keyID), true, interval)
+}
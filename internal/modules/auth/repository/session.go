@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/pkg/cache"
+	"gorm.io/gorm"
+)
+
+const (
+	activeSessionPrefix = "auth:session"
+	sessionSeenPrefix   = "auth:session:seen"
+)
+
+// SessionRepository defines the interface for signed-in device data access.
+type SessionRepository interface {
+	Create(ctx context.Context, session *entity.Session) error
+	GetByID(ctx context.Context, id string) (*entity.Session, error)
+	ListActive(ctx context.Context, userID string) ([]entity.Session, error)
+	Rotate(ctx context.Context, id, tokenID, ipAddress, userAgent string, expiresAt time.Time) error
+	MarkSeen(ctx context.Context, id string, at time.Time) error
+	Revoke(ctx context.Context, ids []string, reason string) error
+
+	CacheActive(ctx context.Context, id, userID string, ttl time.Duration) error
+	GetCachedOwner(ctx context.Context, id string) (string, bool, error)
+	Uncache(ctx context.Context, ids ...string) error
+	ShouldMarkSeen(ctx context.Context, id string, interval time.Duration) (bool, error)
+}
+
+type sessionRepository struct {
+	db    *gorm.DB
+	cache *cache.Client
+}
+
+// NewSessionRepository creates a new session repository.
+// Sessions are stored in the database; active session IDs are mirrored in
+// Redis so the JWT middleware can check them on every request.
+func NewSessionRepository(db *gorm.DB, cache *cache.Client) SessionRepository {
+	return &sessionRepository{db: db, cache: cache}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id string) (*entity.Session, error) {
+	var session entity.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActive returns the user's unrevoked, unexpired sessions, most recently used first.
+func (r *sessionRepository) ListActive(ctx context.Context, userID string) ([]entity.Session, error) {
+	var sessions []entity.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("COALESCE(last_seen_at, created_at) DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Rotate records a refresh: the new access token, client details and expiry.
+func (r *sessionRepository) Rotate(ctx context.Context, id, tokenID, ipAddress, userAgent string, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.Session{}).Where("id = ?", id).Updates(map[string]any{
+		"token_id":     tokenID,
+		"ip_address":   ipAddress,
+		"user_agent":   userAgent,
+		"last_seen_at": time.Now(),
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+func (r *sessionRepository) MarkSeen(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.Session{}).Where("id = ?", id).Update("last_seen_at", at).Error
+}
+
+// Revoke ends the given sessions. Already revoked sessions keep their original reason.
+func (r *sessionRepository) Revoke(ctx context.Context, ids []string, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&entity.Session{}).
+		Where("id IN ? AND revoked_at IS NULL", ids).
+		Updates(map[string]any{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error
+}
+
+func (r *sessionRepository) CacheActive(ctx context.Context, id, userID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return r.cache.Set(ctx, cache.CacheKey(activeSessionPrefix, id), userID, ttl)
+}
+
+// GetCachedOwner returns the user ID of an active session, or false on a cache miss.
+func (r *sessionRepository) GetCachedOwner(ctx context.Context, id string) (string, bool, error) {
+	var userID string
+	found, err := r.cache.Get(ctx, cache.CacheKey(activeSessionPrefix, id), &userID)
+	return userID, found, err
+}
+
+func (r *sessionRepository) Uncache(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = cache.CacheKey(activeSessionPrefix, id)
+	}
+	return r.cache.Delete(ctx, keys...)
+}
+
+// ShouldMarkSeen throttles last_seen_at writes to one per interval per session.
+func (r *sessionRepository) ShouldMarkSeen(ctx context.Context, id string, interval time.Duration) (bool, error) {
+	return r.cache.SetNX(ctx, cache.CacheKey(sessionSeenPrefix, id), true, interval)
+}
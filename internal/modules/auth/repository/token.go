@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/pkg/cache"
+)
+
+const (
+	revokedTokenPrefix  = "auth:revoked:token"
+	revokedFamilyPrefix = "auth:revoked:family"
+	usedRefreshPrefix   = "auth:refresh:used"
+)
+
+// TokenRepository defines the interface for server-side token state.
+type TokenRepository interface {
+	RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string, ttl time.Duration) error
+	IsFamilyRevoked(ctx context.Context, familyID string) (bool, error)
+	MarkRefreshUsed(ctx context.Context, tokenID string, ttl time.Duration) (bool, error)
+}
+
+type tokenRepository struct {
+	cache *cache.Client
+}
+
+// NewTokenRepository creates a new token repository backed by Redis.
+func NewTokenRepository(cache *cache.Client) TokenRepository {
+	return &tokenRepository{cache: cache}
+}
+
+func (r *tokenRepository) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil // Already expired, nothing to revoke
+	}
+	return r.cache.Set(ctx, cache.CacheKey(revokedTokenPrefix, tokenID), true, ttl)
+}
+
+func (r *tokenRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return r.cache.Exists(ctx, cache.CacheKey(revokedTokenPrefix, tokenID))
+}
+
+func (r *tokenRepository) RevokeFamily(ctx context.Context, familyID string, ttl time.Duration) error {
+	return r.cache.Set(ctx, cache.CacheKey(revokedFamilyPrefix, familyID), true, ttl)
+}
+
+func (r *tokenRepository) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	return r.cache.Exists(ctx, cache.CacheKey(revokedFamilyPrefix, familyID))
+}
+
+// MarkRefreshUsed atomically flags a refresh token as consumed.
+// It returns false if the token had already been used before.
+func (r *tokenRepository) MarkRefreshUsed(ctx context.Context, tokenID string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return r.cache.SetNX(ctx, cache.CacheKey(usedRefreshPrefix, tokenID), true, ttl)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
 	"gorm.io/gorm"
 )
 
@@ -13,8 +14,18 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	Create(ctx context.Context, user *entity.User) error
 	Update(ctx context.Context, user *entity.User) error
-	Delete(ctx context.Context, id string) error
-	List(ctx context.Context, offset, limit int) ([]*entity.User, int64, error)
+	Delete(ctx context.Context, id, deletedBy string) error
+	List(ctx context.Context, filter UserFilter, params *query.Params) ([]*entity.User, int64, error)
+	EmailTaken(ctx context.Context, email, excludeID string) (bool, error)
+}
+
+// UserFilter narrows a user search. Empty fields are ignored.
+type UserFilter struct {
+	RoleID     string
+	SubRoleID  string
+	BranchID   string
+	DivisionID string
+	IsActive   *bool
 }
 
 type userRepository struct {
@@ -50,21 +61,58 @@ func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
-func (r *userRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Delete(&entity.User{}, "id = ?", id).Error
+// Delete soft-deletes the user, stamping updated_by with the caller.
+func (r *userRepository) Delete(ctx context.Context, id, deletedBy string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.User{}).Where("id = ?", id).Update("updated_by", deletedBy).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.User{}, "id = ?", id).Error
+	})
 }
 
-func (r *userRepository) List(ctx context.Context, offset, limit int) ([]*entity.User, int64, error) {
+func (r *userRepository) List(ctx context.Context, filter UserFilter, params *query.Params) ([]*entity.User, int64, error) {
 	var users []*entity.User
 	var total int64
 
-	if err := r.db.WithContext(ctx).Model(&entity.User{}).Count(&total).Error; err != nil {
+	query := r.db.WithContext(ctx).Model(&entity.User{})
+	if filter.RoleID != "" {
+		query = query.Where("role_id = ?", filter.RoleID)
+	}
+	if filter.SubRoleID != "" {
+		query = query.Where("sub_role_id = ?", filter.SubRoleID)
+	}
+	if filter.BranchID != "" {
+		query = query.Where("branch_id = ?", filter.BranchID)
+	}
+	if filter.DivisionID != "" {
+		query = query.Where("division_id = ?", filter.DivisionID)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+
+	if err := params.Count(query, &total); err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+	if err := params.Find(query, &users); err != nil {
 		return nil, 0, err
 	}
 
 	return users, total, nil
 }
+
+// EmailTaken reports whether another user, including a soft-deleted one,
+// already holds the email. sys_users.email is unique across deleted rows.
+func (r *userRepository) EmailTaken(ctx context.Context, email, excludeID string) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Unscoped().Model(&entity.User{}).Where("LOWER(email) = LOWER(?)", email)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -2,6 +2,7 @@ package seeder
 
 import (
 	"os"
+	"time"
 
 	"github.com/user/go-boilerplate/internal/modules/auth/entity"
 	"github.com/user/go-boilerplate/pkg/logger"
@@ -28,16 +29,35 @@ func (s *Seeder) Seed() error {
 	return s.seedSampleUsers()
 }
 
+// Role and sub-role assigned to the admin user ("Admin" / "Admin IT" in the
+// system module's sys_roles.sql and sys_sub_roles.sql seeders).
+const (
+	adminRoleID    = "16087e36-6a42-54d4-845d-048fc1e73ada"
+	adminSubRoleID = "6f64c8bf-d9e6-5392-997b-8d884d19be3b"
+)
+
 func (s *Seeder) seedAdminUser() error {
+	roleID, subRoleID := adminRoleID, adminSubRoleID
+
 	var existing entity.User
 	if err := s.db.Where("email = ?", "admin@example.com").First(&existing).Error; err == nil {
+		if existing.RoleID == nil {
+			existing.RoleID, existing.SubRoleID = &roleID, &subRoleID
+			s.db.Save(&existing)
+			logger.Log.Info("Admin user assigned to Admin role")
+			return nil
+		}
 		logger.Log.Info("Admin user already exists, skipping")
 		return nil
 	}
 
+	// The default password is on the common-password list, so the admin
+	// has to replace it at first login
 	adminPassword := os.Getenv("ADMIN_PASSWORD")
+	mustChange := false
 	if adminPassword == "" {
 		adminPassword = "Admin@123456"
+		mustChange = true
 		logger.Log.Warn("Using default admin password - CHANGE IN PRODUCTION!")
 	}
 
@@ -47,13 +67,17 @@ func (s *Seeder) seedAdminUser() error {
 	}
 
 	admin := &entity.User{
-		Email:    "admin@example.com",
-		Password: string(hashedPassword),
-		FullName: "System Administrator",
-		IsActive: true,
+		Email:     "admin@example.com",
+		Password:  string(hashedPassword),
+		FullName:  "System Administrator",
+		RoleID:    &roleID,
+		SubRoleID: &subRoleID,
+		IsActive:  true,
+
+		MustChangePassword: mustChange,
 	}
 
-	if err := s.db.Create(admin).Error; err != nil {
+	if err := s.createUser(admin); err != nil {
 		return err
 	}
 
@@ -70,8 +94,8 @@ func (s *Seeder) seedSampleUsers() error {
 		Email, FullName, Password string
 		IsActive                  bool
 	}{
-		{"john.doe@example.com", "John Doe", "password123", true},
-		{"jane.smith@example.com", "Jane Smith", "password123", true},
+		{"john.doe@example.com", "John Doe", "Sample-User-2024", true},
+		{"jane.smith@example.com", "Jane Smith", "Sample-User-2024", true},
 	}
 
 	for _, u := range users {
@@ -87,9 +111,23 @@ func (s *Seeder) seedSampleUsers() error {
 			FullName: u.FullName,
 			IsActive: u.IsActive,
 		}
-		s.db.Create(user)
+		s.createUser(user)
 	}
 
 	logger.Log.Info("Sample users seeded")
 	return nil
 }
+
+// createUser inserts a user and records its password in the history so
+// it cannot be reused at the first change.
+func (s *Seeder) createUser(user *entity.User) error {
+	now := time.Now()
+	user.PasswordChangedAt = &now
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entity.PasswordHistory{UserID: user.ID, PasswordHash: user.Password}).Error
+	})
+}
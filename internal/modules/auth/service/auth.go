@@ -2,51 +2,216 @@ package service
 
 import (
 	"context"
+	"net/http"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/user/go-boilerplate/internal/modules/auth/dto"
 	"github.com/user/go-boilerplate/internal/modules/auth/entity"
 	"github.com/user/go-boilerplate/internal/modules/auth/repository"
 	"github.com/user/go-boilerplate/pkg/apperror"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/token"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-// JWTClaims represents the JWT claims structure.
-type JWTClaims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	jwt.RegisteredClaims
-}
-
 // AuthService defines the authentication service interface.
 type AuthService interface {
-	Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error)
-	Register(ctx context.Context, req *dto.RegisterRequest) (*dto.UserResponse, error)
+	Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error)
+	LoginExternal(ctx context.Context, user *entity.User, method string, client dto.ClientInfo) (*dto.LoginResponse, error)
+	VerifyMFA(ctx context.Context, req *dto.MFAVerifyRequest) (*dto.AuthResponse, error)
+	Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.AuthResponse, error)
+	Logout(ctx context.Context, req *dto.LogoutRequest) error
 	GetMe(ctx context.Context, userID string) (*dto.UserResponse, error)
+	IsRevoked(ctx context.Context, tokenID, familyID string) (bool, error)
+	ValidateSession(ctx context.Context, sessionID, userID string) (bool, error)
+	ResolvePermissions(ctx context.Context, roleID, subRoleID string) ([]string, error)
+
+	// Two-factor enrollment for signed-in users
+	BeginMFAEnrollment(ctx context.Context, userID string) (*dto.MFAEnrollmentResponse, error)
+	ConfirmMFAEnrollment(ctx context.Context, userID string, req *dto.MFACodeRequest) (*dto.MFARecoveryCodesResponse, error)
+	DisableMFA(ctx context.Context, userID string, req *dto.MFADisableRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID string, req *dto.MFACodeRequest) (*dto.MFARecoveryCodesResponse, error)
+
+	// Signed-in devices
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]dto.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID string, client dto.ClientInfo) error
+	RevokeAllSessions(ctx context.Context, actorID, userID string, client dto.ClientInfo) (*dto.RevokeSessionsResponse, error)
+	RevokeUserSessions(ctx context.Context, userID, keepID, reason string) error
+
+	// Forced enrollment with a login challenge token
+	SetupMFA(ctx context.Context, req *dto.MFASetupRequest) (*dto.MFAEnrollmentResponse, error)
+	ConfirmMFASetup(ctx context.Context, req *dto.MFASetupConfirmRequest) (*dto.MFASetupResponse, error)
+}
+
+// TokenConfig holds token signing and lifetime settings.
+type TokenConfig struct {
+	Keys          *token.KeySet
+	AccessExpiry  time.Duration
+	RefreshExpiry time.Duration
 }
 
 type authService struct {
-	userRepo  repository.UserRepository
-	jwtSecret string
-	jwtExpiry time.Duration
+	userRepo       repository.UserRepository
+	tokenRepo      repository.TokenRepository
+	permissionRepo repository.PermissionRepository
+	attemptRepo    repository.LoginAttemptRepository
+	eventRepo      repository.SecurityEventRepository
+	mfaRepo        repository.MFARepository
+	sessionRepo    repository.SessionRepository
+	passwords      *PasswordManager
+	verifier       CredentialVerifier
+	tokens         TokenConfig
+	loginPolicy    LoginPolicy
+	mfa            MFAConfig
+	sessions       SessionPolicy
 }
 
 // NewAuthService creates a new auth service.
-func NewAuthService(userRepo repository.UserRepository, jwtSecret string, jwtExpiry time.Duration) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	permissionRepo repository.PermissionRepository,
+	attemptRepo repository.LoginAttemptRepository,
+	eventRepo repository.SecurityEventRepository,
+	mfaRepo repository.MFARepository,
+	sessionRepo repository.SessionRepository,
+	passwords *PasswordManager,
+	verifier CredentialVerifier,
+	tokens TokenConfig,
+	loginPolicy LoginPolicy,
+	mfa MFAConfig,
+	sessions SessionPolicy,
+) AuthService {
 	return &authService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		jwtExpiry: jwtExpiry,
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		permissionRepo: permissionRepo,
+		attemptRepo:    attemptRepo,
+		eventRepo:      eventRepo,
+		mfaRepo:        mfaRepo,
+		sessionRepo:    sessionRepo,
+		passwords:      passwords,
+		verifier:       verifier,
+		tokens:         tokens,
+		loginPolicy:    loginPolicy,
+		mfa:            mfa,
+		sessions:       sessions,
+	}
+}
+
+// Login checks the password against the configured credential backends.
+// Users with 2FA enabled, or whose role forces 2FA, receive an MFA challenge
+// instead of a token pair.
+func (s *authService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error) {
+	email := normalizeEmail(req.Email)
+
+	if err := s.checkLoginAllowed(ctx, email, req.ClientInfo); err != nil {
+		return nil, err
+	}
+
+	// The password is checked first so a deactivated account is only
+	// reported to someone who knows its password
+	user, err := s.verifier.Verify(ctx, req.Email, req.Password)
+	switch err {
+	case nil:
+	case errUnknownAccount:
+		return nil, s.loginFailed(ctx, email, nil, "unknown_email", req.ClientInfo)
+	case errInvalidPassword:
+		var userID *string
+		if user != nil {
+			userID = &user.ID
+		}
+		return nil, s.loginFailed(ctx, email, userID, "invalid_password", req.ClientInfo)
+	default:
+		return nil, err
+	}
+
+	if !user.IsActive {
+		s.recordEvent(ctx, entity.SecurityEventLoginFailure, "account_inactive", &user.ID, email, nil, req.ClientInfo)
+		return nil, apperror.Forbidden("Account is deactivated")
+	}
+
+	challenge, err := s.mfaChallenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &dto.LoginResponse{MFAChallengeResponse: challenge}, nil
+	}
+
+	s.loginSucceeded(ctx, email, user.ID, "", req.ClientInfo)
+
+	tokens, err := s.startSession(ctx, user, req.ClientInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginResponse{AuthResponse: tokens}, nil
+}
+
+// LoginExternal signs in a user whose identity was verified by an external
+// provider such as OIDC. Local 2FA still applies; method is recorded as the
+// reason of the login_success event.
+func (s *authService) LoginExternal(ctx context.Context, user *entity.User, method string, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	email := normalizeEmail(user.Email)
+
+	if !user.IsActive {
+		s.recordEvent(ctx, entity.SecurityEventLoginFailure, "account_inactive", &user.ID, email, nil, client)
+		return nil, apperror.Forbidden("Account is deactivated")
+	}
+
+	challenge, err := s.mfaChallenge(ctx, user)
+	if err != nil {
+		return nil, err
 	}
+	if challenge != nil {
+		return &dto.LoginResponse{MFAChallengeResponse: challenge}, nil
+	}
+
+	s.loginSucceeded(ctx, email, user.ID, method, client)
+
+	tokens, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginResponse{AuthResponse: tokens}, nil
 }
 
-func (s *authService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
-	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+func (s *authService) Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.AuthResponse, error) {
+	claims, err := s.parseToken(req.RefreshToken)
+	if err != nil || claims.TokenType != token.TypeRefresh || claims.FamilyID == "" {
+		return nil, apperror.New(apperror.ErrCodeInvalidToken, "Invalid refresh token", http.StatusUnauthorized)
+	}
+
+	revoked, err := s.tokenRepo.IsFamilyRevoked(ctx, claims.FamilyID)
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+	}
+	if revoked {
+		return nil, apperror.New(apperror.ErrCodeTokenRevoked, "Refresh token has been revoked", http.StatusUnauthorized)
+	}
+
+	// Each refresh token may be exchanged exactly once. A second use means the
+	// token leaked, so the whole family (every session descendant) is killed.
+	fresh, err := s.tokenRepo.MarkRefreshUsed(ctx, claims.ID, time.Until(claims.ExpiresAt.Time))
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+	}
+	if !fresh {
+		if err := s.revokeSessions(ctx, []string{claims.FamilyID}, entity.SessionRevokedRefreshReuse); err != nil {
+			return nil, err
+		}
+		logger.Warn(ctx, "Refresh token reuse detected, token family revoked",
+			zap.String("user_id", claims.UserID),
+			zap.String("family_id", claims.FamilyID),
+		)
+		return nil, apperror.New(apperror.ErrCodeTokenRevoked, "Refresh token reuse detected", http.StatusUnauthorized)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, apperror.Unauthorized("Invalid email or password")
+			return nil, apperror.New(apperror.ErrCodeInvalidToken, "Invalid refresh token", http.StatusUnauthorized)
 		}
 		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
 	}
@@ -55,60 +220,59 @@ func (s *authService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 		return nil, apperror.Forbidden("Account is deactivated")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, apperror.Unauthorized("Invalid email or password")
+	session, err := s.sessionRepo.GetByID(ctx, claims.FamilyID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch session", 500)
 	}
-
-	expiresAt := time.Now().Add(s.jwtExpiry)
-	claims := &JWTClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	if session == nil || session.UserID != user.ID || !sessionActive(session) {
+		return nil, apperror.New(apperror.ErrCodeTokenRevoked, "Session has ended", http.StatusUnauthorized)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+	tokens, tokenID, err := s.issueTokenPair(user, claims.FamilyID)
 	if err != nil {
-		return nil, apperror.Internal("Failed to generate token")
+		return nil, err
 	}
 
-	return &dto.AuthResponse{
-		Token:     tokenString,
-		ExpiresAt: expiresAt.Unix(),
-	}, nil
+	expiresAt := time.Unix(tokens.RefreshExpiresAt, 0)
+	userAgent := truncate(req.UserAgent, maxUserAgentLength)
+	if err := s.sessionRepo.Rotate(ctx, session.ID, tokenID, req.IPAddress, userAgent, expiresAt); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to update session", 500)
+	}
+	if err := s.sessionRepo.CacheActive(ctx, session.ID, user.ID, s.tokens.RefreshExpiry); err != nil {
+		logger.Warn(ctx, "Failed to cache session", zap.String("session_id", session.ID), zap.Error(err))
+	}
+
+	return tokens, nil
 }
 
-func (s *authService) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.UserResponse, error) {
-	existing, _ := s.userRepo.GetByEmail(ctx, req.Email)
-	if existing != nil {
-		return nil, apperror.Conflict("Email already registered")
+func (s *authService) Logout(ctx context.Context, req *dto.LogoutRequest) error {
+	if req.TokenID != "" {
+		if err := s.tokenRepo.RevokeToken(ctx, req.TokenID, time.Until(req.ExpiresAt)); err != nil {
+			return apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+		}
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, apperror.Internal("Failed to hash password")
+	if req.FamilyID != "" {
+		return s.revokeSessions(ctx, []string{req.FamilyID}, entity.SessionRevokedLogout)
 	}
 
-	user := &entity.User{
-		Email:    req.Email,
-		Password: string(hashedPassword),
-		FullName: req.FullName,
-		IsActive: true,
+	return nil
+}
+
+// IsRevoked implements middleware.RevocationChecker.
+func (s *authService) IsRevoked(ctx context.Context, tokenID, familyID string) (bool, error) {
+	if tokenID != "" {
+		revoked, err := s.tokenRepo.IsTokenRevoked(ctx, tokenID)
+		if err != nil || revoked {
+			return revoked, err
+		}
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create user", 500)
+	if familyID != "" {
+		return s.tokenRepo.IsFamilyRevoked(ctx, familyID)
 	}
 
-	return &dto.UserResponse{
-		ID:       user.ID,
-		Email:    user.Email,
-		FullName: user.FullName,
-		IsActive: user.IsActive,
-	}, nil
+	return false, nil
 }
 
 func (s *authService) GetMe(ctx context.Context, userID string) (*dto.UserResponse, error) {
@@ -120,10 +284,30 @@ func (s *authService) GetMe(ctx context.Context, userID string) (*dto.UserRespon
 		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
 	}
 
+	permissions, err := s.ResolvePermissions(ctx, derefString(user.RoleID), derefString(user.SubRoleID))
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to resolve permissions", 500)
+	}
+
 	return &dto.UserResponse{
-		ID:       user.ID,
-		Email:    user.Email,
-		FullName: user.FullName,
-		IsActive: user.IsActive,
+		ID:          user.ID,
+		Email:       user.Email,
+		FullName:    user.FullName,
+		IsActive:    user.IsActive,
+		RoleID:      user.RoleID,
+		SubRoleID:   user.SubRoleID,
+		Permissions: permissions,
+		MFAEnabled:  user.MFAEnabled,
+
+		PasswordChangeRequired: s.passwords.ChangeRequired(user),
+		PasswordExpiresAt:      s.passwords.ExpiresAt(user),
 	}, nil
 }
+
+// ResolvePermissions implements middleware.PermissionResolver.
+func (s *authService) ResolvePermissions(ctx context.Context, roleID, subRoleID string) ([]string, error) {
+	if roleID == "" {
+		return []string{}, nil
+	}
+	return s.permissionRepo.ListCodesByRole(ctx, roleID, subRoleID)
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/directory"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Credential backends selectable with AUTH_BACKENDS.
+const (
+	BackendLocal = "local" // bcrypt hashes in sys_users
+	BackendLDAP  = "ldap"  // LDAP / Active Directory bind
+)
+
+var (
+	errUnknownAccount  = errors.New("unknown account")
+	errInvalidPassword = errors.New("invalid password")
+)
+
+// CredentialVerifier checks an email and password and returns the local
+// account. It returns errUnknownAccount when the backend has no such
+// account and errInvalidPassword, together with the account when it is
+// known locally, for a wrong password. Account status is checked by the
+// caller.
+type CredentialVerifier interface {
+	Verify(ctx context.Context, email, password string) (*entity.User, error)
+}
+
+// localVerifier compares against the bcrypt hash stored in sys_users.
+type localVerifier struct {
+	userRepo repository.UserRepository
+}
+
+// NewLocalVerifier creates the bcrypt-on-sys_users backend.
+func NewLocalVerifier(userRepo repository.UserRepository) CredentialVerifier {
+	return &localVerifier{userRepo: userRepo}
+}
+
+func (v *localVerifier) Verify(ctx context.Context, email, password string) (*entity.User, error) {
+	user, err := v.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errUnknownAccount
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+	// Directory accounts must not outlive their directory entry through a
+	// local password.
+	if user.CredentialSource == entity.CredentialSourceLDAP {
+		return nil, errUnknownAccount
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return user, errInvalidPassword
+	}
+	return user, nil
+}
+
+// LDAPConfig holds the settings applied to directory users.
+type LDAPConfig struct {
+	RoleMappings []RoleMapping
+}
+
+// ldapVerifier binds against the directory and keeps the local account in
+// sync with it, creating the account on first login.
+type ldapVerifier struct {
+	client   *directory.Client
+	userRepo repository.UserRepository
+	config   LDAPConfig
+}
+
+// NewLDAPVerifier creates the LDAP / Active Directory backend.
+func NewLDAPVerifier(client *directory.Client, userRepo repository.UserRepository, config LDAPConfig) CredentialVerifier {
+	return &ldapVerifier{client: client, userRepo: userRepo, config: config}
+}
+
+func (v *ldapVerifier) Verify(ctx context.Context, email, password string) (*entity.User, error) {
+	entry, err := v.client.Authenticate(ctx, email, password)
+	switch {
+	case errors.Is(err, directory.ErrNotFound):
+		return nil, errUnknownAccount
+	case errors.Is(err, directory.ErrInvalidCredentials):
+		// Attribute the failure to the local account when there is one
+		user, _ := v.userRepo.GetByEmail(ctx, email)
+		return user, errInvalidPassword
+	case err != nil:
+		logger.Error(ctx, "LDAP authentication failed", zap.Error(err))
+		return nil, apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Directory unavailable", http.StatusServiceUnavailable)
+	}
+
+	if entry.Email == "" {
+		entry.Email = email
+	}
+	mapping := matchRoleMapping(v.config.RoleMappings, entry.Groups)
+
+	user, err := v.userRepo.GetByEmail(ctx, entry.Email)
+	if err == gorm.ErrRecordNotFound {
+		return v.provision(ctx, entry, mapping)
+	}
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+
+	changed := applyDirectoryEntry(user, entry)
+	switch {
+	case mapping != nil:
+		changed = applyRoleMapping(user, mapping) || changed
+	case len(v.config.RoleMappings) > 0 && user.RoleID != nil:
+		// Leaving every mapped group removes the role
+		user.RoleID, user.SubRoleID = nil, nil
+		changed = true
+	}
+	if changed {
+		if err := v.userRepo.Update(ctx, user); err != nil {
+			return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to update user", 500)
+		}
+	}
+	return user, nil
+}
+
+// provision creates the local account for a first directory login. The
+// account has no local password.
+func (v *ldapVerifier) provision(ctx context.Context, entry *directory.Entry, mapping *RoleMapping) (*entity.User, error) {
+	// Deleted accounts keep their email reserved
+	taken, err := v.userRepo.EmailTaken(ctx, entry.Email, "")
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check email", 500)
+	}
+	if taken {
+		return nil, apperror.Forbidden("Account is deactivated")
+	}
+
+	user := &entity.User{Email: entry.Email, IsActive: true, CredentialSource: entity.CredentialSourceLDAP}
+	applyDirectoryEntry(user, entry)
+	if user.FullName == "" {
+		user.FullName, _, _ = strings.Cut(entry.Email, "@")
+	}
+	if mapping != nil {
+		applyRoleMapping(user, mapping)
+	}
+
+	if err := v.userRepo.Create(ctx, user); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create user", 500)
+	}
+	logger.Info(ctx, "Provisioned user from directory", zap.String("user_id", user.ID), zap.String("dn", entry.DN))
+	return user, nil
+}
+
+// applyDirectoryEntry copies the mapped attributes the directory returned
+// and reports whether anything changed. Empty attributes leave the local
+// value alone.
+func applyDirectoryEntry(user *entity.User, entry *directory.Entry) bool {
+	changed := false
+	if entry.FullName != "" && user.FullName != entry.FullName {
+		user.FullName = entry.FullName
+		changed = true
+	}
+	if entry.EmployeeNumber != "" && derefString(user.EmployeeNumber) != entry.EmployeeNumber {
+		employeeNumber := entry.EmployeeNumber
+		user.EmployeeNumber = &employeeNumber
+		changed = true
+	}
+	if entry.DivisionName != "" && derefString(user.DivisionName) != entry.DivisionName {
+		divisionName := entry.DivisionName
+		user.DivisionName = &divisionName
+		changed = true
+	}
+	return changed
+}
+
+// chainVerifier tries each backend in order. An account unknown to one
+// backend, a wrong password or an unreachable backend falls through to the
+// next, so a local break-glass admin still works while the directory is
+// down.
+type chainVerifier []CredentialVerifier
+
+// NewChainVerifier combines backends, tried in the given order.
+func NewChainVerifier(verifiers ...CredentialVerifier) CredentialVerifier {
+	if len(verifiers) == 1 {
+		return verifiers[0]
+	}
+	return chainVerifier(verifiers)
+}
+
+func (c chainVerifier) Verify(ctx context.Context, email, password string) (*entity.User, error) {
+	var known *entity.User
+	var failure error = errUnknownAccount
+	var backendErr error
+
+	for _, verifier := range c {
+		user, err := verifier.Verify(ctx, email, password)
+		switch err {
+		case nil:
+			return user, nil
+		case errUnknownAccount:
+		case errInvalidPassword:
+			failure = errInvalidPassword
+			if user != nil {
+				known = user
+			}
+		default:
+			backendErr = err
+		}
+	}
+
+	// A backend error is only reported when no backend knew the account
+	if failure == errUnknownAccount && backendErr != nil {
+		return nil, backendErr
+	}
+	return known, failure
+}
+
+// ValidateBackends checks the AUTH_BACKENDS list.
+func ValidateBackends(names []string) error {
+	if len(names) == 0 {
+		return errors.New("at least one credential backend is required")
+	}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name != BackendLocal && name != BackendLDAP {
+			return fmt.Errorf("unknown credential backend %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("credential backend %q listed twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
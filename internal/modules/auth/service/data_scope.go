@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/internal/shared/datascope"
+)
+
+const maxScopeOverrideReasonLength = 100
+
+// DataScopeService implements middleware.DataScopeResolver on top of the
+// mst_branches hierarchy.
+type DataScopeService interface {
+	ResolveScope(ctx context.Context, branchID string, area bool) (*datascope.Scope, error)
+	RecordScopeOverride(ctx context.Context, userID, email, ipAddress, userAgent, target string)
+}
+
+type dataScopeService struct {
+	branchRepo repository.BranchScopeRepository
+	eventRepo  repository.SecurityEventRepository
+}
+
+// NewDataScopeService creates a new data scope service.
+func NewDataScopeService(branchRepo repository.BranchScopeRepository, eventRepo repository.SecurityEventRepository) DataScopeService {
+	return &dataScopeService{branchRepo: branchRepo, eventRepo: eventRepo}
+}
+
+// ResolveScope returns every branch for the head office, the whole area
+// when area is set, and otherwise the branch and the branches below it.
+// Callers without a branch, or with a deleted one, see no scoped rows.
+func (s *dataScopeService) ResolveScope(ctx context.Context, branchID string, area bool) (*datascope.Scope, error) {
+	if branchID == "" {
+		return &datascope.Scope{}, nil
+	}
+
+	branches, err := s.branchRepo.Get(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	if branches == nil {
+		return &datascope.Scope{}, nil
+	}
+
+	scope := &datascope.Scope{HomeBranchID: branchID, BranchIDs: branches.Subtree}
+	switch {
+	case branches.HeadOffice:
+		scope.All = true
+	case area:
+		scope.BranchIDs = branches.Area
+	}
+	return scope, nil
+}
+
+// RecordScopeOverride writes a data_scope_override security event naming
+// the request, e.g. "GET /api/transactions/batchings".
+func (s *dataScopeService) RecordScopeOverride(ctx context.Context, userID, email, ipAddress, userAgent, target string) {
+	var actorID *string
+	if userID != "" {
+		actorID = &userID
+	}
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventDataScopeOverride, truncate(target, maxScopeOverrideReasonLength), actorID, normalizeEmail(email), actorID, dto.ClientInfo{
+		IPAddress: ipAddress,
+		UserAgent: truncate(userAgent, maxUserAgentLength),
+	})
+}
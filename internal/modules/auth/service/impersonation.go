@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/token"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxImpersonationReasonLength matches sys_security_events.reason.
+const maxImpersonationReasonLength = 100
+
+// ImpersonationConfig holds the impersonation settings.
+type ImpersonationConfig struct {
+	Keys   *token.KeySet
+	Expiry time.Duration // Lifetime of an impersonation token; it cannot be refreshed
+}
+
+// ImpersonationService defines the admin impersonation interface.
+type ImpersonationService interface {
+	Start(ctx context.Context, subjectID string, req *dto.ImpersonateRequest) (*dto.ImpersonationTokenResponse, error)
+	Stop(ctx context.Context, req *dto.StopImpersonationRequest) error
+	List(ctx context.Context, query *dto.ImpersonationQuery, params *query.Params) ([]*dto.ImpersonationResponse, int64, error)
+	Get(ctx context.Context, id string) (*dto.ImpersonationResponse, error)
+}
+
+type impersonationService struct {
+	userRepo          repository.UserRepository
+	impersonationRepo repository.ImpersonationRepository
+	permissionRepo    repository.PermissionRepository
+	tokenRepo         repository.TokenRepository
+	eventRepo         repository.SecurityEventRepository
+	config            ImpersonationConfig
+}
+
+// NewImpersonationService creates a new impersonation service.
+func NewImpersonationService(
+	userRepo repository.UserRepository,
+	impersonationRepo repository.ImpersonationRepository,
+	permissionRepo repository.PermissionRepository,
+	tokenRepo repository.TokenRepository,
+	eventRepo repository.SecurityEventRepository,
+	config ImpersonationConfig,
+) ImpersonationService {
+	return &impersonationService{
+		userRepo:          userRepo,
+		impersonationRepo: impersonationRepo,
+		permissionRepo:    permissionRepo,
+		tokenRepo:         tokenRepo,
+		eventRepo:         eventRepo,
+		config:            config,
+	}
+}
+
+// Start issues a short-lived access token for the subject that names the
+// administrator in its "act" claim. The token carries the subject's role,
+// sub-role and branch, so permissions and data scope are exactly the
+// subject's, and belongs to the administrator's session so it ends with it.
+// Administrators cannot impersonate users holding permissions they lack.
+func (s *impersonationService) Start(ctx context.Context, subjectID string, req *dto.ImpersonateRequest) (*dto.ImpersonationTokenResponse, error) {
+	if req.ActorSessionID == "" {
+		return nil, apperror.Forbidden("Impersonation requires a signed-in user")
+	}
+	if subjectID == req.ActorID {
+		return nil, apperror.BadRequest("You cannot impersonate yourself")
+	}
+
+	subject, err := s.userRepo.GetByID(ctx, subjectID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("User not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+	if !subject.IsActive {
+		return nil, apperror.BadRequest("Inactive users cannot be impersonated")
+	}
+
+	if subject.RoleID != nil {
+		permissions, err := s.permissionRepo.ListCodesByRole(ctx, *subject.RoleID, derefString(subject.SubRoleID))
+		if err != nil {
+			return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to resolve permissions", 500)
+		}
+		if !containsAll(req.ActorPermissions, permissions) {
+			return nil, apperror.Forbidden("You cannot impersonate a user with permissions you do not hold")
+		}
+	}
+
+	allowWrite := false
+	if req.ActorRoleID != "" {
+		allowWrite, err = s.impersonationRepo.RoleAllowsWrite(ctx, req.ActorRoleID)
+		if err != nil {
+			return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch role", 500)
+		}
+	}
+
+	now := time.Now()
+	impersonation := &entity.Impersonation{
+		ID:         uuid.New().String(),
+		ActorID:    req.ActorID,
+		SubjectID:  subject.ID,
+		SessionID:  &req.ActorSessionID,
+		Reason:     req.Reason,
+		AllowWrite: allowWrite,
+		IPAddress:  req.IPAddress,
+		UserAgent:  truncate(req.UserAgent, maxUserAgentLength),
+		ExpiresAt:  now.Add(s.config.Expiry),
+	}
+
+	signed, err := s.config.Keys.Sign(&token.Claims{
+		UserID:    subject.ID,
+		Email:     subject.Email,
+		RoleID:    derefString(subject.RoleID),
+		SubRoleID: derefString(subject.SubRoleID),
+		BranchID:  derefString(subject.BranchID),
+		TokenType: token.TypeAccess,
+		FamilyID:  req.ActorSessionID,
+		Actor: &token.Actor{
+			UserID: req.ActorID,
+			Email:  req.ActorEmail,
+			Write:  allowWrite,
+		},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        impersonation.ID,
+			Subject:   subject.ID,
+			ExpiresAt: jwt.NewNumericDate(impersonation.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if err != nil {
+		return nil, apperror.Internal("Failed to generate token")
+	}
+
+	if err := s.impersonationRepo.Create(ctx, impersonation); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to start impersonation", 500)
+	}
+
+	logger.Info(ctx, "Impersonation started",
+		zap.String("impersonation_id", impersonation.ID),
+		zap.String("actor_id", req.ActorID),
+		zap.String("subject_id", subject.ID),
+		zap.Bool("allow_write", allowWrite),
+	)
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventImpersonationStarted, truncate(req.Reason, maxImpersonationReasonLength), &subject.ID, normalizeEmail(subject.Email), &req.ActorID, req.ClientInfo)
+
+	return &dto.ImpersonationTokenResponse{
+		ImpersonationID: impersonation.ID,
+		Token:           signed,
+		TokenType:       "Bearer",
+		ExpiresAt:       impersonation.ExpiresAt.Unix(),
+		AllowWrite:      allowWrite,
+		Subject: dto.ImpersonatedUser{
+			ID:       subject.ID,
+			Email:    subject.Email,
+			FullName: subject.FullName,
+			BranchID: subject.BranchID,
+		},
+	}, nil
+}
+
+// Stop revokes the impersonation token and records the end. Stopping an
+// impersonation that has already ended only revokes the token.
+func (s *impersonationService) Stop(ctx context.Context, req *dto.StopImpersonationRequest) error {
+	if err := s.tokenRepo.RevokeToken(ctx, req.ImpersonationID, time.Until(req.ExpiresAt)); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+	}
+
+	ended, err := s.impersonationRepo.End(ctx, req.ImpersonationID, time.Now())
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to stop impersonation", 500)
+	}
+	if !ended {
+		return nil
+	}
+
+	logger.Info(ctx, "Impersonation stopped",
+		zap.String("impersonation_id", req.ImpersonationID),
+		zap.String("actor_id", req.ActorID),
+		zap.String("subject_id", req.SubjectID),
+	)
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventImpersonationStopped, "", &req.SubjectID, normalizeEmail(req.SubjectEmail), &req.ActorID, req.ClientInfo)
+	return nil
+}
+
+func (s *impersonationService) List(ctx context.Context, query *dto.ImpersonationQuery, params *query.Params) ([]*dto.ImpersonationResponse, int64, error) {
+	impersonations, total, err := s.impersonationRepo.List(ctx, repository.ImpersonationFilter{
+		ActorID:   query.ActorID,
+		SubjectID: query.SubjectID,
+	}, params)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch impersonations", 500)
+	}
+
+	result := make([]*dto.ImpersonationResponse, len(impersonations))
+	for i, impersonation := range impersonations {
+		result[i] = toImpersonationResponse(impersonation)
+	}
+	return result, total, nil
+}
+
+// Get returns an impersonation with every row written during it.
+func (s *impersonationService) Get(ctx context.Context, id string) (*dto.ImpersonationResponse, error) {
+	impersonation, err := s.impersonationRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("Impersonation not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch impersonation", 500)
+	}
+
+	writes, err := s.impersonationRepo.ListWrites(ctx, id)
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch impersonation writes", 500)
+	}
+
+	resp := toImpersonationResponse(impersonation)
+	resp.Writes = make([]dto.ImpersonationWriteResponse, len(writes))
+	for i, write := range writes {
+		resp.Writes[i] = dto.ImpersonationWriteResponse{
+			Table:        write.TargetTable,
+			Operation:    write.Operation,
+			RowID:        write.RowID,
+			RowsAffected: write.RowsAffected,
+			RequestID:    write.RequestID,
+			CreatedAt:    write.CreatedAt,
+		}
+	}
+	return resp, nil
+}
+
+func toImpersonationResponse(impersonation *entity.Impersonation) *dto.ImpersonationResponse {
+	return &dto.ImpersonationResponse{
+		ID:         impersonation.ID,
+		ActorID:    impersonation.ActorID,
+		SubjectID:  impersonation.SubjectID,
+		Reason:     impersonation.Reason,
+		AllowWrite: impersonation.AllowWrite,
+		IPAddress:  impersonation.IPAddress,
+		UserAgent:  impersonation.UserAgent,
+		StartedAt:  impersonation.CreatedAt,
+		ExpiresAt:  impersonation.ExpiresAt,
+		EndedAt:    impersonation.EndedAt,
+		Active:     impersonation.EndedAt == nil && impersonation.ExpiresAt.After(time.Now()),
+	}
+}
+
+// containsAll reports whether every code in want is in have.
+func containsAll(have, want []string) bool {
+	held := make(map[string]struct{}, len(have))
+	for _, code := range have {
+		held[code] = struct{}{}
+	}
+	for _, code := range want {
+		if _, ok := held[code]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// LoginPolicy holds the brute-force protection thresholds.
+type LoginPolicy struct {
+	// MaxAttempts is the number of failures per email before it is locked.
+	MaxAttempts int
+	// IPMaxAttempts is the number of failures per client IP before it is throttled.
+	IPMaxAttempts int
+	// Window is how long failures are counted before the counters reset.
+	Window time.Duration
+	// Lockout is the first lockout duration; it doubles on each repeat.
+	Lockout time.Duration
+	// MaxLockout caps the escalated lockout duration.
+	MaxLockout time.Duration
+}
+
+// checkLoginAllowed rejects throttled IPs and locked accounts before any
+// password is checked. Counter store errors fail open: an unavailable Redis
+// must not take down login.
+func (s *authService) checkLoginAllowed(ctx context.Context, email string, client dto.ClientInfo) error {
+	if client.IPAddress != "" && s.loginPolicy.IPMaxAttempts > 0 {
+		failures, err := s.attemptRepo.GetIPFailures(ctx, client.IPAddress)
+		if err != nil {
+			logger.Warn(ctx, "Login attempt store unavailable, skipping IP check", zap.Error(err))
+		} else if failures >= int64(s.loginPolicy.IPMaxAttempts) {
+			s.recordEvent(ctx, entity.SecurityEventLoginFailure, "ip_throttled", nil, email, nil, client)
+			return apperror.RateLimitExceeded()
+		}
+	}
+
+	lockedUntil, err := s.attemptRepo.GetLockedUntil(ctx, email)
+	if err != nil {
+		logger.Warn(ctx, "Login attempt store unavailable, skipping lockout check", zap.Error(err))
+		return nil
+	}
+	if lockedUntil != nil && lockedUntil.After(time.Now()) {
+		s.recordEvent(ctx, entity.SecurityEventLoginFailure, "account_locked", nil, email, nil, client)
+		return accountLockedError(*lockedUntil)
+	}
+
+	return nil
+}
+
+// loginFailed counts a failed attempt against the email and the client IP,
+// locks the email once the threshold is reached and returns the error to
+// hand back to the caller.
+func (s *authService) loginFailed(ctx context.Context, email string, userID *string, reason string, client dto.ClientInfo) error {
+	s.recordEvent(ctx, entity.SecurityEventLoginFailure, reason, userID, email, nil, client)
+
+	if until := s.countFailure(ctx, email, userID, client); until != nil {
+		return accountLockedError(*until)
+	}
+	return apperror.Unauthorized("Invalid email or password")
+}
+
+// countFailure counts a failed password or second factor against the email
+// and the client IP. Once the email reaches the threshold it is locked and
+// the lock expiry is returned.
+func (s *authService) countFailure(ctx context.Context, email string, userID *string, client dto.ClientInfo) *time.Time {
+	if client.IPAddress != "" {
+		if _, err := s.attemptRepo.IncrementIPFailures(ctx, client.IPAddress, s.loginPolicy.Window); err != nil {
+			logger.Warn(ctx, "Failed to count login failure for IP", zap.Error(err))
+		}
+	}
+
+	failures, err := s.attemptRepo.IncrementEmailFailures(ctx, email, s.loginPolicy.Window)
+	if err != nil {
+		logger.Warn(ctx, "Failed to count login failure for email", zap.Error(err))
+		return nil
+	}
+	if s.loginPolicy.MaxAttempts <= 0 || failures < int64(s.loginPolicy.MaxAttempts) {
+		return nil
+	}
+
+	lockouts, err := s.attemptRepo.IncrementLockouts(ctx, email, s.loginPolicy.MaxLockout)
+	if err != nil {
+		logger.Warn(ctx, "Failed to count lockout", zap.Error(err))
+		lockouts = 1
+	}
+
+	until := time.Now().Add(s.lockoutDuration(lockouts))
+	if err := s.attemptRepo.Lock(ctx, email, until); err != nil {
+		logger.Warn(ctx, "Failed to lock account", zap.Error(err))
+		return nil
+	}
+	if err := s.attemptRepo.ResetEmailFailures(ctx, email); err != nil {
+		logger.Warn(ctx, "Failed to reset login failures", zap.Error(err))
+	}
+
+	s.recordEvent(ctx, entity.SecurityEventAccountLocked, "too_many_failures", userID, email, nil, client)
+	logger.Warn(ctx, "Account locked after repeated login failures",
+		zap.String("email", email),
+		zap.Time("locked_until", until),
+	)
+
+	return &until
+}
+
+// loginSucceeded clears the email failure counter and records the login.
+// It runs only once every factor has been verified, so a known password
+// does not reset the failures of the second factor. The IP counter is left
+// alone so one valid account cannot be used to reset a throttled address.
+func (s *authService) loginSucceeded(ctx context.Context, email, userID, reason string, client dto.ClientInfo) {
+	if err := s.attemptRepo.ResetEmailFailures(ctx, email); err != nil {
+		logger.Warn(ctx, "Failed to reset login failures", zap.Error(err))
+	}
+	s.recordEvent(ctx, entity.SecurityEventLoginSuccess, reason, &userID, email, nil, client)
+}
+
+// lockoutDuration doubles the base lockout for every repeat lockout in the
+// escalation window, capped at MaxLockout.
+func (s *authService) lockoutDuration(lockouts int64) time.Duration {
+	d := s.loginPolicy.Lockout
+	for i := int64(1); i < lockouts && d < s.loginPolicy.MaxLockout; i++ {
+		d *= 2
+	}
+	if s.loginPolicy.MaxLockout > 0 && d > s.loginPolicy.MaxLockout {
+		d = s.loginPolicy.MaxLockout
+	}
+	return d
+}
+
+// recordEvent writes a security event. Failures are logged, never returned,
+// so the audit trail cannot block authentication.
+func (s *authService) recordEvent(ctx context.Context, eventType, reason string, userID *string, email string, actorID *string, client dto.ClientInfo) {
+	recordSecurityEvent(ctx, s.eventRepo, eventType, reason, userID, email, actorID, client)
+}
+
+func accountLockedError(until time.Time) error {
+	appErr := apperror.New(apperror.ErrCodeAccountLocked, "Account is temporarily locked due to too many failed login attempts", http.StatusLocked)
+	appErr.Details = map[string]any{"locked_until": until.UTC().Format(time.RFC3339)}
+	return appErr
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
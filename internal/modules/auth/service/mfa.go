@@ -0,0 +1,561 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"image/png"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/token"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	totpPeriod = 30 // seconds
+	totpSkew   = 1  // accepted steps before/after the current one
+	totpDigits = otp.DigitsSix
+
+	mfaMaxAttempts    = 5
+	recoveryCodeCount = 10
+	recoveryCodeChars = "abcdefghjkmnpqrstuvwxyz23456789" // no 0/o, 1/l/i
+)
+
+// MFAConfig holds two-factor authentication settings.
+type MFAConfig struct {
+	// Issuer is shown as the account label in authenticator apps.
+	Issuer string
+	// EncryptionKey protects TOTP secrets at rest (AES-256-GCM over its SHA-256).
+	EncryptionKey string
+	// ChallengeExpiry is the lifetime of the login MFA challenge token.
+	ChallengeExpiry time.Duration
+}
+
+// mfaChallenge returns a challenge when the user must pass a second factor,
+// or nil when the password alone is enough.
+func (s *authService) mfaChallenge(ctx context.Context, user *entity.User) (*dto.MFAChallengeResponse, error) {
+	if user.MFAEnabled {
+		return s.issueMFAChallenge(user, false)
+	}
+
+	if user.RoleID == nil {
+		return nil, nil
+	}
+
+	required, err := s.mfaRepo.RoleRequiresMFA(ctx, *user.RoleID)
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check two-factor policy", 500)
+	}
+	if !required {
+		return nil, nil
+	}
+
+	return s.issueMFAChallenge(user, true)
+}
+
+func (s *authService) issueMFAChallenge(user *entity.User, setupRequired bool) (*dto.MFAChallengeResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.mfa.ChallengeExpiry)
+
+	challenge, err := s.signToken(user, token.TypeMFA, uuid.New().String(), "", now, expiresAt)
+	if err != nil {
+		return nil, apperror.Internal("Failed to generate token")
+	}
+
+	return &dto.MFAChallengeResponse{
+		MFARequired:      true,
+		MFASetupRequired: setupRequired,
+		MFAToken:         challenge,
+		MFAExpiresAt:     expiresAt.Unix(),
+	}, nil
+}
+
+// VerifyMFA completes a login with a TOTP code or a recovery code.
+func (s *authService) VerifyMFA(ctx context.Context, req *dto.MFAVerifyRequest) (*dto.AuthResponse, error) {
+	claims, user, err := s.parseMFAChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLoginAllowed(ctx, normalizeEmail(user.Email), req.ClientInfo); err != nil {
+		return nil, err
+	}
+
+	if !user.MFAEnabled {
+		return nil, apperror.New(apperror.ErrCodeInvalidToken, "Invalid MFA token", http.StatusUnauthorized)
+	}
+
+	reason := "mfa"
+	if req.Code != "" {
+		ok, err := s.verifyUserCode(ctx, user, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, s.mfaChallengeFailed(ctx, claims, user, "invalid_code", req.ClientInfo)
+		}
+	} else {
+		ok, err := s.mfaRepo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(req.RecoveryCode))
+		if err != nil {
+			return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to verify recovery code", 500)
+		}
+		if !ok {
+			return nil, s.mfaChallengeFailed(ctx, claims, user, "invalid_recovery_code", req.ClientInfo)
+		}
+		reason = "recovery_code"
+		s.recordEvent(ctx, entity.SecurityEventRecoveryCodeUsed, "", &user.ID, normalizeEmail(user.Email), nil, req.ClientInfo)
+	}
+
+	if err := s.consumeMFAChallenge(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	s.loginSucceeded(ctx, normalizeEmail(user.Email), user.ID, reason, req.ClientInfo)
+
+	return s.startSession(ctx, user, req.ClientInfo)
+}
+
+// BeginMFAEnrollment generates a new secret for a signed-in user.
+// The secret is stored but inactive until confirmed with a code.
+func (s *authService) BeginMFAEnrollment(ctx context.Context, userID string) (*dto.MFAEnrollmentResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, apperror.Conflict("Two-factor authentication is already enabled")
+	}
+
+	return s.beginEnrollment(ctx, user)
+}
+
+// ConfirmMFAEnrollment activates 2FA once the user proves the app works.
+func (s *authService) ConfirmMFAEnrollment(ctx context.Context, userID string, req *dto.MFACodeRequest) (*dto.MFARecoveryCodesResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, apperror.Conflict("Two-factor authentication is already enabled")
+	}
+
+	codes, err := s.confirmEnrollment(ctx, user, req.Code, req.ClientInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA turns off 2FA unless the user's role requires it.
+func (s *authService) DisableMFA(ctx context.Context, userID string, req *dto.MFADisableRequest) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return apperror.BadRequest("Two-factor authentication is not enabled")
+	}
+
+	if user.RoleID != nil {
+		required, err := s.mfaRepo.RoleRequiresMFA(ctx, *user.RoleID)
+		if err != nil {
+			return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check two-factor policy", 500)
+		}
+		if required {
+			return apperror.Forbidden("Two-factor authentication is required for your role")
+		}
+	}
+
+	if _, err := s.verifier.Verify(ctx, user.Email, req.Password); err != nil {
+		if err == errUnknownAccount || err == errInvalidPassword {
+			return apperror.Unauthorized("Password is incorrect")
+		}
+		return err
+	}
+
+	ok, err := s.verifyUserCode(ctx, user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return invalidMFACodeError()
+	}
+
+	if err := s.mfaRepo.Disable(ctx, user.ID); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to disable two-factor authentication", 500)
+	}
+
+	s.recordEvent(ctx, entity.SecurityEventMFADisabled, "", &user.ID, normalizeEmail(user.Email), &user.ID, req.ClientInfo)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a TOTP code.
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, userID string, req *dto.MFACodeRequest) (*dto.MFARecoveryCodesResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, apperror.BadRequest("Two-factor authentication is not enabled")
+	}
+
+	ok, err := s.verifyUserCode(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, invalidMFACodeError()
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, apperror.Internal("Failed to generate recovery codes")
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to save recovery codes", 500)
+	}
+
+	s.recordEvent(ctx, entity.SecurityEventRecoveryCodesReset, "", &user.ID, normalizeEmail(user.Email), &user.ID, req.ClientInfo)
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// SetupMFA starts a forced enrollment for a user holding a login challenge.
+func (s *authService) SetupMFA(ctx context.Context, req *dto.MFASetupRequest) (*dto.MFAEnrollmentResponse, error) {
+	_, user, err := s.parseMFAChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	// Never let a password-only challenge replace an existing second factor.
+	if user.MFAEnabled {
+		return nil, apperror.Conflict("Two-factor authentication is already enabled")
+	}
+
+	return s.beginEnrollment(ctx, user)
+}
+
+// ConfirmMFASetup completes a forced enrollment and signs the user in.
+func (s *authService) ConfirmMFASetup(ctx context.Context, req *dto.MFASetupConfirmRequest) (*dto.MFASetupResponse, error) {
+	claims, user, err := s.parseMFAChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLoginAllowed(ctx, normalizeEmail(user.Email), req.ClientInfo); err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, apperror.Conflict("Two-factor authentication is already enabled")
+	}
+
+	codes, err := s.confirmEnrollment(ctx, user, req.Code, req.ClientInfo)
+	if err != nil {
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) && appErr.Code == apperror.ErrCodeInvalidMFACode {
+			return nil, s.mfaChallengeFailed(ctx, claims, user, "invalid_code", req.ClientInfo)
+		}
+		return nil, err
+	}
+
+	if err := s.consumeMFAChallenge(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	s.loginSucceeded(ctx, normalizeEmail(user.Email), user.ID, "mfa_setup", req.ClientInfo)
+
+	tokens, err := s.startSession(ctx, user, req.ClientInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFASetupResponse{AuthResponse: tokens, RecoveryCodes: codes}, nil
+}
+
+func (s *authService) beginEnrollment(ctx context.Context, user *entity.User) (*dto.MFAEnrollmentResponse, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.mfa.Issuer,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+		Digits:      totpDigits,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, apperror.Internal("Failed to generate two-factor secret")
+	}
+
+	encrypted, err := s.encryptSecret(key.Secret())
+	if err != nil {
+		return nil, apperror.Internal("Failed to protect two-factor secret")
+	}
+
+	if err := s.mfaRepo.SaveSecret(ctx, user.ID, encrypted); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to save two-factor secret", 500)
+	}
+
+	qrCode, err := qrDataURL(key)
+	if err != nil {
+		return nil, apperror.Internal("Failed to render QR code")
+	}
+
+	return &dto.MFAEnrollmentResponse{
+		Secret:          key.Secret(),
+		ProvisioningURI: key.URL(),
+		QRCode:          qrCode,
+	}, nil
+}
+
+func (s *authService) confirmEnrollment(ctx context.Context, user *entity.User, code string, client dto.ClientInfo) ([]string, error) {
+	if user.MFASecret == nil {
+		return nil, apperror.BadRequest("Two-factor enrollment has not been started")
+	}
+
+	ok, err := s.verifyUserCode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, invalidMFACodeError()
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, apperror.Internal("Failed to generate recovery codes")
+	}
+
+	if err := s.mfaRepo.Enable(ctx, user.ID, hashes); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to enable two-factor authentication", 500)
+	}
+
+	s.recordEvent(ctx, entity.SecurityEventMFAEnabled, "", &user.ID, normalizeEmail(user.Email), &user.ID, client)
+	return codes, nil
+}
+
+// verifyUserCode checks a TOTP code against the user's stored secret and
+// rejects codes that were already accepted within their validity window.
+// It fails with 503 when the replay store is unreachable.
+func (s *authService) verifyUserCode(ctx context.Context, user *entity.User, code string) (bool, error) {
+	if user.MFASecret == nil {
+		return false, nil
+	}
+
+	secret, err := s.decryptSecret(*user.MFASecret)
+	if err != nil {
+		logger.Error(ctx, "Failed to decrypt two-factor secret", zap.String("user_id", user.ID), zap.Error(err))
+		return false, apperror.Internal("Failed to verify code")
+	}
+
+	ok, err := totp.ValidateCustom(code, secret, time.Now(), totp.ValidateOpts{
+		Period:    totpPeriod,
+		Skew:      totpSkew,
+		Digits:    totpDigits,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if err != nil || !ok {
+		return false, nil
+	}
+
+	window := time.Duration(2*totpSkew+1) * totpPeriod * time.Second
+	fresh, err := s.mfaRepo.MarkCodeUsed(ctx, user.ID, code, window)
+	if err != nil {
+		// Without the store a captured code could be replayed.
+		return false, apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+	}
+
+	return fresh, nil
+}
+
+// parseMFAChallenge validates a challenge token and loads its user.
+func (s *authService) parseMFAChallenge(ctx context.Context, tokenString string) (*token.Claims, *entity.User, error) {
+	invalid := apperror.New(apperror.ErrCodeInvalidToken, "Invalid or expired MFA token", http.StatusUnauthorized)
+
+	claims, err := s.parseToken(tokenString)
+	if err != nil || claims.TokenType != token.TypeMFA {
+		return nil, nil, invalid
+	}
+
+	used, err := s.mfaRepo.IsChallengeUsed(ctx, claims.ID)
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+	}
+	if used {
+		return nil, nil, invalid
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, invalid
+		}
+		return nil, nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+	if !user.IsActive {
+		return nil, nil, apperror.Forbidden("Account is deactivated")
+	}
+
+	return claims, user, nil
+}
+
+// consumeMFAChallenge makes sure a challenge yields at most one token pair.
+func (s *authService) consumeMFAChallenge(ctx context.Context, claims *token.Claims) error {
+	fresh, err := s.mfaRepo.MarkChallengeUsed(ctx, claims.ID, time.Until(claims.ExpiresAt.Time))
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+	}
+	if !fresh {
+		return apperror.New(apperror.ErrCodeInvalidToken, "Invalid or expired MFA token", http.StatusUnauthorized)
+	}
+	return nil
+}
+
+// mfaChallengeFailed counts a wrong code against the email lockout, like a
+// wrong password, and against the challenge, which is burnt after
+// mfaMaxAttempts or when the email gets locked, forcing a new sign-in.
+func (s *authService) mfaChallengeFailed(ctx context.Context, claims *token.Claims, user *entity.User, reason string, client dto.ClientInfo) error {
+	email := normalizeEmail(user.Email)
+	s.recordEvent(ctx, entity.SecurityEventMFAChallengeFailed, reason, &user.ID, email, nil, client)
+
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if until := s.countFailure(ctx, email, &user.ID, client); until != nil {
+		if _, err := s.mfaRepo.MarkChallengeUsed(ctx, claims.ID, ttl); err != nil {
+			logger.Warn(ctx, "Failed to expire two-factor challenge", zap.Error(err))
+		}
+		return accountLockedError(*until)
+	}
+
+	failures, err := s.mfaRepo.IncrementChallengeFailures(ctx, claims.ID, ttl)
+	if err != nil {
+		logger.Warn(ctx, "Failed to count two-factor failure", zap.Error(err))
+		return invalidMFACodeError()
+	}
+
+	if failures >= mfaMaxAttempts {
+		if _, err := s.mfaRepo.MarkChallengeUsed(ctx, claims.ID, ttl); err != nil {
+			logger.Warn(ctx, "Failed to expire two-factor challenge", zap.Error(err))
+		}
+		return apperror.New(apperror.ErrCodeInvalidToken, "Too many invalid codes, please sign in again", http.StatusUnauthorized)
+	}
+
+	return invalidMFACodeError()
+}
+
+func (s *authService) getUser(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("User not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+	return user, nil
+}
+
+func (s *authService) encryptSecret(plain string) (string, error) {
+	gcm, err := s.secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *authService) decryptSecret(encoded string) (string, error) {
+	gcm, err := s.secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func (s *authService) secretCipher() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(s.mfa.EncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func invalidMFACodeError() error {
+	return apperror.New(apperror.ErrCodeInvalidMFACode, "Invalid two-factor code", http.StatusUnauthorized)
+}
+
+// generateRecoveryCodes returns the plain codes (shown once) and their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	max := big.NewInt(int64(len(recoveryCodeChars)))
+
+	for i := range codes {
+		var b strings.Builder
+		for j := 0; j < 10; j++ {
+			if j == 5 {
+				b.WriteByte('-')
+			}
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, nil, err
+			}
+			b.WriteByte(recoveryCodeChars[n.Int64()])
+		}
+		codes[i] = b.String()
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a code after normalizing case, dashes and spaces.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func qrDataURL(key *otp.Key) (string, error) {
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const (
+	oidcLoginMethod  = "oidc"
+	oidcStateExpiry  = 10 * time.Minute
+	oidcHTTPTimeout  = 10 * time.Second
+	oidcRandomLength = 32
+)
+
+// OIDCConfig holds the OpenID Connect client settings.
+type OIDCConfig struct {
+	IssuerURL    string // Discovery is read from IssuerURL/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	RoleMappings []RoleMapping
+	// LinkOnly disables provisioning: unknown emails are rejected.
+	LinkOnly bool
+}
+
+// OIDCService implements single sign-on with the authorization code flow
+// and PKCE. Successful logins receive the application's normal token pair.
+type OIDCService interface {
+	BeginLogin(ctx context.Context) (*dto.OIDCLoginResponse, error)
+	Callback(ctx context.Context, req *dto.OIDCCallbackRequest) (*dto.LoginResponse, error)
+}
+
+type oidcService struct {
+	auth         AuthService
+	userRepo     repository.UserRepository
+	identityRepo repository.IdentityRepository
+	config       OIDCConfig
+
+	// The provider is discovered on first use so the API starts even when
+	// the identity provider is unreachable.
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+	client   context.Context
+}
+
+// NewOIDCService creates a new OIDC service.
+func NewOIDCService(auth AuthService, userRepo repository.UserRepository, identityRepo repository.IdentityRepository, config OIDCConfig) OIDCService {
+	httpClient := &http.Client{Timeout: oidcHTTPTimeout}
+	return &oidcService{
+		auth:         auth,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		config:       config,
+		client:       oidc.ClientContext(context.Background(), httpClient),
+	}
+}
+
+// BeginLogin stores a fresh state, nonce and PKCE verifier and returns the
+// provider's authorization URL.
+func (s *oidcService) BeginLogin(ctx context.Context) (*dto.OIDCLoginResponse, error) {
+	oauth, _, err := s.provider()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return nil, apperror.Internal("Failed to start login")
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, apperror.Internal("Failed to start login")
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return nil, apperror.Internal("Failed to start login")
+	}
+
+	if err := s.identityRepo.SaveState(ctx, state, &repository.OIDCState{CodeVerifier: verifier, Nonce: nonce}, oidcStateExpiry); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Login state store unavailable", http.StatusServiceUnavailable)
+	}
+
+	authURL := oauth.AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", pkceChallenge(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+
+	return &dto.OIDCLoginResponse{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiresAt:        time.Now().Add(oidcStateExpiry).Unix(),
+	}, nil
+}
+
+// Callback redeems the authorization code, verifies the ID token and signs
+// the user in, linking or provisioning the local account by email.
+func (s *oidcService) Callback(ctx context.Context, req *dto.OIDCCallbackRequest) (*dto.LoginResponse, error) {
+	oauth, verifier, err := s.provider()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.identityRepo.ConsumeState(ctx, req.State, oidcStateExpiry)
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Login state store unavailable", http.StatusServiceUnavailable)
+	}
+	if state == nil {
+		return nil, apperror.New(apperror.ErrCodeInvalidToken, "Invalid or expired login state", http.StatusBadRequest)
+	}
+
+	if req.Error != "" {
+		logger.Warn(ctx, "Identity provider rejected login", zap.String("error", req.Error), zap.String("description", req.ErrorDescription))
+		return nil, apperror.Unauthorized("Sign-in was cancelled or rejected by the identity provider")
+	}
+
+	exchangeCtx, cancel := context.WithTimeout(s.client, oidcHTTPTimeout)
+	defer cancel()
+
+	oauthToken, err := oauth.Exchange(exchangeCtx, req.Code, oauth2.SetAuthURLParam("code_verifier", state.CodeVerifier))
+	if err != nil {
+		logger.Warn(ctx, "OIDC code exchange failed", zap.Error(err))
+		return nil, apperror.Unauthorized("Invalid authorization code")
+	}
+
+	rawIDToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok {
+		return nil, apperror.Unauthorized("Identity provider did not return an ID token")
+	}
+	idToken, err := verifier.Verify(exchangeCtx, rawIDToken)
+	if err != nil {
+		logger.Warn(ctx, "OIDC ID token verification failed", zap.Error(err))
+		return nil, apperror.Unauthorized("Invalid ID token")
+	}
+	if idToken.Nonce != state.Nonce {
+		return nil, apperror.Unauthorized("Invalid ID token")
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, apperror.Unauthorized("Invalid ID token")
+	}
+
+	user, err := s.resolveUser(ctx, idToken.Subject, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.auth.LoginExternal(ctx, user, oidcLoginMethod, req.ClientInfo)
+}
+
+// resolveUser finds the account linked to the subject, links an existing
+// account by verified email, or provisions a new one. Mapped groups update
+// the role and sub-role on every login.
+func (s *oidcService) resolveUser(ctx context.Context, subject string, claims map[string]any) (*entity.User, error) {
+	email, _ := claims["email"].(string)
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, apperror.Forbidden("Identity provider did not return an email")
+	}
+	verified, stated := emailVerified(claims)
+	if stated && !verified {
+		return nil, apperror.Forbidden("Email is not verified by the identity provider")
+	}
+
+	var user *entity.User
+	identity, err := s.identityRepo.GetBySubject(ctx, s.config.IssuerURL, subject)
+	switch {
+	case err == nil:
+		user, err = s.userRepo.GetByID(ctx, identity.UserID)
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.Forbidden("Account is deactivated")
+		}
+		if err != nil {
+			return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+		}
+	case err != gorm.ErrRecordNotFound:
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch identity", 500)
+	}
+
+	mapping := matchRoleMapping(s.config.RoleMappings, stringsClaim(claims[s.config.GroupsClaim]))
+
+	if user == nil {
+		identity = &entity.UserIdentity{Provider: s.config.IssuerURL, Subject: subject, Email: &email}
+		user, err = s.linkOrProvision(ctx, email, verified, claims, identity, mapping)
+		if err != nil {
+			return nil, err
+		}
+	} else if mapping != nil && applyRoleMapping(user, mapping) {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to update user", 500)
+		}
+	}
+
+	if err := s.identityRepo.Touch(ctx, identity.ID, email, time.Now()); err != nil {
+		logger.Warn(ctx, "Failed to record identity login", zap.Error(err))
+	}
+	return user, nil
+}
+
+// linkOrProvision links the identity to the account with that email, which
+// requires email_verified to be true, or else provisions a new account.
+func (s *oidcService) linkOrProvision(ctx context.Context, email string, verified bool, claims map[string]any, identity *entity.UserIdentity, mapping *RoleMapping) (*entity.User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		// Otherwise anyone able to set an arbitrary email at the provider
+		// could sign in as the local account, administrators included.
+		if !verified {
+			return nil, apperror.Forbidden("Email must be verified by the identity provider to link an existing account")
+		}
+		identity.UserID = user.ID
+		if err := s.identityRepo.Link(ctx, identity); err != nil {
+			return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to link identity", 500)
+		}
+		if mapping != nil && applyRoleMapping(user, mapping) {
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to update user", 500)
+			}
+		}
+		return user, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+
+	if s.config.LinkOnly {
+		return nil, apperror.Forbidden("No account exists for this email")
+	}
+
+	// Deleted accounts keep their email reserved
+	taken, err := s.userRepo.EmailTaken(ctx, email, "")
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check email", 500)
+	}
+	if taken {
+		return nil, apperror.Forbidden("Account is deactivated")
+	}
+
+	name, _ := claims["name"].(string)
+	if strings.TrimSpace(name) == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	// Provisioned accounts have no local password until one is set through
+	// the password reset flow
+	user = &entity.User{
+		Email:    email,
+		FullName: name,
+		IsActive: true,
+	}
+	if mapping != nil {
+		applyRoleMapping(user, mapping)
+	}
+
+	if err := s.identityRepo.Provision(ctx, user, identity); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create user", 500)
+	}
+	return user, nil
+}
+
+// emailVerified reads the email_verified claim; stated is false when the
+// provider omits it. Some providers send the boolean as a string.
+func emailVerified(claims map[string]any) (verified, stated bool) {
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		return v, true
+	case string:
+		return v == "true", true
+	}
+	return false, false
+}
+
+// provider discovers the identity provider on first use. A failed discovery
+// is retried on the next request.
+func (s *oidcService) provider() (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.oauth != nil {
+		return s.oauth, s.verifier, nil
+	}
+
+	ctx, cancel := context.WithTimeout(s.client, oidcHTTPTimeout)
+	defer cancel()
+
+	provider, err := oidc.NewProvider(ctx, s.config.IssuerURL)
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Identity provider unavailable", http.StatusServiceUnavailable)
+	}
+
+	s.oauth = &oauth2.Config{
+		ClientID:     s.config.ClientID,
+		ClientSecret: s.config.ClientSecret,
+		RedirectURL:  s.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       s.config.Scopes,
+	}
+	s.verifier = provider.Verifier(&oidc.Config{ClientID: s.config.ClientID})
+	return s.oauth, s.verifier, nil
+}
+
+// stringsClaim reads a claim holding a list of strings or a single string.
+func stringsClaim(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return items
+	}
+	return nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, oidcRandomLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// pkceChallenge derives the S256 code challenge (RFC 7636).
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
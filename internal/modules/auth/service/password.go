@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/mailer"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// PasswordService defines the password recovery and change interface.
+type PasswordService interface {
+	Forgot(ctx context.Context, req *dto.ForgotPasswordRequest) error
+	Reset(ctx context.Context, req *dto.ResetPasswordRequest) error
+	Change(ctx context.Context, userID string, req *dto.ChangePasswordRequest) error
+}
+
+// PasswordResetConfig holds password reset settings.
+type PasswordResetConfig struct {
+	// URL is the frontend page that accepts the token as ?token=.
+	URL    string
+	Expiry time.Duration
+}
+
+type passwordService struct {
+	userRepo    repository.UserRepository
+	resetRepo   repository.PasswordResetRepository
+	attemptRepo repository.LoginAttemptRepository
+	eventRepo   repository.SecurityEventRepository
+	sessions    SessionRevoker
+	mail        mailer.Sender
+	passwords   *PasswordManager
+	reset       PasswordResetConfig
+}
+
+// NewPasswordService creates a new password service.
+func NewPasswordService(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	attemptRepo repository.LoginAttemptRepository,
+	eventRepo repository.SecurityEventRepository,
+	sessions SessionRevoker,
+	mail mailer.Sender,
+	passwords *PasswordManager,
+	reset PasswordResetConfig,
+) PasswordService {
+	return &passwordService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		attemptRepo: attemptRepo,
+		eventRepo:   eventRepo,
+		sessions:    sessions,
+		mail:        mail,
+		passwords:   passwords,
+		reset:       reset,
+	}
+}
+
+// Forgot emails a single-use reset link. Unknown, inactive and directory
+// accounts are ignored silently, and delivery failures are only logged, so the response
+// never reveals whether an email is registered.
+func (s *passwordService) Forgot(ctx context.Context, req *dto.ForgotPasswordRequest) error {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+	if !user.IsActive || user.CredentialSource == entity.CredentialSourceLDAP {
+		return nil
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return apperror.Internal("Failed to generate reset token")
+	}
+
+	if err := s.resetRepo.Save(ctx, hashResetToken(token), user.ID, s.reset.Expiry); err != nil {
+		logger.Error(ctx, "Failed to store password reset token", zap.String("user_id", user.ID), zap.Error(err))
+		return nil
+	}
+
+	msg := mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Reset your password",
+		Body:    s.resetEmailBody(user.FullName, token),
+	}
+	if err := s.mail.Send(ctx, msg); err != nil {
+		logger.Error(ctx, "Failed to send password reset email", zap.String("user_id", user.ID), zap.Error(err))
+		return nil
+	}
+
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventPasswordResetRequested, "", &user.ID, normalizeEmail(user.Email), nil, req.ClientInfo)
+	return nil
+}
+
+// Reset consumes a reset token and sets the new password. A successful reset
+// also lifts any login lockout on the account and signs it out everywhere.
+// Inactive and directory accounts are refused, as in Forgot.
+func (s *passwordService) Reset(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	userID, err := s.resetRepo.Consume(ctx, hashResetToken(req.Token), s.reset.Expiry)
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+	}
+	if userID == "" {
+		return apperror.New(apperror.ErrCodeInvalidToken, "Invalid or expired reset token", http.StatusBadRequest)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return apperror.New(apperror.ErrCodeInvalidToken, "Invalid or expired reset token", http.StatusBadRequest)
+		}
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+	if !user.IsActive || user.CredentialSource == entity.CredentialSourceLDAP {
+		return apperror.New(apperror.ErrCodeInvalidToken, "Invalid or expired reset token", http.StatusBadRequest)
+	}
+
+	if err := s.setPassword(ctx, user, req.NewPassword, ""); err != nil {
+		return err
+	}
+
+	email := normalizeEmail(user.Email)
+	if err := s.attemptRepo.Unlock(ctx, email); err != nil {
+		logger.Warn(ctx, "Failed to clear login lockout after password reset", zap.Error(err))
+	}
+
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventPasswordReset, "", &user.ID, email, nil, req.ClientInfo)
+	return nil
+}
+
+// Change sets a new password for an authenticated user after verifying the
+// current one, signing out every other session.
+func (s *passwordService) Change(ctx context.Context, userID string, req *dto.ChangePasswordRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return apperror.NotFound("User not found")
+		}
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return apperror.Unauthorized("Current password is incorrect")
+	}
+
+	if err := s.setPassword(ctx, user, req.NewPassword, req.SessionID); err != nil {
+		return err
+	}
+
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventPasswordChanged, "", &user.ID, normalizeEmail(user.Email), &user.ID, req.ClientInfo)
+	return nil
+}
+
+// setPassword enforces the password policy and history, stores the new
+// password and revokes every session of the user except keepSessionID.
+// Directory accounts never get a local password.
+func (s *passwordService) setPassword(ctx context.Context, user *entity.User, password, keepSessionID string) error {
+	if user.CredentialSource == entity.CredentialSourceLDAP {
+		return apperror.Forbidden("The password of a directory account is changed in the directory")
+	}
+	if err := s.passwords.Validate(ctx, "new_password", password, user); err != nil {
+		return err
+	}
+	if err := s.passwords.Apply(user, password); err != nil {
+		return err
+	}
+
+	user.UpdatedBy = &user.ID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to update password", 500)
+	}
+
+	if err := s.passwords.Record(ctx, user); err != nil {
+		return err
+	}
+	return s.sessions.RevokeUserSessions(ctx, user.ID, keepSessionID, entity.SessionRevokedPasswordChanged)
+}
+
+func (s *passwordService) resetEmailBody(name, token string) string {
+	link := token
+	if s.reset.URL != "" {
+		link = s.reset.URL + "?token=" + url.QueryEscape(token)
+	}
+
+	return fmt.Sprintf(`Hello %s,
+
+We received a request to reset your password. Use the link below to choose a new one:
+
+%s
+
+The link expires in %d minutes and can only be used once. If you did not request a reset, you can ignore this email.
+`, name, link, int(s.reset.Expiry.Minutes()))
+}
+
+// generateResetToken returns 32 random bytes, URL-safe encoded.
+func generateResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/password"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordPolicy holds password age and reuse settings.
+type PasswordPolicy struct {
+	// MaxAge forces a change once a password is older; 0 disables expiry.
+	MaxAge time.Duration
+	// HistorySize is how many previous passwords cannot be reused; 0 disables the check.
+	HistorySize int
+}
+
+// PasswordManager applies the strength policy, expiry and reuse rules
+// wherever a password is set: registration, admin creation, reset and change.
+type PasswordManager struct {
+	strength *password.Policy
+	history  repository.PasswordHistoryRepository
+	policy   PasswordPolicy
+}
+
+// NewPasswordManager creates a new password manager.
+func NewPasswordManager(strength *password.Policy, history repository.PasswordHistoryRepository, policy PasswordPolicy) *PasswordManager {
+	return &PasswordManager{strength: strength, history: history, policy: policy}
+}
+
+// Validate checks a candidate password against the strength policy and,
+// for an existing user, the password history. Violations are returned as
+// a validation error on field.
+func (m *PasswordManager) Validate(ctx context.Context, field, candidate string, user *entity.User) error {
+	errs := m.strength.Check(field, candidate)
+
+	if user != nil && user.ID != "" && m.policy.HistorySize > 0 {
+		reused, err := m.reused(ctx, user, candidate)
+		if err != nil {
+			return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check password history", 500)
+		}
+		if reused {
+			errs = append(errs, validator.ValidationError{
+				Field:   field,
+				Message: "Must not match any of your last passwords",
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return apperror.Validation("Validation failed", errs)
+	}
+	return nil
+}
+
+// Apply hashes the password onto the user and restarts its expiry clock.
+// The caller saves the user and then calls Record.
+func (m *PasswordManager) Apply(user *entity.User, plaintext string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plaintext), bcrypt.DefaultCost)
+	if err != nil {
+		return apperror.Internal("Failed to hash password")
+	}
+
+	now := time.Now()
+	user.Password = string(hashed)
+	user.PasswordChangedAt = &now
+	user.MustChangePassword = false
+	return nil
+}
+
+// Record adds the user's current password to the history.
+func (m *PasswordManager) Record(ctx context.Context, user *entity.User) error {
+	if m.policy.HistorySize <= 0 {
+		return nil
+	}
+	if err := m.history.Add(ctx, user.ID, user.Password, m.policy.HistorySize); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to record password history", 500)
+	}
+	return nil
+}
+
+// ChangeRequired reports whether the user must change their password
+// before using the API: it was set by an administrator or has expired.
+func (m *PasswordManager) ChangeRequired(user *entity.User) bool {
+	if user.MustChangePassword {
+		return true
+	}
+	expiresAt := m.ExpiresAt(user)
+	return expiresAt != nil && !expiresAt.After(time.Now())
+}
+
+// ExpiresAt returns when the user's password expires, or nil if it never does.
+func (m *PasswordManager) ExpiresAt(user *entity.User) *time.Time {
+	if m.policy.MaxAge <= 0 || user.PasswordChangedAt == nil {
+		return nil
+	}
+	expiresAt := user.PasswordChangedAt.Add(m.policy.MaxAge)
+	return &expiresAt
+}
+
+// reused compares the candidate with the current password and the history.
+func (m *PasswordManager) reused(ctx context.Context, user *entity.User, candidate string) (bool, error) {
+	hashes, err := m.history.Recent(ctx, user.ID, m.policy.HistorySize)
+	if err != nil {
+		return false, err
+	}
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(candidate)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/mailer"
+	"github.com/user/go-boilerplate/pkg/token"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Registration modes (REGISTRATION_MODE).
+const (
+	// RegistrationDisabled closes /auth/register; only admins create accounts.
+	RegistrationDisabled = "disabled"
+	// RegistrationDomain allows self-registration for allowlisted email
+	// domains. Accounts start inactive until an admin approves them.
+	RegistrationDomain = "domain"
+	// RegistrationInvite closes /auth/register; users join by redeeming an
+	// admin-issued invite.
+	RegistrationInvite = "invite"
+)
+
+// RegistrationConfig holds onboarding settings.
+type RegistrationConfig struct {
+	Mode           string
+	AllowedDomains []string // Lower-case domains for RegistrationDomain
+	InviteURL      string   // Frontend page; the invite token is appended as ?token=
+	InviteExpiry   time.Duration
+}
+
+// ValidRegistrationMode reports whether mode is a known registration mode.
+func ValidRegistrationMode(mode string) bool {
+	switch mode {
+	case RegistrationDisabled, RegistrationDomain, RegistrationInvite:
+		return true
+	}
+	return false
+}
+
+// RegistrationService defines self-registration and invite onboarding.
+type RegistrationService interface {
+	Register(ctx context.Context, req *dto.RegisterRequest) (*dto.UserResponse, error)
+
+	ListInvites(ctx context.Context, query *dto.InviteQuery, params *query.Params) ([]*dto.InviteResponse, int64, error)
+	CreateInvite(ctx context.Context, actorID string, req *dto.CreateInviteRequest) (*dto.IssuedInviteResponse, error)
+	RevokeInvite(ctx context.Context, id string) error
+	AcceptInvite(ctx context.Context, req *dto.AcceptInviteRequest) (*dto.UserResponse, error)
+}
+
+type registrationService struct {
+	userRepo       repository.UserRepository
+	assignmentRepo repository.AssignmentRepository
+	permissionRepo repository.PermissionRepository
+	inviteRepo     repository.InviteRepository
+	eventRepo      repository.SecurityEventRepository
+	passwords      *PasswordManager
+	keys           *token.KeySet
+	mail           mailer.Sender
+	config         RegistrationConfig
+}
+
+// NewRegistrationService creates a new registration service.
+func NewRegistrationService(
+	userRepo repository.UserRepository,
+	assignmentRepo repository.AssignmentRepository,
+	permissionRepo repository.PermissionRepository,
+	inviteRepo repository.InviteRepository,
+	eventRepo repository.SecurityEventRepository,
+	passwords *PasswordManager,
+	keys *token.KeySet,
+	mail mailer.Sender,
+	config RegistrationConfig,
+) RegistrationService {
+	return &registrationService{
+		userRepo:       userRepo,
+		assignmentRepo: assignmentRepo,
+		permissionRepo: permissionRepo,
+		inviteRepo:     inviteRepo,
+		eventRepo:      eventRepo,
+		passwords:      passwords,
+		keys:           keys,
+		mail:           mail,
+		config:         config,
+	}
+}
+
+// Register creates a self-registered account. It is only open in domain
+// mode, and the account stays inactive until an admin activates it.
+func (s *registrationService) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.UserResponse, error) {
+	switch s.config.Mode {
+	case RegistrationDomain:
+	case RegistrationInvite:
+		return nil, apperror.Forbidden("Registration is by invitation only")
+	default:
+		return nil, apperror.Forbidden("Registration is disabled")
+	}
+
+	if !s.domainAllowed(req.Email) {
+		return nil, apperror.Validation("Validation failed", []validator.ValidationError{
+			{Field: "email", Message: "Email domain is not allowed to register"},
+		})
+	}
+
+	if err := s.checkEmail(ctx, req.Email); err != nil {
+		return nil, err
+	}
+
+	if err := s.passwords.Validate(ctx, "password", req.Password, nil); err != nil {
+		return nil, err
+	}
+
+	user := &entity.User{
+		Email:    req.Email,
+		FullName: req.FullName,
+		IsActive: false,
+	}
+	if err := s.passwords.Apply(user, req.Password); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create user", 500)
+	}
+	if err := s.passwords.Record(ctx, user); err != nil {
+		return nil, err
+	}
+
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventUserRegistered, "pending_approval", &user.ID, normalizeEmail(user.Email), nil, req.ClientInfo)
+
+	return &dto.UserResponse{
+		ID:       user.ID,
+		Email:    user.Email,
+		FullName: user.FullName,
+		IsActive: user.IsActive,
+	}, nil
+}
+
+func (s *registrationService) ListInvites(ctx context.Context, query *dto.InviteQuery, params *query.Params) ([]*dto.InviteResponse, int64, error) {
+	filter := repository.InviteFilter{
+		Email:  strings.ToLower(strings.TrimSpace(query.Email)),
+		Status: query.Status,
+	}
+
+	invites, total, err := s.inviteRepo.List(ctx, filter, params)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch invites", 500)
+	}
+
+	now := time.Now()
+	items := make([]*dto.InviteResponse, len(invites))
+	for i, invite := range invites {
+		items[i] = toInviteResponse(invite, now)
+	}
+
+	return items, total, nil
+}
+
+// CreateInvite stores an invite, signs its token and emails the link.
+// Earlier pending invites for the same email stop working. The role can
+// only grant permissions the inviter holds. Delivery
+// failures are only logged because the token is also returned to the admin.
+func (s *registrationService) CreateInvite(ctx context.Context, actorID string, req *dto.CreateInviteRequest) (*dto.IssuedInviteResponse, error) {
+	if s.config.Mode != RegistrationInvite {
+		return nil, apperror.Forbidden("Invites are disabled")
+	}
+
+	if err := s.checkEmail(ctx, req.Email); err != nil {
+		return nil, err
+	}
+
+	// Validate the assignments now so a bad invite fails for the admin,
+	// not for the invitee
+	if err := checkAssignments(ctx, s.assignmentRepo, &entity.User{
+		RoleID:    req.RoleID,
+		SubRoleID: req.SubRoleID,
+		BranchID:  req.BranchID,
+	}); err != nil {
+		return nil, err
+	}
+	if err := checkGrantable(ctx, s.permissionRepo, req.ActorPermissions, req.RoleID, req.SubRoleID); err != nil {
+		return nil, err
+	}
+
+	expiry := s.config.InviteExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	now := time.Now()
+	invite := &entity.Invite{
+		Email:     strings.TrimSpace(req.Email),
+		FullName:  req.FullName,
+		RoleID:    req.RoleID,
+		SubRoleID: req.SubRoleID,
+		BranchID:  req.BranchID,
+		ExpiresAt: now.Add(expiry),
+		CreatedBy: &actorID,
+	}
+	if err := s.inviteRepo.Create(ctx, invite); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create invite", 500)
+	}
+
+	inviteToken, err := s.keys.Sign(&token.Claims{
+		Email:     invite.Email,
+		RoleID:    derefString(invite.RoleID),
+		SubRoleID: derefString(invite.SubRoleID),
+		TokenType: token.TypeInvite,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        invite.ID,
+			Subject:   invite.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(invite.ExpiresAt),
+		},
+	})
+	if err != nil {
+		return nil, apperror.Internal("Failed to sign invite")
+	}
+
+	link := s.inviteLink(inviteToken)
+	msg := mailer.Message{
+		To:      []string{invite.Email},
+		Subject: "You have been invited",
+		Body:    inviteEmailBody(invite, link),
+	}
+	if err := s.mail.Send(ctx, msg); err != nil {
+		logger.Error(ctx, "Failed to send invite email", zap.String("invite_id", invite.ID), zap.Error(err))
+	}
+
+	resp := &dto.IssuedInviteResponse{
+		InviteResponse: toInviteResponse(invite, now),
+		Token:          inviteToken,
+	}
+	if s.config.InviteURL != "" {
+		resp.URL = link
+	}
+	return resp, nil
+}
+
+func (s *registrationService) RevokeInvite(ctx context.Context, id string) error {
+	if _, err := s.findInvite(ctx, id); err != nil {
+		return err
+	}
+
+	revoked, err := s.inviteRepo.Revoke(ctx, id)
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to revoke invite", 500)
+	}
+	if !revoked {
+		return apperror.Conflict("Invite has already been accepted or revoked")
+	}
+	return nil
+}
+
+// AcceptInvite verifies the signed invite token and creates an active
+// account with the invite's email and assignments.
+func (s *registrationService) AcceptInvite(ctx context.Context, req *dto.AcceptInviteRequest) (*dto.UserResponse, error) {
+	if s.config.Mode != RegistrationInvite {
+		return nil, apperror.Forbidden("Invites are disabled")
+	}
+
+	invalid := apperror.New(apperror.ErrCodeInvalidToken, "Invalid or expired invite", http.StatusBadRequest)
+
+	claims, err := s.keys.Parse(req.Token)
+	if err != nil || claims.TokenType != token.TypeInvite {
+		return nil, invalid
+	}
+
+	invite, err := s.inviteRepo.GetByID(ctx, claims.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, invalid
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch invite", 500)
+	}
+	if invite.Status(time.Now()) != entity.InviteStatusPending {
+		return nil, invalid
+	}
+
+	if err := s.checkEmail(ctx, invite.Email); err != nil {
+		return nil, err
+	}
+
+	if err := s.passwords.Validate(ctx, "password", req.Password, nil); err != nil {
+		return nil, err
+	}
+
+	user := &entity.User{
+		Email:     invite.Email,
+		FullName:  req.FullName,
+		RoleID:    invite.RoleID,
+		SubRoleID: invite.SubRoleID,
+		BranchID:  invite.BranchID,
+		IsActive:  true,
+	}
+	user.CreatedBy, user.UpdatedBy = invite.CreatedBy, invite.CreatedBy
+	if err := s.passwords.Apply(user, req.Password); err != nil {
+		return nil, err
+	}
+
+	if err := s.inviteRepo.Redeem(ctx, invite.ID, user); err != nil {
+		if err == repository.ErrInviteUnavailable {
+			return nil, invalid
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create user", 500)
+	}
+	if err := s.passwords.Record(ctx, user); err != nil {
+		return nil, err
+	}
+
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventInviteAccepted, "", &user.ID, normalizeEmail(user.Email), invite.CreatedBy, req.ClientInfo)
+
+	return &dto.UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		FullName:  user.FullName,
+		IsActive:  user.IsActive,
+		RoleID:    user.RoleID,
+		SubRoleID: user.SubRoleID,
+	}, nil
+}
+
+func (s *registrationService) domainAllowed(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range s.config.AllowedDomains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *registrationService) checkEmail(ctx context.Context, email string) error {
+	taken, err := s.userRepo.EmailTaken(ctx, email, "")
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check email", 500)
+	}
+	if taken {
+		return apperror.Conflict("Email already registered")
+	}
+	return nil
+}
+
+func (s *registrationService) findInvite(ctx context.Context, id string) (*entity.Invite, error) {
+	invite, err := s.inviteRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("Invite not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch invite", 500)
+	}
+	return invite, nil
+}
+
+func (s *registrationService) inviteLink(inviteToken string) string {
+	if s.config.InviteURL == "" {
+		return inviteToken
+	}
+	return s.config.InviteURL + "?token=" + url.QueryEscape(inviteToken)
+}
+
+func inviteEmailBody(invite *entity.Invite, link string) string {
+	name := "there"
+	if invite.FullName != nil && *invite.FullName != "" {
+		name = *invite.FullName
+	}
+
+	return fmt.Sprintf(`Hello %s,
+
+You have been invited to create an account for %s. Use the link below to choose your password:
+
+%s
+
+The invite expires on %s and can only be used once.
+`, name, invite.Email, link, invite.ExpiresAt.Format(time.RFC1123))
+}
+
+func toInviteResponse(invite *entity.Invite, now time.Time) *dto.InviteResponse {
+	return &dto.InviteResponse{
+		ID:             invite.ID,
+		Email:          invite.Email,
+		FullName:       invite.FullName,
+		RoleID:         invite.RoleID,
+		SubRoleID:      invite.SubRoleID,
+		BranchID:       invite.BranchID,
+		Status:         invite.Status(now),
+		ExpiresAt:      invite.ExpiresAt,
+		AcceptedAt:     invite.AcceptedAt,
+		AcceptedUserID: invite.AcceptedUserID,
+		RevokedAt:      invite.RevokedAt,
+		CreatedBy:      invite.CreatedBy,
+		CreatedAt:      invite.CreatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+)
+
+// RoleMapping assigns a role, and optionally a sub-role, to members of a
+// group reported by an external identity provider.
+type RoleMapping struct {
+	Group     string
+	RoleID    string
+	SubRoleID string
+}
+
+// ParseRoleMappings parses "group=role_id[:sub_role_id]" entries separated
+// by ";". Groups may contain "=" (e.g. an LDAP DN): the last "=" separates
+// the group from the role.
+func ParseRoleMappings(value string) ([]RoleMapping, error) {
+	var mappings []RoleMapping
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		sep := strings.LastIndex(entry, "=")
+		if sep <= 0 {
+			return nil, fmt.Errorf("role mapping %q: expected group=role_id[:sub_role_id]", entry)
+		}
+		group := strings.TrimSpace(entry[:sep])
+		roleID, subRoleID, _ := strings.Cut(strings.TrimSpace(entry[sep+1:]), ":")
+
+		if _, err := uuid.Parse(roleID); err != nil {
+			return nil, fmt.Errorf("role mapping %q: invalid role ID", entry)
+		}
+		if subRoleID != "" {
+			if _, err := uuid.Parse(subRoleID); err != nil {
+				return nil, fmt.Errorf("role mapping %q: invalid sub-role ID", entry)
+			}
+		}
+
+		mappings = append(mappings, RoleMapping{Group: group, RoleID: roleID, SubRoleID: subRoleID})
+	}
+	return mappings, nil
+}
+
+// matchRoleMapping returns the first mapping whose group the user belongs
+// to, comparing case-insensitively, or nil.
+func matchRoleMapping(mappings []RoleMapping, groups []string) *RoleMapping {
+	for i := range mappings {
+		for _, group := range groups {
+			if strings.EqualFold(mappings[i].Group, group) {
+				return &mappings[i]
+			}
+		}
+	}
+	return nil
+}
+
+// applyRoleMapping sets the mapped role and sub-role on the user and
+// reports whether anything changed.
+func applyRoleMapping(user *entity.User, mapping *RoleMapping) bool {
+	if derefString(user.RoleID) == mapping.RoleID && derefString(user.SubRoleID) == mapping.SubRoleID {
+		return false
+	}
+
+	roleID := mapping.RoleID
+	user.RoleID, user.SubRoleID = &roleID, nil
+	if mapping.SubRoleID != "" {
+		subRoleID := mapping.SubRoleID
+		user.SubRoleID = &subRoleID
+	}
+	return true
+}
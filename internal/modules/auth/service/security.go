@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// SecurityService defines the security administration interface.
+type SecurityService interface {
+	ListEvents(ctx context.Context, query *dto.SecurityEventQuery, params *query.Params) ([]*entity.SecurityEvent, int64, error)
+	Unlock(ctx context.Context, actorID string, req *dto.UnlockRequest) error
+}
+
+type securityService struct {
+	attemptRepo repository.LoginAttemptRepository
+	eventRepo   repository.SecurityEventRepository
+}
+
+// NewSecurityService creates a new security service.
+func NewSecurityService(
+	attemptRepo repository.LoginAttemptRepository,
+	eventRepo repository.SecurityEventRepository,
+) SecurityService {
+	return &securityService{
+		attemptRepo: attemptRepo,
+		eventRepo:   eventRepo,
+	}
+}
+
+func (s *securityService) ListEvents(ctx context.Context, query *dto.SecurityEventQuery, params *query.Params) ([]*entity.SecurityEvent, int64, error) {
+	filter := repository.SecurityEventFilter{
+		EventType: query.EventType,
+		UserID:    query.UserID,
+		Email:     normalizeEmail(query.Email),
+		IPAddress: query.IPAddress,
+		From:      query.From,
+		To:        query.To,
+	}
+
+	events, total, err := s.eventRepo.List(ctx, filter, params)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch security events", 500)
+	}
+
+	return events, total, nil
+}
+
+func (s *securityService) Unlock(ctx context.Context, actorID string, req *dto.UnlockRequest) error {
+	email := normalizeEmail(req.Email)
+
+	if err := s.attemptRepo.Unlock(ctx, email); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Login attempt store unavailable", http.StatusServiceUnavailable)
+	}
+
+	recordSecurityEvent(ctx, s.eventRepo, entity.SecurityEventAccountUnlocked, "manual", nil, email, &actorID, req.ClientInfo)
+	return nil
+}
+
+// recordSecurityEvent writes a security event, logging instead of
+// returning write failures.
+func recordSecurityEvent(
+	ctx context.Context,
+	repo repository.SecurityEventRepository,
+	eventType, reason string,
+	userID *string,
+	email string,
+	actorID *string,
+	client dto.ClientInfo,
+) {
+	event := &entity.SecurityEvent{
+		EventType: eventType,
+		UserID:    userID,
+		ActorID:   actorID,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+	}
+	if reason != "" {
+		event.Reason = &reason
+	}
+	if email != "" {
+		event.Email = &email
+	}
+	if requestID, ok := ctx.Value(logger.RequestIDKey).(string); ok {
+		event.RequestID = requestID
+	}
+
+	if err := repo.Create(ctx, event); err != nil {
+		logger.Warn(ctx, "Failed to record security event",
+			zap.String("event_type", eventType),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,524 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/middleware"
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/internal/shared/permission"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// API key format: gbk_<prefix>_<secret>. The prefix identifies the key in
+// the database; only the SHA-256 of the whole key is stored.
+const (
+	apiKeyScheme       = "gbk"
+	apiKeyPrefixBytes  = 8
+	apiKeySecretBytes  = 32
+	apiKeyCacheTTL     = 5 * time.Minute
+	apiKeyUsedInterval = time.Minute
+	defaultStaleDays   = 90
+)
+
+// ServiceAccountService defines the service account administration interface.
+// It also authenticates API keys for the JWT middleware.
+type ServiceAccountService interface {
+	List(ctx context.Context, params *query.Params) ([]*dto.ServiceAccountResponse, int64, error)
+	Get(ctx context.Context, id string) (*dto.ServiceAccountResponse, error)
+	Create(ctx context.Context, actorID string, req *dto.CreateServiceAccountRequest) (*dto.ServiceAccountResponse, error)
+	Update(ctx context.Context, actorID, id string, req *dto.UpdateServiceAccountRequest) (*dto.ServiceAccountResponse, error)
+	Delete(ctx context.Context, actorID, id string) error
+
+	CreateKey(ctx context.Context, actorID string, actorPermissions []string, accountID string, req *dto.CreateAPIKeyRequest) (*dto.IssuedAPIKeyResponse, error)
+	RotateKey(ctx context.Context, actorID string, actorPermissions []string, accountID, keyID string, req *dto.RotateAPIKeyRequest) (*dto.IssuedAPIKeyResponse, error)
+	RevokeKey(ctx context.Context, accountID, keyID string) error
+	ListStaleKeys(ctx context.Context, query *dto.StaleAPIKeyQuery, params *query.Params) ([]*dto.APIKeyResponse, int64, error)
+
+	AuthenticateAPIKey(ctx context.Context, key string) (*middleware.APIKeyPrincipal, error)
+}
+
+type serviceAccountService struct {
+	repo repository.ServiceAccountRepository
+}
+
+// NewServiceAccountService creates a new service account service.
+func NewServiceAccountService(repo repository.ServiceAccountRepository) ServiceAccountService {
+	return &serviceAccountService{repo: repo}
+}
+
+func (s *serviceAccountService) List(ctx context.Context, params *query.Params) ([]*dto.ServiceAccountResponse, int64, error) {
+	accounts, total, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch service accounts", 500)
+	}
+
+	items := make([]*dto.ServiceAccountResponse, len(accounts))
+	for i, account := range accounts {
+		items[i] = toServiceAccountResponse(account, nil)
+	}
+
+	return items, total, nil
+}
+
+func (s *serviceAccountService) Get(ctx context.Context, id string) (*dto.ServiceAccountResponse, error) {
+	account, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.ListKeys(ctx, account.ID)
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch API keys", 500)
+	}
+
+	return toServiceAccountResponse(account, keys), nil
+}
+
+func (s *serviceAccountService) Create(ctx context.Context, actorID string, req *dto.CreateServiceAccountRequest) (*dto.ServiceAccountResponse, error) {
+	if err := s.checkName(ctx, req.Name, ""); err != nil {
+		return nil, err
+	}
+
+	account := &entity.ServiceAccount{
+		Name:        req.Name,
+		Description: req.Description,
+		IsActive:    true,
+	}
+	account.CreatedBy, account.UpdatedBy = &actorID, &actorID
+
+	if err := s.repo.Create(ctx, account); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create service account", 500)
+	}
+
+	return toServiceAccountResponse(account, nil), nil
+}
+
+func (s *serviceAccountService) Update(ctx context.Context, actorID, id string, req *dto.UpdateServiceAccountRequest) (*dto.ServiceAccountResponse, error) {
+	account, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && *req.Name != account.Name {
+		if err := s.checkName(ctx, *req.Name, account.ID); err != nil {
+			return nil, err
+		}
+		account.Name = *req.Name
+	}
+	if req.Description != nil {
+		account.Description = req.Description
+	}
+	if req.IsActive != nil {
+		account.IsActive = *req.IsActive
+	}
+	account.UpdatedBy = &actorID
+
+	if err := s.repo.Update(ctx, account); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to update service account", 500)
+	}
+
+	// Deactivation must take effect immediately, not when the cache expires
+	if err := s.uncacheAccountKeys(ctx, account.ID); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, account.ID)
+}
+
+func (s *serviceAccountService) Delete(ctx context.Context, actorID, id string) error {
+	account, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, account.ID, actorID); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to delete service account", 500)
+	}
+
+	return s.uncacheAccountKeys(ctx, account.ID)
+}
+
+func (s *serviceAccountService) CreateKey(ctx context.Context, actorID string, actorPermissions []string, accountID string, req *dto.CreateAPIKeyRequest) (*dto.IssuedAPIKeyResponse, error) {
+	account, err := s.find(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := checkScopes(req.Scopes, actorPermissions)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, apperror.Validation("Validation failed", []validator.ValidationError{
+			{Field: "expires_at", Message: "Value must be in the future"},
+		})
+	}
+
+	plaintext, key, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key.ServiceAccountID = account.ID
+	key.Name = req.Name
+	key.Scopes = strings.Join(scopes, " ")
+	key.ExpiresAt = req.ExpiresAt
+	key.CreatedBy = &actorID
+
+	if err := s.repo.CreateKey(ctx, key); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create API key", 500)
+	}
+
+	return &dto.IssuedAPIKeyResponse{APIKeyResponse: toAPIKeyResponse(key), Key: plaintext}, nil
+}
+
+func (s *serviceAccountService) RotateKey(ctx context.Context, actorID string, actorPermissions []string, accountID, keyID string, req *dto.RotateAPIKeyRequest) (*dto.IssuedAPIKeyResponse, error) {
+	old, err := s.findKey(ctx, accountID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	// The caller receives a fresh secret, so they must be able to grant its scopes.
+	if !containsAll(actorPermissions, strings.Fields(old.Scopes)) {
+		return nil, apperror.Forbidden("You cannot rotate a key with scopes you do not hold")
+	}
+
+	now := time.Now()
+	if !old.Usable(now) {
+		return nil, apperror.Conflict("API key is revoked or expired")
+	}
+
+	expiresAt := req.ExpiresAt
+	if expiresAt == nil && old.ExpiresAt != nil {
+		inherited := now.Add(old.ExpiresAt.Sub(old.CreatedAt))
+		expiresAt = &inherited
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, apperror.Validation("Validation failed", []validator.ValidationError{
+			{Field: "expires_at", Message: "Value must be in the future"},
+		})
+	}
+
+	plaintext, key, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key.ServiceAccountID = old.ServiceAccountID
+	key.Name = old.Name
+	key.Scopes = old.Scopes
+	key.ExpiresAt = expiresAt
+	key.RotatedFrom = &old.ID
+	key.CreatedBy = &actorID
+
+	// The old key keeps working until the end of the grace period,
+	// but never longer than it would have anyway.
+	oldExpiresAt := now.Add(time.Duration(req.GraceMinutes) * time.Minute)
+	if old.ExpiresAt != nil && old.ExpiresAt.Before(oldExpiresAt) {
+		oldExpiresAt = *old.ExpiresAt
+	}
+
+	if err := s.repo.RotateKey(ctx, key, old.ID, oldExpiresAt); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to rotate API key", 500)
+	}
+	if err := s.uncache(ctx, old.Prefix); err != nil {
+		return nil, err
+	}
+
+	return &dto.IssuedAPIKeyResponse{APIKeyResponse: toAPIKeyResponse(key), Key: plaintext}, nil
+}
+
+func (s *serviceAccountService) RevokeKey(ctx context.Context, accountID, keyID string) error {
+	key, err := s.findKey(ctx, accountID, keyID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.RevokeKey(ctx, key.ID); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to revoke API key", 500)
+	}
+
+	return s.uncache(ctx, key.Prefix)
+}
+
+func (s *serviceAccountService) ListStaleKeys(ctx context.Context, query *dto.StaleAPIKeyQuery, params *query.Params) ([]*dto.APIKeyResponse, int64, error) {
+	days := query.UnusedDays
+	if days <= 0 {
+		days = defaultStaleDays
+	}
+
+	keys, total, err := s.repo.ListStaleKeys(ctx, time.Now().AddDate(0, 0, -days), params)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch API keys", 500)
+	}
+
+	items := make([]*dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		items[i] = toAPIKeyResponse(key)
+	}
+
+	return items, total, nil
+}
+
+// AuthenticateAPIKey implements middleware.APIKeyAuthenticator. It returns
+// nil for unknown, revoked or expired keys and keys of inactive accounts.
+func (s *serviceAccountService) AuthenticateAPIKey(ctx context.Context, plaintext string) (*middleware.APIKeyPrincipal, error) {
+	prefix, ok := parseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, nil
+	}
+
+	key, found, err := s.repo.GetCachedKey(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		if key, err = s.loadActiveKey(ctx, prefix); err != nil || key == nil {
+			return nil, err
+		}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(plaintext))) != 1 {
+		return nil, nil
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	s.touchKey(ctx, key.ID)
+
+	return &middleware.APIKeyPrincipal{
+		ServiceAccountID: key.ServiceAccountID,
+		KeyID:            key.ID,
+		Scopes:           key.Scopes,
+	}, nil
+}
+
+// loadActiveKey reads a key from the database and caches it if it is usable.
+func (s *serviceAccountService) loadActiveKey(ctx context.Context, prefix string) (*repository.CachedAPIKey, error) {
+	key, err := s.repo.GetKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.Usable(now) {
+		return nil, nil
+	}
+
+	account, err := s.repo.GetByID(ctx, key.ServiceAccountID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !account.IsActive {
+		return nil, nil
+	}
+
+	cached := &repository.CachedAPIKey{
+		ID:               key.ID,
+		ServiceAccountID: key.ServiceAccountID,
+		KeyHash:          key.KeyHash,
+		Scopes:           key.ScopeList(),
+		ExpiresAt:        key.ExpiresAt,
+	}
+
+	ttl := apiKeyCacheTTL
+	if key.ExpiresAt != nil && key.ExpiresAt.Sub(now) < ttl {
+		ttl = key.ExpiresAt.Sub(now)
+	}
+	if err := s.repo.CacheActiveKey(ctx, prefix, cached, ttl); err != nil {
+		logger.Warn(ctx, "Failed to cache API key", zap.String("key_id", key.ID), zap.Error(err))
+	}
+
+	return cached, nil
+}
+
+// touchKey updates last_used_at at most once per interval. Failures are
+// only logged; they must not fail the request.
+func (s *serviceAccountService) touchKey(ctx context.Context, keyID string) {
+	due, err := s.repo.ShouldTouchKey(ctx, keyID, apiKeyUsedInterval)
+	if err != nil || !due {
+		return
+	}
+	if err := s.repo.TouchKey(ctx, keyID, time.Now()); err != nil {
+		logger.Warn(ctx, "Failed to update API key last used", zap.String("key_id", keyID), zap.Error(err))
+	}
+}
+
+func (s *serviceAccountService) uncache(ctx context.Context, prefixes ...string) error {
+	if err := s.repo.UncacheKeys(ctx, prefixes...); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Key cache unavailable", 503)
+	}
+	return nil
+}
+
+func (s *serviceAccountService) uncacheAccountKeys(ctx context.Context, accountID string) error {
+	keys, err := s.repo.ListKeys(ctx, accountID)
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch API keys", 500)
+	}
+
+	prefixes := make([]string, len(keys))
+	for i, key := range keys {
+		prefixes[i] = key.Prefix
+	}
+	return s.uncache(ctx, prefixes...)
+}
+
+func (s *serviceAccountService) find(ctx context.Context, id string) (*entity.ServiceAccount, error) {
+	account, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("Service account not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch service account", 500)
+	}
+	return account, nil
+}
+
+func (s *serviceAccountService) findKey(ctx context.Context, accountID, keyID string) (*entity.APIKey, error) {
+	if _, err := s.find(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	key, err := s.repo.GetKey(ctx, accountID, keyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("API key not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch API key", 500)
+	}
+	return key, nil
+}
+
+func (s *serviceAccountService) checkName(ctx context.Context, name, excludeID string) error {
+	taken, err := s.repo.NameTaken(ctx, name, excludeID)
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check name", 500)
+	}
+	if taken {
+		return apperror.Conflict("Service account name already in use")
+	}
+	return nil
+}
+
+// checkScopes rejects unknown permission codes and codes the caller does
+// not hold, so nobody can mint a key more powerful than themselves.
+func checkScopes(scopes, actorPermissions []string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, code := range permission.Codes() {
+		known[code] = true
+	}
+	held := make(map[string]bool)
+	for _, code := range actorPermissions {
+		held[code] = true
+	}
+
+	var result []string
+	seen := make(map[string]bool)
+	var errs []validator.ValidationError
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		switch {
+		case !known[scope]:
+			errs = append(errs, validator.ValidationError{Field: "scopes", Message: "Unknown scope " + scope})
+		case !held[scope]:
+			errs = append(errs, validator.ValidationError{Field: "scopes", Message: "You cannot grant a scope you do not hold: " + scope})
+		case !seen[scope]:
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, apperror.Validation("Validation failed", errs)
+	}
+	return result, nil
+}
+
+// newAPIKey generates a key and returns its plaintext once.
+func newAPIKey() (string, *entity.APIKey, error) {
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	secretBytes := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", nil, apperror.Internal("Failed to generate API key")
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", nil, apperror.Internal("Failed to generate API key")
+	}
+
+	prefix := hex.EncodeToString(prefixBytes)
+	plaintext := apiKeyScheme + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	return plaintext, &entity.APIKey{Prefix: prefix, KeyHash: hashAPIKey(plaintext)}, nil
+}
+
+func parseAPIKeyPrefix(plaintext string) (string, bool) {
+	scheme, rest, ok := strings.Cut(plaintext, "_")
+	if !ok || scheme != apiKeyScheme {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != apiKeyPrefixBytes*2 || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+func toServiceAccountResponse(account *entity.ServiceAccount, keys []*entity.APIKey) *dto.ServiceAccountResponse {
+	resp := &dto.ServiceAccountResponse{
+		ID:          account.ID,
+		Name:        account.Name,
+		Description: account.Description,
+		IsActive:    account.IsActive,
+		CreatedAt:   account.CreatedAt,
+		UpdatedAt:   account.UpdatedAt,
+		CreatedBy:   account.CreatedBy,
+		UpdatedBy:   account.UpdatedBy,
+	}
+	if keys != nil {
+		resp.Keys = make([]*dto.APIKeyResponse, len(keys))
+		for i, key := range keys {
+			resp.Keys[i] = toAPIKeyResponse(key)
+		}
+	}
+	return resp
+}
+
+func toAPIKeyResponse(key *entity.APIKey) *dto.APIKeyResponse {
+	return &dto.APIKeyResponse{
+		ID:               key.ID,
+		ServiceAccountID: key.ServiceAccountID,
+		Name:             key.Name,
+		Prefix:           key.Prefix,
+		Scopes:           key.ScopeList(),
+		ExpiresAt:        key.ExpiresAt,
+		LastUsedAt:       key.LastUsedAt,
+		RevokedAt:        key.RevokedAt,
+		RotatedFrom:      key.RotatedFrom,
+		CreatedBy:        key.CreatedBy,
+		CreatedAt:        key.CreatedAt,
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// sessionSeenInterval limits last_seen_at writes per session.
+const sessionSeenInterval = time.Minute
+
+// maxUserAgentLength matches sys_user_sessions.user_agent.
+const maxUserAgentLength = 512
+
+// SessionPolicy holds the concurrent session settings.
+type SessionPolicy struct {
+	// MaxPerUser caps active sessions per user; the least recently used
+	// sessions are signed out when a new login exceeds it. Zero or negative
+	// disables the cap.
+	MaxPerUser int
+}
+
+// startSession issues the first token pair of a new login and records it
+// as a session. The session ID is the token family.
+func (s *authService) startSession(ctx context.Context, user *entity.User, client dto.ClientInfo) (*dto.AuthResponse, error) {
+	familyID := newFamilyID()
+
+	tokens, tokenID, err := s.issueTokenPair(user, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &entity.Session{
+		ID:         familyID,
+		UserID:     user.ID,
+		TokenID:    tokenID,
+		UserAgent:  truncate(client.UserAgent, maxUserAgentLength),
+		IPAddress:  client.IPAddress,
+		LastSeenAt: &now,
+		ExpiresAt:  time.Unix(tokens.RefreshExpiresAt, 0),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create session", 500)
+	}
+
+	// A failed cache write is recovered from the database on first use.
+	if err := s.sessionRepo.CacheActive(ctx, session.ID, user.ID, s.tokens.RefreshExpiry); err != nil {
+		logger.Warn(ctx, "Failed to cache session", zap.String("session_id", session.ID), zap.Error(err))
+	}
+
+	if err := s.enforceSessionLimit(ctx, user.ID, session.ID); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// enforceSessionLimit signs out the least recently used sessions beyond the cap.
+func (s *authService) enforceSessionLimit(ctx context.Context, userID, keepID string) error {
+	if s.sessions.MaxPerUser <= 0 {
+		return nil
+	}
+
+	sessions, err := s.sessionRepo.ListActive(ctx, userID)
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to list sessions", 500)
+	}
+	if len(sessions) <= s.sessions.MaxPerUser {
+		return nil
+	}
+
+	// Sessions are ordered most recently used first; the new one is always kept.
+	var evict []string
+	kept := 1
+	for _, session := range sessions {
+		if session.ID == keepID {
+			continue
+		}
+		if kept < s.sessions.MaxPerUser {
+			kept++
+			continue
+		}
+		evict = append(evict, session.ID)
+	}
+
+	return s.revokeSessions(ctx, evict, entity.SessionRevokedLimit)
+}
+
+// revokeSessions ends sessions in the database, drops them from the cache
+// and revokes their token families so their refresh tokens stop working.
+func (s *authService) revokeSessions(ctx context.Context, ids []string, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, ids, reason); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to revoke sessions", 500)
+	}
+
+	if err := s.sessionRepo.Uncache(ctx, ids...); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+	}
+	for _, id := range ids {
+		if err := s.tokenRepo.RevokeFamily(ctx, id, s.tokens.RefreshExpiry); err != nil {
+			return apperror.Wrap(err, apperror.ErrCodeServiceUnavailable, "Token store unavailable", http.StatusServiceUnavailable)
+		}
+	}
+
+	return nil
+}
+
+// ValidateSession implements middleware.SessionValidator. Active sessions
+// are answered from Redis; on a cache miss the database is consulted and
+// the cache repopulated.
+func (s *authService) ValidateSession(ctx context.Context, sessionID, userID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+
+	owner, found, err := s.sessionRepo.GetCachedOwner(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	if !found {
+		session, err := s.sessionRepo.GetByID(ctx, sessionID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return false, nil
+			}
+			return false, err
+		}
+		if !sessionActive(session) {
+			return false, nil
+		}
+		owner = session.UserID
+		if err := s.sessionRepo.CacheActive(ctx, session.ID, owner, time.Until(session.ExpiresAt)); err != nil {
+			logger.Warn(ctx, "Failed to cache session", zap.String("session_id", session.ID), zap.Error(err))
+		}
+	}
+
+	if owner != userID {
+		return false, nil
+	}
+
+	s.markSessionSeen(ctx, sessionID)
+	return true, nil
+}
+
+// markSessionSeen updates last_seen_at at most once per interval. Failures
+// are only logged; they must not fail the request.
+func (s *authService) markSessionSeen(ctx context.Context, sessionID string) {
+	due, err := s.sessionRepo.ShouldMarkSeen(ctx, sessionID, sessionSeenInterval)
+	if err != nil || !due {
+		return
+	}
+	if err := s.sessionRepo.MarkSeen(ctx, sessionID, time.Now()); err != nil {
+		logger.Warn(ctx, "Failed to update session last seen", zap.String("session_id", sessionID), zap.Error(err))
+	}
+}
+
+// ListSessions returns the user's active sessions, flagging the current one.
+func (s *authService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]dto.SessionResponse, error) {
+	sessions, err := s.sessionRepo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to list sessions", 500)
+	}
+
+	result := make([]dto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		result[i] = dto.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			IssuedAt:   session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentSessionID,
+		}
+	}
+	return result, nil
+}
+
+// RevokeSession signs out one of the user's own sessions.
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID string, client dto.ClientInfo) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return apperror.NotFound("Session not found")
+		}
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch session", 500)
+	}
+	if session.UserID != userID || !sessionActive(session) {
+		return apperror.NotFound("Session not found")
+	}
+
+	if err := s.revokeSessions(ctx, []string{session.ID}, entity.SessionRevokedByUser); err != nil {
+		return err
+	}
+
+	s.recordEvent(ctx, entity.SecurityEventSessionRevoked, "", &userID, "", &userID, client)
+	return nil
+}
+
+// RevokeAllSessions signs a user out on every device.
+func (s *authService) RevokeAllSessions(ctx context.Context, actorID, userID string, client dto.ClientInfo) (*dto.RevokeSessionsResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("User not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+
+	ids, err := s.activeSessionIDs(ctx, user.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, ids, entity.SessionRevokedSignOutAll); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, entity.SecurityEventSessionsRevoked, "", &user.ID, normalizeEmail(user.Email), &actorID, client)
+	return &dto.RevokeSessionsResponse{Revoked: len(ids)}, nil
+}
+
+// RevokeUserSessions ends every active session of a user except keepID
+// (empty to end all), e.g. after a role or password change. Their access
+// tokens stop working with the session.
+func (s *authService) RevokeUserSessions(ctx context.Context, userID, keepID, reason string) error {
+	ids, err := s.activeSessionIDs(ctx, userID, keepID)
+	if err != nil {
+		return err
+	}
+	return s.revokeSessions(ctx, ids, reason)
+}
+
+func (s *authService) activeSessionIDs(ctx context.Context, userID, exceptID string) ([]string, error) {
+	sessions, err := s.sessionRepo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to list sessions", 500)
+	}
+
+	ids := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if session.ID != exceptID {
+			ids = append(ids, session.ID)
+		}
+	}
+	return ids, nil
+}
+
+func sessionActive(session *entity.Session) bool {
+	return session.RevokedAt == nil && session.ExpiresAt.After(time.Now())
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.ToValidUTF8(s[:max], "")
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+
+	"github.com/user/go-boilerplate/internal/modules/auth/dto"
+	"github.com/user/go-boilerplate/internal/modules/auth/entity"
+	"github.com/user/go-boilerplate/internal/modules/auth/repository"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// UserService defines the user administration interface.
+type UserService interface {
+	List(ctx context.Context, query *dto.UserQuery, params *query.Params) ([]*dto.AdminUserResponse, int64, error)
+	Get(ctx context.Context, id string) (*dto.AdminUserResponse, error)
+	Create(ctx context.Context, actorID string, req *dto.CreateUserRequest) (*dto.AdminUserResponse, error)
+	Update(ctx context.Context, actorID, id string, req *dto.UpdateUserRequest) (*dto.AdminUserResponse, error)
+	SetActive(ctx context.Context, actorID, id string, req *dto.SetUserStatusRequest) (*dto.AdminUserResponse, error)
+	Delete(ctx context.Context, actorID, id string, actorPermissions []string) error
+}
+
+// SessionRevoker signs a user out; AuthService implements it.
+type SessionRevoker interface {
+	RevokeUserSessions(ctx context.Context, userID, keepID, reason string) error
+}
+
+type userService struct {
+	userRepo       repository.UserRepository
+	assignmentRepo repository.AssignmentRepository
+	permissionRepo repository.PermissionRepository
+	sessions       SessionRevoker
+	passwords      *PasswordManager
+}
+
+// NewUserService creates a new user administration service.
+func NewUserService(
+	userRepo repository.UserRepository,
+	assignmentRepo repository.AssignmentRepository,
+	permissionRepo repository.PermissionRepository,
+	sessions SessionRevoker,
+	passwords *PasswordManager,
+) UserService {
+	return &userService{
+		userRepo:       userRepo,
+		assignmentRepo: assignmentRepo,
+		permissionRepo: permissionRepo,
+		sessions:       sessions,
+		passwords:      passwords,
+	}
+}
+
+func (s *userService) List(ctx context.Context, query *dto.UserQuery, params *query.Params) ([]*dto.AdminUserResponse, int64, error) {
+	filter := repository.UserFilter{
+		RoleID:     query.RoleID,
+		SubRoleID:  query.SubRoleID,
+		BranchID:   query.BranchID,
+		DivisionID: query.DivisionID,
+		IsActive:   query.IsActive,
+	}
+
+	users, total, err := s.userRepo.List(ctx, filter, params)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch users", 500)
+	}
+
+	items := make([]*dto.AdminUserResponse, len(users))
+	for i, user := range users {
+		items[i] = toAdminUserResponse(user)
+	}
+
+	return items, total, nil
+}
+
+func (s *userService) Get(ctx context.Context, id string) (*dto.AdminUserResponse, error) {
+	user, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toAdminUserResponse(user), nil
+}
+
+func (s *userService) Create(ctx context.Context, actorID string, req *dto.CreateUserRequest) (*dto.AdminUserResponse, error) {
+	if err := s.checkEmail(ctx, req.Email, ""); err != nil {
+		return nil, err
+	}
+
+	user := &entity.User{
+		Email:          req.Email,
+		FullName:       req.FullName,
+		EmployeeNumber: req.EmployeeNumber,
+		BranchID:       req.BranchID,
+		DivisionID:     req.DivisionID,
+		DivisionName:   req.DivisionName,
+		RoleID:         req.RoleID,
+		SubRoleID:      req.SubRoleID,
+		IsActive:       req.IsActive == nil || *req.IsActive,
+	}
+	if err := checkAssignments(ctx, s.assignmentRepo, user); err != nil {
+		return nil, err
+	}
+	if err := checkGrantable(ctx, s.permissionRepo, req.ActorPermissions, user.RoleID, user.SubRoleID); err != nil {
+		return nil, err
+	}
+
+	if err := s.passwords.Validate(ctx, "password", req.Password, nil); err != nil {
+		return nil, err
+	}
+	if err := s.passwords.Apply(user, req.Password); err != nil {
+		return nil, err
+	}
+	// An administrator chose this password, so by default the user replaces it at first login
+	user.MustChangePassword = req.MustChangePassword == nil || *req.MustChangePassword
+	user.CreatedBy, user.UpdatedBy = &actorID, &actorID
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to create user", 500)
+	}
+	if err := s.passwords.Record(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return toAdminUserResponse(user), nil
+}
+
+func (s *userService) Update(ctx context.Context, actorID, id string, req *dto.UpdateUserRequest) (*dto.AdminUserResponse, error) {
+	user, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkManageable(ctx, s.permissionRepo, req.ActorPermissions, user); err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil && *req.Email != user.Email {
+		if err := s.checkEmail(ctx, *req.Email, user.ID); err != nil {
+			return nil, err
+		}
+		user.Email = *req.Email
+	}
+	if req.FullName != nil {
+		user.FullName = *req.FullName
+	}
+	roleID, subRoleID, branchID := derefString(user.RoleID), derefString(user.SubRoleID), derefString(user.BranchID)
+	applyOptional(&user.EmployeeNumber, req.EmployeeNumber)
+	applyOptional(&user.BranchID, req.BranchID)
+	applyOptional(&user.DivisionID, req.DivisionID)
+	applyOptional(&user.DivisionName, req.DivisionName)
+	applyOptional(&user.RoleID, req.RoleID)
+	applyOptional(&user.SubRoleID, req.SubRoleID)
+
+	// A role change without a sub-role invalidates the old sub-role.
+	if req.RoleID != nil && req.SubRoleID == nil {
+		user.SubRoleID = nil
+	}
+
+	if err := checkAssignments(ctx, s.assignmentRepo, user); err != nil {
+		return nil, err
+	}
+	roleChanged := derefString(user.RoleID) != roleID || derefString(user.SubRoleID) != subRoleID
+	if roleChanged {
+		if user.ID == actorID {
+			return nil, apperror.BadRequest("You cannot change your own role")
+		}
+		if err := checkGrantable(ctx, s.permissionRepo, req.ActorPermissions, user.RoleID, user.SubRoleID); err != nil {
+			return nil, err
+		}
+	}
+
+	user.UpdatedBy = &actorID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to update user", 500)
+	}
+
+	// Sessions carry the old role and branch in their tokens.
+	if roleChanged || derefString(user.BranchID) != branchID {
+		if err := s.sessions.RevokeUserSessions(ctx, user.ID, "", entity.SessionRevokedAccountChanged); err != nil {
+			return nil, err
+		}
+	}
+
+	return toAdminUserResponse(user), nil
+}
+
+func (s *userService) SetActive(ctx context.Context, actorID, id string, req *dto.SetUserStatusRequest) (*dto.AdminUserResponse, error) {
+	active := *req.IsActive
+	if id == actorID && !active {
+		return nil, apperror.BadRequest("You cannot deactivate your own account")
+	}
+
+	user, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkManageable(ctx, s.permissionRepo, req.ActorPermissions, user); err != nil {
+		return nil, err
+	}
+
+	user.IsActive = active
+	user.UpdatedBy = &actorID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to update user", 500)
+	}
+
+	if !active {
+		if err := s.sessions.RevokeUserSessions(ctx, user.ID, "", entity.SessionRevokedAccountChanged); err != nil {
+			return nil, err
+		}
+	}
+
+	return toAdminUserResponse(user), nil
+}
+
+func (s *userService) Delete(ctx context.Context, actorID, id string, actorPermissions []string) error {
+	if id == actorID {
+		return apperror.BadRequest("You cannot delete your own account")
+	}
+
+	user, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkManageable(ctx, s.permissionRepo, actorPermissions, user); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Delete(ctx, id, actorID); err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to delete user", 500)
+	}
+
+	return s.sessions.RevokeUserSessions(ctx, id, "", entity.SessionRevokedAccountChanged)
+}
+
+func (s *userService) find(ctx context.Context, id string) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("User not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch user", 500)
+	}
+	return user, nil
+}
+
+func (s *userService) checkEmail(ctx context.Context, email, excludeID string) error {
+	taken, err := s.userRepo.EmailTaken(ctx, email, excludeID)
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check email", 500)
+	}
+	if taken {
+		return apperror.Conflict("Email already registered")
+	}
+	return nil
+}
+
+// checkAssignments verifies that the role, sub-role and branch exist and
+// that the sub-role belongs to the role.
+func checkAssignments(ctx context.Context, assignmentRepo repository.AssignmentRepository, user *entity.User) error {
+	var details []validator.ValidationError
+
+	if user.SubRoleID != nil && user.RoleID == nil {
+		details = append(details, validator.ValidationError{Field: "sub_role_id", Message: "A sub-role requires a role"})
+	}
+
+	if user.RoleID != nil {
+		ok, err := assignmentRepo.RoleExists(ctx, *user.RoleID)
+		if err != nil {
+			return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check role", 500)
+		}
+		if !ok {
+			details = append(details, validator.ValidationError{Field: "role_id", Message: "Role not found"})
+		} else if user.SubRoleID != nil {
+			ok, err := assignmentRepo.SubRoleBelongsTo(ctx, *user.SubRoleID, *user.RoleID)
+			if err != nil {
+				return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check sub-role", 500)
+			}
+			if !ok {
+				details = append(details, validator.ValidationError{Field: "sub_role_id", Message: "Sub-role not found for this role"})
+			}
+		}
+	}
+
+	if user.BranchID != nil {
+		ok, err := assignmentRepo.BranchExists(ctx, *user.BranchID)
+		if err != nil {
+			return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check branch", 500)
+		}
+		if !ok {
+			details = append(details, validator.ValidationError{Field: "branch_id", Message: "Branch not found"})
+		}
+	}
+
+	if len(details) > 0 {
+		return apperror.Validation("Validation failed", details)
+	}
+	return nil
+}
+
+// checkGrantable rejects a role or sub-role carrying permissions the actor
+// does not hold, so nobody can make an account more powerful than their own.
+func checkGrantable(ctx context.Context, permissionRepo repository.PermissionRepository, actorPermissions []string, roleID, subRoleID *string) error {
+	ok, err := holdsRole(ctx, permissionRepo, actorPermissions, roleID, subRoleID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperror.Forbidden("You cannot assign a role with permissions you do not hold")
+	}
+	return nil
+}
+
+// checkManageable rejects any change to an account whose current role
+// carries permissions the actor does not hold. Otherwise an administrator
+// could, say, point a superadmin's email at a mailbox they control and take
+// the account over through a password reset.
+func checkManageable(ctx context.Context, permissionRepo repository.PermissionRepository, actorPermissions []string, user *entity.User) error {
+	ok, err := holdsRole(ctx, permissionRepo, actorPermissions, user.RoleID, user.SubRoleID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperror.Forbidden("You cannot manage an account with permissions you do not hold")
+	}
+	return nil
+}
+
+// holdsRole reports whether actorPermissions include every permission of
+// the role and sub-role. No role grants nothing.
+func holdsRole(ctx context.Context, permissionRepo repository.PermissionRepository, actorPermissions []string, roleID, subRoleID *string) (bool, error) {
+	if roleID == nil {
+		return true, nil
+	}
+
+	permissions, err := permissionRepo.ListCodesByRole(ctx, *roleID, derefString(subRoleID))
+	if err != nil {
+		return false, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to resolve permissions", 500)
+	}
+	return containsAll(actorPermissions, permissions), nil
+}
+
+// applyOptional copies a patch value onto a nullable field: nil leaves the
+// field unchanged and an empty string clears it.
+func applyOptional(field **string, value *string) {
+	if value == nil {
+		return
+	}
+	if *value == "" {
+		*field = nil
+		return
+	}
+	v := *value
+	*field = &v
+}
+
+func toAdminUserResponse(user *entity.User) *dto.AdminUserResponse {
+	resp := &dto.AdminUserResponse{
+		ID:                 user.ID,
+		Email:              user.Email,
+		FullName:           user.FullName,
+		EmployeeNumber:     user.EmployeeNumber,
+		BranchID:           user.BranchID,
+		DivisionID:         user.DivisionID,
+		DivisionName:       user.DivisionName,
+		RoleID:             user.RoleID,
+		SubRoleID:          user.SubRoleID,
+		IsActive:           user.IsActive,
+		MFAEnabled:         user.MFAEnabled,
+		MustChangePassword: user.MustChangePassword,
+		PasswordChangedAt:  user.PasswordChangedAt,
+		CreatedAt:          user.CreatedAt,
+		UpdatedAt:          user.UpdatedAt,
+		CreatedBy:          user.CreatedBy,
+		UpdatedBy:          user.UpdatedBy,
+	}
+	if user.DeletedAt.Valid {
+		deletedAt := user.DeletedAt.Time
+		resp.DeletedAt = &deletedAt
+	}
+	return resp
+}
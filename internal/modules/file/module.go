@@ -3,7 +3,9 @@ package file
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/config"
+	"github.com/user/go-boilerplate/internal/middleware"
 	"github.com/user/go-boilerplate/internal/modules/file/handler"
+	"github.com/user/go-boilerplate/internal/shared/permission"
 	"github.com/user/go-boilerplate/pkg/logger"
 	"github.com/user/go-boilerplate/pkg/storage"
 	"go.uber.org/zap"
@@ -40,11 +42,14 @@ func New(cfg *config.Config) *Module {
 
 // RegisterRoutes registers all file routes.
 func (m *Module) RegisterRoutes(api *gin.RouterGroup) {
-	api.GET("/export/csv", m.handler.ExportCSV)
-	api.GET("/export/xlsx", m.handler.ExportXLSX)
-	api.GET("/export/pdf", m.handler.ExportPDF)
-	api.POST("/upload", m.handler.Upload)
-	api.POST("/upload/presigned", m.handler.GetPresignedUploadURL)
-	api.GET("/download/:key", m.handler.Download)
-	api.DELETE("/file/:key", m.handler.Delete)
+	read := middleware.RequirePermission(permission.FileRead)
+	write := middleware.RequirePermission(permission.FileWrite)
+
+	api.GET("/export/csv", read, m.handler.ExportCSV)
+	api.GET("/export/xlsx", read, m.handler.ExportXLSX)
+	api.GET("/export/pdf", read, m.handler.ExportPDF)
+	api.POST("/upload", write, m.handler.Upload)
+	api.POST("/upload/presigned", write, m.handler.GetPresignedUploadURL)
+	api.GET("/download/:key", read, m.handler.Download)
+	api.DELETE("/file/:key", write, m.handler.Delete)
 }
@@ -0,0 +1,26 @@
+package dto
+
+// ImportChange is the old and new value of one field of an updated row.
+type ImportChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// ImportRow is one data row of an import file.
+type ImportRow struct {
+	Row     int                     `json:"row"`          // Line or sheet row number; the header is row 1
+	ID      string                  `json:"id,omitempty"` // Existing row, for updates and unchanged rows
+	Data    map[string]any          `json:"data,omitempty"`
+	Changes map[string]ImportChange `json:"changes,omitempty"`
+	Errors  []string                `json:"errors,omitempty"`
+}
+
+// ImportResult is the diff of an import file against the table. Nothing is
+// written while Rejected is non-empty.
+type ImportResult struct {
+	DryRun    bool        `json:"dry_run"`
+	Inserts   []ImportRow `json:"inserts"`
+	Updates   []ImportRow `json:"updates"`
+	Unchanged []ImportRow `json:"unchanged"`
+	Rejected  []ImportRow `json:"rejected"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/user/go-boilerplate/internal/modules/master/entity"
+
+// Region levels, from the widest to the narrowest.
+const (
+	RegionProvince    = "province"
+	RegionDistrict    = "district"
+	RegionSubDistrict = "sub_district"
+	RegionVillage     = "village"
+)
+
+// RegionChain is a region together with its ancestors. Level names the
+// region that was resolved; the levels below it are omitted. An ancestor
+// is also missing when the row's parent reference is empty or dangling.
+type RegionChain struct {
+	Level       string              `json:"level"`
+	Province    *entity.Province    `json:"province,omitempty"`
+	District    *entity.District    `json:"district,omitempty"`
+	SubDistrict *entity.SubDistrict `json:"sub_district,omitempty"`
+	Village     *entity.Village     `json:"village,omitempty"`
+}
+
+// ValidateRegionRequest is an address's region codes. Any subset may be
+// given, but they must all lie on the chain of the narrowest one.
+type ValidateRegionRequest struct {
+	ProvinceCode    string `json:"province_code" validate:"omitempty,max=15"`
+	DistrictCode    string `json:"district_code" validate:"omitempty,max=15"`
+	SubDistrictCode string `json:"sub_district_code" validate:"omitempty,max=15"`
+	VillageCode     string `json:"village_code" validate:"omitempty,max=15"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// TableVersion identifies the content of a master table: every write bumps
+// Modified, and Rows catches inserts carrying older timestamps.
+type TableVersion struct {
+	Rows     int64     // Live and soft-deleted rows
+	Modified time.Time // Latest updated_at or deleted_at, zero for an empty table
+}
+
+// Tombstone marks a row soft-deleted since the requested timestamp.
+type Tombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Delta is what changed in one master table since the requested timestamp.
+type Delta struct {
+	Changed any         `json:"changed"` // Live rows created or updated
+	Deleted []Tombstone `json:"deleted"`
+}
+
+// DeltaResponse is the result of GET /api/master/all?since=.
+type DeltaResponse struct {
+	Until time.Time         `json:"until"` // Pass as since on the next sync
+	Types map[string]*Delta `json:"types"`
+}
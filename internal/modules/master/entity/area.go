@@ -1,9 +1,12 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type Area struct {
-	ID   string `json:"id" gorm:"primaryKey"`
-	Code string `json:"code"`
-	Name string `json:"name"`
+	sharedentity.Base
+	Code     string `json:"code" validate:"required,max=50"`
+	Name     string `json:"name" validate:"required,max=255"`
+	IsActive bool   `json:"is_active"`
 }
 
 func (Area) TableName() string { return "mst_areas" }
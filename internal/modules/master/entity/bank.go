@@ -1,9 +1,16 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type Bank struct {
-	ID   string `json:"id" gorm:"primaryKey"`
-	Code string `json:"code"`
-	Name string `json:"name"`
+	sharedentity.Base
+	Code         string  `json:"code" validate:"required,max=10"`
+	Name         string  `json:"name" validate:"required,max=255"`
+	OriginalCode *string `json:"original_code" validate:"omitempty,max=50"`
+	Description  *string `json:"description" validate:"omitempty,max=255"`
+	Category     *string `json:"category" validate:"omitempty,max=100"`
+	SubCategory  *string `json:"sub_category" validate:"omitempty,max=100"`
+	IsSharia     bool    `json:"is_sharia"`
 }
 
 func (Bank) TableName() string { return "mst_banks" }
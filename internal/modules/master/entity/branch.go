@@ -1,9 +1,21 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type Branch struct {
-	ID   string `json:"id" gorm:"primaryKey"`
-	Code string `json:"code"`
-	Name string `json:"name"`
+	sharedentity.Base
+	Code         string  `json:"code" validate:"required,max=255"`
+	Description  string  `json:"description" validate:"required,max=255"`
+	Address      string  `json:"address" validate:"required,max=255"`
+	Phone        string  `json:"phone" validate:"required,max=255"`
+	Phone2       *string `json:"phone2" gorm:"column:phone2" validate:"omitempty,max=255"`
+	BranchType   int16   `json:"branch_type" validate:"omitempty,oneof=1 2"` // 1 head office, 2 regional
+	IsDefault    bool    `json:"is_default"`
+	IsActive     bool    `json:"is_active"`
+	Flag         int16   `json:"flag"`
+	SortOrder    int     `json:"sort_order"`
+	AreaID       string  `json:"area_id" gorm:"type:uuid" validate:"required,uuid"`
+	MainBranchID *string `json:"main_branch_id" gorm:"type:uuid" validate:"omitempty,uuid"`
 }
 
 func (Branch) TableName() string { return "mst_branches" }
@@ -1,10 +1,12 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type Currency struct {
-	ID     string `json:"id" gorm:"primaryKey"`
-	Code   string `json:"code"`
-	Name   string `json:"name"`
-	Symbol string `json:"symbol"`
+	sharedentity.Base
+	Code        string  `json:"code" validate:"required,max=5"`
+	Description *string `json:"description" validate:"omitempty,max=30"`
+	SortOrder   int     `json:"sort_order"`
 }
 
 func (Currency) TableName() string { return "mst_currencies" }
@@ -1,10 +1,14 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type District struct {
-	ID         string `json:"id" gorm:"primaryKey"`
-	ProvinceID string `json:"province_id"`
-	Code       string `json:"code"`
-	Name       string `json:"name"`
+	sharedentity.Base
+	Code         string  `json:"code" validate:"required,max=15"`
+	Description  *string `json:"description" validate:"omitempty,max=100"`
+	SortOrder    int     `json:"sort_order"`
+	ProvinceCode *string `json:"province_code" validate:"omitempty,max=15"` // Legacy reference
+	ProvinceID   *string `json:"province_id" gorm:"type:uuid" validate:"omitempty,uuid"`
 }
 
 func (District) TableName() string { return "mst_districts" }
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+type EffectiveTaxRateLayer struct {
+	sharedentity.Base
+	EffectiveTaxRateCategoryID *string   `json:"effective_tax_rate_category_id" gorm:"type:uuid" validate:"omitempty,uuid"`
+	LegacyCategoryID           *int64    `json:"legacy_category_id"`
+	LogicOperator              *string   `json:"logic_operator" validate:"omitempty,max=10"`
+	MinimumAmount              float64   `json:"minimum_amount" validate:"min=0"`
+	MaximumAmount              float64   `json:"maximum_amount" validate:"min=0"`
+	TaxRate                    float64   `json:"tax_rate" validate:"min=0,max=100"` // Percentage, e.g. 5.00
+	EffectiveDate              time.Time `json:"effective_date" gorm:"type:date" validate:"required"`
+	IsActive                   bool      `json:"is_active"`
+}
+
+func (EffectiveTaxRateLayer) TableName() string { return "mst_effective_tax_rate_layers" }
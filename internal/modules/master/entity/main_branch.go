@@ -0,0 +1,14 @@
+package entity
+
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
+type MainBranch struct {
+	sharedentity.Base
+	Code      string `json:"code" validate:"required,max=255"`
+	Name      string `json:"name" validate:"required,max=255"`
+	IsActive  bool   `json:"is_active"`
+	SortOrder int    `json:"sort_order"`
+	AreaID    string `json:"area_id" gorm:"type:uuid" validate:"required,uuid"`
+}
+
+func (MainBranch) TableName() string { return "mst_main_branches" }
@@ -1,9 +1,11 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type MaritalStatus struct {
-	ID   string `json:"id" gorm:"primaryKey"`
-	Code string `json:"code"`
-	Name string `json:"name"`
+	sharedentity.Base
+	Description string `json:"description" validate:"required,max=30"`
+	SortOrder   int    `json:"sort_order"`
 }
 
 func (MaritalStatus) TableName() string { return "mst_marital_statuses" }
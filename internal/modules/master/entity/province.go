@@ -1,9 +1,12 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type Province struct {
-	ID   string `json:"id" gorm:"primaryKey"`
-	Code string `json:"code"`
-	Name string `json:"name"`
+	sharedentity.Base
+	Code        string  `json:"code" validate:"required,max=15"`
+	Description *string `json:"description" validate:"omitempty,max=100"`
+	SortOrder   int     `json:"sort_order"`
 }
 
 func (Province) TableName() string { return "mst_provinces" }
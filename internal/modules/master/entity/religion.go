@@ -1,9 +1,10 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type Religion struct {
-	ID   string `json:"id" gorm:"primaryKey"`
-	Code string `json:"code"`
-	Name string `json:"name"`
+	sharedentity.Base
+	Description string `json:"description" validate:"required,max=30"`
 }
 
 func (Religion) TableName() string { return "mst_religions" }
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+type SeveranceBenefit struct {
+	sharedentity.Base
+	TerminationReason string    `json:"termination_reason" validate:"required,max=255"`
+	SeverancePay      float64   `json:"severance_pay" validate:"min=0"` // Uang Pesangon
+	ServicePay        float64   `json:"service_pay" validate:"min=0"`   // UPMK
+	EffectiveDate     time.Time `json:"effective_date" gorm:"type:date" validate:"required"`
+}
+
+func (SeveranceBenefit) TableName() string { return "mst_severance_benefits" }
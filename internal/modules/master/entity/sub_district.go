@@ -0,0 +1,15 @@
+package entity
+
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
+type SubDistrict struct {
+	sharedentity.Base
+	Code         string  `json:"code" validate:"required,max=15"`
+	Description  *string `json:"description" validate:"omitempty,max=100"`
+	SortOrder    int     `json:"sort_order"`
+	ProvinceCode *string `json:"province_code" validate:"omitempty,max=15"` // Legacy reference
+	DistrictCode *string `json:"district_code" validate:"omitempty,max=15"` // Legacy reference
+	DistrictID   *string `json:"district_id" gorm:"type:uuid" validate:"omitempty,uuid"`
+}
+
+func (SubDistrict) TableName() string { return "mst_sub_districts" }
@@ -1,10 +1,20 @@
 package entity
 
+import (
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
 type TaxBracket struct {
-	ID         string  `json:"id" gorm:"primaryKey"`
-	MinAmount  float64 `json:"min_amount"`
-	MaxAmount  float64 `json:"max_amount"`
-	Percentage float64 `json:"percentage"`
+	sharedentity.Base
+	MinIncome                *float64   `json:"min_income" validate:"omitempty,min=0"`
+	MaxIncome                *float64   `json:"max_income" validate:"omitempty,min=0"`
+	TaxRate                  float64    `json:"tax_rate" validate:"min=0,max=100"` // Percentage, e.g. 5.00
+	EffectiveTaxRateCategory *string    `json:"effective_tax_rate_category" validate:"omitempty,max=255"`
+	EffectiveDate            *time.Time `json:"effective_date" gorm:"type:date"`
+	LogicOperator            *string    `json:"logic_operator" validate:"omitempty,max=255"`
+	IsActive                 bool       `json:"is_active"`
 }
 
 func (TaxBracket) TableName() string { return "mst_tax_brackets" }
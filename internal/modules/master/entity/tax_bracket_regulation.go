@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+// TaxBracketMinistryRegulation16 is a TER bracket under PMK 16.
+type TaxBracketMinistryRegulation16 struct {
+	sharedentity.Base
+	MinimumIncome            *float64   `json:"minimum_income" validate:"omitempty,min=0"`
+	MaximumIncome            *float64   `json:"maximum_income" validate:"omitempty,min=0"`
+	TaxRate                  float64    `json:"tax_rate" validate:"min=0,max=100"` // Percentage, e.g. 5.00
+	EffectiveTaxRateCategory *string    `json:"effective_tax_rate_category" validate:"omitempty,max=255"`
+	EffectiveDate            *time.Time `json:"effective_date" gorm:"type:date"`
+	LogicOperator            *string    `json:"logic_operator" validate:"omitempty,max=255"`
+	IsActive                 bool       `json:"is_active"`
+}
+
+func (TaxBracketMinistryRegulation16) TableName() string {
+	return "mst_tax_brackets_ministry_regulation_16"
+}
+
+// TaxBracketIncomeTaxArticle17 is a progressive bracket under PPh 17.
+type TaxBracketIncomeTaxArticle17 struct {
+	sharedentity.Base
+	MinimumIncome            *float64   `json:"minimum_income" validate:"omitempty,min=0"`
+	MaximumIncome            *float64   `json:"maximum_income" validate:"omitempty,min=0"`
+	TaxRate                  float64    `json:"tax_rate" validate:"min=0,max=100"` // Percentage, e.g. 5.00
+	EffectiveTaxRateCategory *string    `json:"effective_tax_rate_category" validate:"omitempty,max=255"`
+	EffectiveDate            *time.Time `json:"effective_date" gorm:"type:date"`
+	LogicOperator            *string    `json:"logic_operator" validate:"omitempty,max=255"`
+	IsActive                 bool       `json:"is_active"`
+}
+
+func (TaxBracketIncomeTaxArticle17) TableName() string {
+	return "mst_tax_brackets_income_tax_article_17"
+}
@@ -1,9 +1,19 @@
 package entity
 
+import (
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
 type TaxGroup struct {
-	ID   string `json:"id" gorm:"primaryKey"`
-	Code string `json:"code"`
-	Name string `json:"name"`
+	sharedentity.Base
+	Name                 string     `json:"name" validate:"required,max=255"`
+	TaxExemptIncomeCode  *string    `json:"tax_exempt_income_code" validate:"omitempty,max=255"`  // PTKP code
+	EffectiveTaxRateCode *string    `json:"effective_tax_rate_code" validate:"omitempty,max=255"` // TER code
+	IsTaxIDCombined      bool       `json:"is_tax_id_combined"`
+	EffectiveDate        *time.Time `json:"effective_date" gorm:"type:date"`
+	IsActive             bool       `json:"is_active"`
 }
 
 func (TaxGroup) TableName() string { return "mst_tax_groups" }
@@ -6,30 +6,31 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/user/go-boilerplate/internal/modules/master/entity"
+	"github.com/user/go-boilerplate/internal/modules/master/dto"
+	"github.com/user/go-boilerplate/internal/modules/master/registry"
+	"github.com/user/go-boilerplate/internal/modules/master/repository"
+	"github.com/user/go-boilerplate/internal/modules/master/service"
 	"github.com/user/go-boilerplate/internal/shared/response"
-	"github.com/user/go-boilerplate/pkg/cache"
-	"gorm.io/gorm"
 )
 
 // BatchHandler handles multiple master data types in one request.
 type BatchHandler struct {
-	db    *gorm.DB
-	cache *cache.Client
+	registry *registry.Registry
+	repo     repository.MasterRepository
+	cache    service.CacheService
 }
 
-const (
-	masterCachePrefix = "master:"
-	masterCacheTTL    = 1 * time.Hour
-)
-
 // NewBatchHandler creates a new batch master handler.
-func NewBatchHandler(db *gorm.DB, cache *cache.Client) *BatchHandler {
-	return &BatchHandler{db: db, cache: cache}
+func NewBatchHandler(reg *registry.Registry, repo repository.MasterRepository, cache service.CacheService) *BatchHandler {
+	return &BatchHandler{registry: reg, repo: repo, cache: cache}
 }
 
 // All returns multiple master data types based on query parameter.
 // Example: GET /api/master/all?types=banks,provinces,genders
+//
+// Responses carry an ETag and Last-Modified; a matching If-None-Match or
+// If-Modified-Since gets 304. With ?since=<RFC 3339 timestamp> only the rows
+// changed after it are returned, with tombstones for deleted ones.
 func (h *BatchHandler) All(c *gin.Context) {
 	typesParam := c.Query("types")
 	if typesParam == "" {
@@ -37,93 +38,65 @@ func (h *BatchHandler) All(c *gin.Context) {
 		return
 	}
 
-	requestedTypes := strings.Split(typesParam, ",")
-	results := make(map[string]interface{})
+	var since time.Time
+	if raw := c.Query("since"); raw != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, raw); err != nil {
+			response.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "Query parameter 'since' must be an RFC 3339 timestamp", nil)
+			return
+		}
+	}
 
-	for _, t := range requestedTypes {
-		t = strings.TrimSpace(t)
-		if t == "" {
+	var defs []*registry.Definition
+	var versions []*dto.TableVersion
+	for _, t := range strings.Split(typesParam, ",") {
+		def, ok := h.registry.ByKey(strings.TrimSpace(t))
+		if !ok {
 			continue
 		}
-
-		// Try cache first
-		cacheKey := masterCachePrefix + t
-		var cachedData interface{}
-
-		if h.cache != nil {
-			found, err := h.cache.Get(c.Request.Context(), cacheKey, &cachedData)
-			if err == nil && found {
-				results[t] = cachedData
-				continue
-			}
+		version, err := h.cache.Version(c.Request.Context(), def)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch "+def.Key, nil)
+			return
 		}
+		defs = append(defs, def)
+		versions = append(versions, version)
+	}
+	if notModified(c, versions...) {
+		return
+	}
 
-		// Database fallback
-		var items interface{}
-		switch t {
-		case "areas":
-			var data []entity.Area
-			h.db.Find(&data)
-			items = data
-		case "provinces":
-			var data []entity.Province
-			h.db.Find(&data)
-			items = data
-		case "districts":
-			var data []entity.District
-			h.db.Find(&data)
-			items = data
-		case "banks":
-			var data []entity.Bank
-			h.db.Find(&data)
-			items = data
-		case "branches":
-			var data []entity.Branch
-			h.db.Find(&data)
-			items = data
-		case "genders":
-			var data []entity.Gender
-			h.db.Find(&data)
-			items = data
-		case "religions":
-			var data []entity.Religion
-			h.db.Find(&data)
-			items = data
-		case "marital_statuses":
-			var data []entity.MaritalStatus
-			h.db.Find(&data)
-			items = data
-		case "citizenships":
-			var data []entity.Citizenship
-			h.db.Find(&data)
-			items = data
-		case "education_levels":
-			var data []entity.EducationLevel
-			h.db.Find(&data)
-			items = data
-		case "currencies":
-			var data []entity.Currency
-			h.db.Find(&data)
-			items = data
-		case "tax_groups":
-			var data []entity.TaxGroup
-			h.db.Find(&data)
-			items = data
-		case "tax_brackets":
-			var data []entity.TaxBracket
-			h.db.Find(&data)
-			items = data
-		default:
-			continue
+	if !since.IsZero() {
+		h.delta(c, defs, since)
+		return
+	}
+
+	results := make(map[string]interface{})
+	for _, def := range defs {
+		items, err := h.cache.Load(c.Request.Context(), def)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch "+def.Key, nil)
+			return
 		}
+		results[def.Key] = items
+	}
 
-		results[t] = items
+	response.Success(c, http.StatusOK, "Success", results)
+}
 
-		// Save to cache
-		if h.cache != nil && items != nil {
-			h.cache.Set(c.Request.Context(), cacheKey, items, masterCacheTTL)
+// delta serves ?since=: the rows of each type changed after since, read
+// from the database.
+func (h *BatchHandler) delta(c *gin.Context, defs []*registry.Definition, since time.Time) {
+	// Taken before reading, so a write racing the reads is sent again.
+	result := &dto.DeltaResponse{Until: time.Now().UTC(), Types: make(map[string]*dto.Delta)}
+	for _, def := range defs {
+		changed, deleted, err := h.repo.Changes(c.Request.Context(), def, since)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch "+def.Key, nil)
+			return
 		}
+		result.Types[def.Key] = &dto.Delta{Changed: changed, Deleted: deleted}
 	}
 
-	response.Success(c, http.StatusOK, "Success", results)
+	response.Success(c, http.StatusOK, "Success", result)
 }
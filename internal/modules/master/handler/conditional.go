@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/master/dto"
+)
+
+// notModified sets the ETag and Last-Modified of a response built from
+// tables at the given versions and answers 304 when the client's copy is
+// current. The ETag also covers the query string, so each distinct request
+// has its own.
+func notModified(c *gin.Context, versions ...*dto.TableVersion) bool {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.URL.RawQuery))
+	var modified time.Time
+	for _, v := range versions {
+		fmt.Fprintf(hash, "|%d:%d", v.Rows, v.Modified.UnixNano())
+		if v.Modified.After(modified) {
+			modified = v.Modified
+		}
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	modified = modified.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if !modified.IsZero() {
+		c.Header("Last-Modified", modified.Format(http.TimeFormat))
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if !etagMatches(match, etag) {
+			return false
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err != nil || modified.IsZero() || modified.After(since) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies the weak comparison of If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
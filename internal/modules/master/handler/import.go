@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/master/registry"
+	"github.com/user/go-boilerplate/internal/modules/master/service"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/utils/fileutil"
+)
+
+// ImportHandler loads master tables from XLSX or CSV files and serves the
+// matching templates.
+type ImportHandler struct {
+	service service.ImportService
+	cache   service.CacheService
+}
+
+// NewImportHandler creates a new master data import handler.
+func NewImportHandler(service service.ImportService, cache service.CacheService) *ImportHandler {
+	return &ImportHandler{service: service, cache: cache}
+}
+
+// Import handles POST /api/master/<name>/import requests with a multipart
+// "file" (.xlsx or .csv). Without ?commit=true it is a dry run returning the
+// diff; with it the diff is applied in one transaction. Files with rejected
+// rows are never applied and fail with 422.
+func (h *ImportHandler) Import(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			respondError(c, apperror.BadRequest("No file provided"))
+			return
+		}
+		defer file.Close()
+
+		var rows [][]string
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".xlsx":
+			rows, err = fileutil.ReadXLSX(file)
+		case ".csv":
+			rows, err = fileutil.ReadCSV(file)
+		default:
+			respondError(c, apperror.BadRequest("The file must be .xlsx or .csv"))
+			return
+		}
+		if err != nil {
+			respondError(c, apperror.BadRequest("The file could not be read"))
+			return
+		}
+
+		dryRun := c.Query("commit") != "true"
+		result, err := h.service.Import(c.Request.Context(), def, rows, c.GetString("user_id"), dryRun)
+		if err != nil {
+			handleServiceError(c, err, "Failed to import "+def.Name)
+			return
+		}
+
+		switch {
+		case len(result.Rejected) > 0 && !dryRun:
+			response.Error(c, http.StatusUnprocessableEntity, string(apperror.ErrCodeValidation), "Rejected rows; nothing was imported", result)
+		case dryRun:
+			response.Success(c, http.StatusOK, "Import preview", result)
+		default:
+			if err := h.cache.Invalidate(c.Request.Context(), def); err != nil {
+				handleServiceError(c, err, "Imported, but failed to refresh the "+def.Name+" cache")
+				return
+			}
+			response.Success(c, http.StatusOK, "Import committed", result)
+		}
+	}
+}
+
+// Template handles GET /api/master/<name>/import/template requests, returning
+// an empty import file (?format=xlsx, the default, or csv).
+func (h *ImportHandler) Template(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header, err := h.service.Template(def)
+		if err != nil {
+			handleServiceError(c, err, "Failed to build template")
+			return
+		}
+
+		// The file is built in memory so a failure still gets an error response.
+		var buf bytes.Buffer
+		data := [][]string{header}
+		filename, contentType := def.Key+".xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		if c.DefaultQuery("format", "xlsx") == "csv" {
+			filename, contentType = def.Key+".csv", "text/csv"
+			err = fileutil.GenerateCSV(&buf, data)
+		} else {
+			err = fileutil.GenerateXLSX(&buf, def.Key, data)
+		}
+		if err != nil {
+			handleServiceError(c, err, "Failed to generate template")
+			return
+		}
+
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Data(http.StatusOK, contentType, buf.Bytes())
+	}
+}
@@ -0,0 +1,412 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/go-boilerplate/internal/modules/master/registry"
+	"github.com/user/go-boilerplate/internal/modules/master/repository"
+	"github.com/user/go-boilerplate/internal/modules/master/service"
+	"github.com/user/go-boilerplate/internal/shared/effective"
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// MasterHandler serves list/get/create/update/delete for every registered
+// master table. Each method returns the handler for one definition.
+type MasterHandler struct {
+	registry *registry.Registry
+	repo     repository.MasterRepository
+	cache    service.CacheService
+}
+
+// ReorderRequest lists every row of a sorted table in its new order.
+type ReorderRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,dive,uuid"`
+}
+
+// NewMasterHandler creates a new master data handler.
+func NewMasterHandler(reg *registry.Registry, repo repository.MasterRepository, cache service.CacheService) *MasterHandler {
+	return &MasterHandler{registry: reg, repo: repo, cache: cache}
+}
+
+// List handles GET /api/master/<name> requests with the shared list query
+// grammar (filter, sort, q, fields), and ?as_of= on effective-dated tables.
+// Responses carry an ETag; a matching If-None-Match gets 304.
+func (h *MasterHandler) List(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params, appErr := query.Parse(c, def.Query)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+
+		scopes, appErr := asOfScopes(c, def)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+		if h.notModified(c, def) {
+			return
+		}
+
+		items, total, err := h.repo.List(c.Request.Context(), def, params, scopes...)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch "+def.Name, nil)
+			return
+		}
+		response.Paginated(c, http.StatusOK, items, total, params)
+	}
+}
+
+// Children handles GET /api/master/<parent>/:id/<name> requests: the list of
+// def's rows under one parent row, e.g. /provinces/:id/districts.
+func (h *MasterHandler) Children(parent, def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		model, appErr := h.find(c, parent)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+
+		params, appErr := query.Parse(c, def.Query)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+		params.Filters = append(params.Filters, query.Filter{
+			Column:   def.Parent.Column,
+			Operator: query.OpEq,
+			Values:   []any{registry.BaseOf(model).ID},
+		})
+
+		scopes, appErr := asOfScopes(c, def)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+		if h.notModified(c, def) {
+			return
+		}
+
+		items, total, err := h.repo.List(c.Request.Context(), def, params, scopes...)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch "+def.Name, nil)
+			return
+		}
+		response.Paginated(c, http.StatusOK, items, total, params)
+	}
+}
+
+// GetByCode handles GET /api/master/<name>/code/:code requests on tables
+// with a unique code.
+func (h *MasterHandler) GetByCode(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		item, err := h.repo.GetBy(c.Request.Context(), def, "code", c.Param("code"))
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				respondError(c, apperror.NotFound("Record not found"))
+				return
+			}
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch record", nil)
+			return
+		}
+		response.Success(c, http.StatusOK, "Success", item)
+	}
+}
+
+// Get handles GET /api/master/<name>/:id requests.
+func (h *MasterHandler) Get(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		item, appErr := h.find(c, def)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+		response.Success(c, http.StatusOK, "Success", item)
+	}
+}
+
+// Create handles POST /api/master/<name> requests. Columns missing from the
+// payload take their database defaults; on sorted tables a missing
+// sort_order appends the row at the end.
+func (h *MasterHandler) Create(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		model := def.NewModel()
+		provided, appErr := bindPayload(c, model)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+
+		userID := c.GetString("user_id")
+		*registry.BaseOf(model) = sharedentity.Base{CreatedBy: &userID, UpdatedBy: &userID}
+
+		if appErr := h.checkWrite(c, def, model, ""); appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+
+		columns, err := h.repo.WritableColumns(def)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to create record", nil)
+			return
+		}
+		appendSort := def.SortOrder && !provided["sort_order"]
+		var omit []string
+		for name, column := range columns {
+			if !provided[name] && !(appendSort && column == "sort_order") {
+				omit = append(omit, column)
+			}
+		}
+
+		if err := h.repo.Create(ctx, def, model, omit, appendSort); err != nil {
+			respondWriteError(c, def, err, "Failed to create record")
+			return
+		}
+		if !h.invalidate(c, def) {
+			return
+		}
+
+		item, err := h.repo.Get(ctx, def, registry.BaseOf(model).ID)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch record", nil)
+			return
+		}
+		response.Success(c, http.StatusCreated, "Record created", item)
+	}
+}
+
+// Update handles PATCH /api/master/<name>/:id requests. Only the fields in
+// the payload change.
+func (h *MasterHandler) Update(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		model, appErr := h.find(c, def)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+
+		base := registry.BaseOf(model)
+		saved := *base
+		if _, appErr := bindPayload(c, model); appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+		userID := c.GetString("user_id")
+		*base = saved
+		base.UpdatedBy = &userID
+
+		if appErr := h.checkWrite(c, def, model, base.ID); appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+
+		if err := h.repo.Update(c.Request.Context(), def, model); err != nil {
+			respondWriteError(c, def, err, "Failed to update record")
+			return
+		}
+		if !h.invalidate(c, def) {
+			return
+		}
+
+		response.Success(c, http.StatusOK, "Record updated", model)
+	}
+}
+
+// Delete handles DELETE /api/master/<name>/:id requests. Rows still
+// referenced by live rows of another table cannot be deleted.
+func (h *MasterHandler) Delete(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		model, appErr := h.find(c, def)
+		if appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+		id := registry.BaseOf(model).ID
+
+		table, err := h.repo.ReferencingTable(ctx, h.registry.DependentsOf(def), id)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to check references", nil)
+			return
+		}
+		if table != "" {
+			respondError(c, apperror.Conflict("Record is still referenced by "+table))
+			return
+		}
+
+		if err := h.repo.Delete(ctx, def, id, c.GetString("user_id")); err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to delete record", nil)
+			return
+		}
+		if !h.invalidate(c, def) {
+			return
+		}
+
+		response.Success(c, http.StatusOK, "Record deleted", nil)
+	}
+}
+
+// Reorder handles PUT /api/master/<name>/order requests on sorted tables,
+// setting sort_order to each ID's position.
+func (h *MasterHandler) Reorder(def *registry.Definition) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ReorderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, apperror.BadRequest("Invalid request body"))
+			return
+		}
+		if appErr := validator.Validate(&req); appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+
+		if err := h.repo.Reorder(c.Request.Context(), def, req.IDs, c.GetString("user_id")); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				respondError(c, apperror.Validation("Validation failed", []validator.ValidationError{
+					{Field: "ids", Message: "ids must name existing records"},
+				}))
+				return
+			}
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to reorder records", nil)
+			return
+		}
+		if !h.invalidate(c, def) {
+			return
+		}
+
+		response.Success(c, http.StatusOK, "Records reordered", nil)
+	}
+}
+
+// find loads the row named by the :id parameter.
+func (h *MasterHandler) find(c *gin.Context, def *registry.Definition) (any, *apperror.AppError) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, apperror.NotFound("Record not found")
+	}
+
+	item, err := h.repo.Get(c.Request.Context(), def, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperror.NotFound("Record not found")
+		}
+		return nil, apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to fetch record", 500)
+	}
+	return item, nil
+}
+
+// checkWrite validates the model, then checks its unique columns and
+// foreign keys. excludeID is the row being updated.
+func (h *MasterHandler) checkWrite(c *gin.Context, def *registry.Definition, model any, excludeID string) *apperror.AppError {
+	if appErr := validator.Validate(model); appErr != nil {
+		return appErr
+	}
+
+	ctx := c.Request.Context()
+	column, err := h.repo.DuplicateColumn(ctx, def, model, excludeID)
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check uniqueness", 500)
+	}
+	if column != "" {
+		return apperror.Conflict("A record with this " + column + " already exists")
+	}
+
+	missing, err := h.repo.MissingReferences(ctx, def, model)
+	if err != nil {
+		return apperror.Wrap(err, apperror.ErrCodeDatabaseError, "Failed to check references", 500)
+	}
+	if len(missing) > 0 {
+		details := make([]validator.ValidationError, len(missing))
+		for i, column := range missing {
+			details[i] = validator.ValidationError{Field: column, Message: column + " does not name an existing record"}
+		}
+		return apperror.Validation("Validation failed", details)
+	}
+	return nil
+}
+
+// notModified answers 304 when the client's copy of the table's list is
+// current; on errors the list is served without validators.
+func (h *MasterHandler) notModified(c *gin.Context, def *registry.Definition) bool {
+	version, err := h.cache.Version(c.Request.Context(), def)
+	return err == nil && notModified(c, version)
+}
+
+// invalidate drops the table from the batch API cache on every instance.
+// The write is already committed, so a failure is answered with an error
+// rather than a success that would hide stale caches.
+func (h *MasterHandler) invalidate(c *gin.Context, def *registry.Definition) bool {
+	if err := h.cache.Invalidate(c.Request.Context(), def); err != nil {
+		handleServiceError(c, err, "Saved, but failed to refresh the "+def.Name+" cache")
+		return false
+	}
+	return true
+}
+
+// bindPayload decodes the JSON body onto model and returns the keys present
+// in it, so PATCH semantics and database defaults can be honoured.
+func bindPayload(c *gin.Context, model any) (map[string]bool, *apperror.AppError) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, apperror.BadRequest("Invalid request body")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, apperror.BadRequest("Invalid request body")
+	}
+	if err := json.Unmarshal(body, model); err != nil {
+		return nil, apperror.BadRequest("Invalid request body")
+	}
+
+	provided := make(map[string]bool, len(fields))
+	for name := range fields {
+		provided[name] = true
+	}
+	return provided, nil
+}
+
+// asOfScopes reads ?as_of= into a scope keeping the versions valid on that
+// date. Tables that are not effective-dated refuse the parameter.
+func asOfScopes(c *gin.Context, def *registry.Definition) ([]func(*gorm.DB) *gorm.DB, *apperror.AppError) {
+	asOf, appErr := effective.ParseAsOf(c)
+	if appErr != nil || asOf == nil {
+		return nil, appErr
+	}
+	if def.Versioning == nil {
+		return nil, apperror.BadRequest(def.Name + " are not effective-dated; as_of is not supported")
+	}
+	return []func(*gorm.DB) *gorm.DB{def.Versioning.AsOf(*asOf)}, nil
+}
+
+// respondWriteError maps the version window errors of effective-dated
+// tables; anything else is a database error.
+func respondWriteError(c *gin.Context, def *registry.Definition, err error, message string) {
+	switch {
+	case errors.Is(err, effective.ErrOverlap):
+		respondError(c, apperror.Conflict("The validity window overlaps another version"))
+	case errors.Is(err, effective.ErrNoStart):
+		respondError(c, apperror.Validation("Validation failed", []validator.ValidationError{
+			{Field: def.Versioning.Start, Message: def.Versioning.Start + " is required"},
+		}))
+	default:
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", message, nil)
+	}
+}
+
+func respondError(c *gin.Context, appErr *apperror.AppError) {
+	response.Error(c, appErr.HTTPStatus, string(appErr.Code), appErr.Message, appErr.Details)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/master/dto"
+	"github.com/user/go-boilerplate/internal/modules/master/service"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RegionHandler resolves and validates the administrative region hierarchy
+// (province, district, sub-district, village) used by address forms.
+type RegionHandler struct {
+	service service.RegionService
+}
+
+// NewRegionHandler creates a new region handler.
+func NewRegionHandler(service service.RegionService) *RegionHandler {
+	return &RegionHandler{service: service}
+}
+
+// Resolve handles GET /api/master/regions/:code requests. The code may be of
+// any level; the response carries the region and all its ancestors.
+func (h *RegionHandler) Resolve(c *gin.Context) {
+	chain, err := h.service.Resolve(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		handleServiceError(c, err, "Failed to resolve region")
+		return
+	}
+	response.Success(c, http.StatusOK, "Success", chain)
+}
+
+// Validate handles POST /api/master/regions/validate requests. Codes that do
+// not lie on the chain of the narrowest one fail with 422.
+func (h *RegionHandler) Validate(c *gin.Context) {
+	var req dto.ValidateRegionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+
+	chain, err := h.service.Validate(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err, "Failed to validate region")
+		return
+	}
+	response.Success(c, http.StatusOK, "Region is consistent", chain)
+}
+
+func handleServiceError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*apperror.AppError); ok {
+		respondError(c, appErr)
+		return
+	}
+	logger.Error(c.Request.Context(), message, zap.Error(err))
+	respondError(c, apperror.Internal(message))
+}
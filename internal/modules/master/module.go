@@ -1,66 +1,73 @@
 package master
 
 import (
+	"context"
+	"slices"
+
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/config"
+	"github.com/user/go-boilerplate/internal/middleware"
 	"github.com/user/go-boilerplate/internal/modules/master/handler"
+	"github.com/user/go-boilerplate/internal/modules/master/repository"
+	"github.com/user/go-boilerplate/internal/modules/master/service"
+	"github.com/user/go-boilerplate/internal/shared/permission"
 	"github.com/user/go-boilerplate/pkg/cache"
 	"gorm.io/gorm"
 )
 
 // Module represents the master data module.
 type Module struct {
-	areaHandler           *handler.AreaHandler
-	provinceHandler       *handler.ProvinceHandler
-	districtHandler       *handler.DistrictHandler
-	bankHandler           *handler.BankHandler
-	branchHandler         *handler.BranchHandler
-	genderHandler         *handler.GenderHandler
-	religionHandler       *handler.ReligionHandler
-	maritalStatusHandler  *handler.MaritalStatusHandler
-	citizenshipHandler    *handler.CitizenshipHandler
-	educationLevelHandler *handler.EducationLevelHandler
-	currencyHandler       *handler.CurrencyHandler
-	taxGroupHandler       *handler.TaxGroupHandler
-	taxBracketHandler     *handler.TaxBracketHandler
-	batchHandler         *handler.BatchHandler
+	masterHandler *handler.MasterHandler
+	batchHandler  *handler.BatchHandler
+	regionHandler *handler.RegionHandler
+	importHandler *handler.ImportHandler
 }
 
 // New creates a new master module.
+// It subscribes to the cache invalidations published by other instances for
+// the lifetime of the process.
 func New(db *gorm.DB, cfg *config.Config, cache *cache.Client) *Module {
+	repo := repository.NewMasterRepository(db)
+	masterCache := service.NewCacheService(repo, cache)
+	go masterCache.Listen(context.Background())
+
 	return &Module{
-		areaHandler:           handler.NewAreaHandler(db),
-		provinceHandler:       handler.NewProvinceHandler(db),
-		districtHandler:       handler.NewDistrictHandler(db),
-		bankHandler:           handler.NewBankHandler(db),
-		branchHandler:         handler.NewBranchHandler(db),
-		genderHandler:         handler.NewGenderHandler(db),
-		religionHandler:       handler.NewReligionHandler(db),
-		maritalStatusHandler:  handler.NewMaritalStatusHandler(db),
-		citizenshipHandler:    handler.NewCitizenshipHandler(db),
-		educationLevelHandler: handler.NewEducationLevelHandler(db),
-		currencyHandler:       handler.NewCurrencyHandler(db),
-		taxGroupHandler:       handler.NewTaxGroupHandler(db),
-		taxBracketHandler:     handler.NewTaxBracketHandler(db),
-		batchHandler:         handler.NewBatchHandler(db, cache),
+		masterHandler: handler.NewMasterHandler(Tables, repo, masterCache),
+		batchHandler:  handler.NewBatchHandler(Tables, repo, masterCache),
+		regionHandler: handler.NewRegionHandler(service.NewRegionService(repository.NewRegionRepository(db))),
+		importHandler: handler.NewImportHandler(service.NewImportService(repo), masterCache),
 	}
 }
 
-// RegisterRoutes registers master data routes.
+// RegisterRoutes registers master data routes. Every table in Tables gets
+// list/get/create/update/delete; sorted tables also get PUT .../order,
+// tables with a unique code GET .../code/:code and parent tables
+// GET .../:id/<child>. Every table can be imported from XLSX or CSV.
 func (m *Module) RegisterRoutes(api *gin.RouterGroup) {
 	master := api.Group("/master")
+	master.Use(middleware.RequirePermission(permission.MasterRead))
 	master.GET("/all", m.batchHandler.All)
-	master.GET("/areas", m.areaHandler.List)
-	master.GET("/provinces", m.provinceHandler.List)
-	master.GET("/districts", m.districtHandler.List)
-	master.GET("/banks", m.bankHandler.List)
-	master.GET("/branches", m.branchHandler.List)
-	master.GET("/genders", m.genderHandler.List)
-	master.GET("/religions", m.religionHandler.List)
-	master.GET("/marital-statuses", m.maritalStatusHandler.List)
-	master.GET("/citizenships", m.citizenshipHandler.List)
-	master.GET("/education-levels", m.educationLevelHandler.List)
-	master.GET("/currencies", m.currencyHandler.List)
-	master.GET("/tax-groups", m.taxGroupHandler.List)
-	master.GET("/tax-brackets", m.taxBracketHandler.List)
+	master.GET("/regions/:code", m.regionHandler.Resolve)
+	master.POST("/regions/validate", m.regionHandler.Validate)
+
+	write := middleware.RequirePermission(permission.MasterWrite)
+	for _, def := range Tables.All() {
+		group := master.Group("/" + def.Name)
+		group.GET("", m.masterHandler.List(def))
+		group.GET("/:id", m.masterHandler.Get(def))
+		if slices.Contains(def.UniqueColumns, "code") {
+			group.GET("/code/:code", m.masterHandler.GetByCode(def))
+		}
+		for _, child := range Tables.ChildrenOf(def) {
+			group.GET("/:id/"+child.Name, m.masterHandler.Children(def, child))
+		}
+		group.POST("", write, m.masterHandler.Create(def))
+		group.PATCH("/:id", write, m.masterHandler.Update(def))
+		group.DELETE("/:id", write, m.masterHandler.Delete(def))
+		if def.SortOrder {
+			group.PUT("/order", write, m.masterHandler.Reorder(def))
+		}
+		group.GET("/import/template", m.importHandler.Template(def))
+		group.POST("/import", write, m.importHandler.Import(def))
+	}
 }
@@ -0,0 +1,225 @@
+// Package registry describes the master tables served by the generic CRUD.
+//
+// Each master table is one Definition, built from its entity:
+//
+//	registry.Entity[entity.Branch]("branches").
+//		Unique("code").
+//		References("area_id", "mst_areas").
+//		Searchable("code", "description").
+//		Sorted()
+//
+// The entity embeds sharedentity.Base (soft delete and audit columns) and
+// carries `validate` tags for create and update payloads. Every column can
+// be filtered, sorted and selected on the list endpoint.
+package registry
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/user/go-boilerplate/internal/shared/effective"
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"gorm.io/gorm/schema"
+)
+
+// Reference is a foreign key column checked on write: it must name a live
+// row in Table.
+type Reference struct {
+	Column string
+	Table  string
+}
+
+// Dependent is a table whose live rows block deleting a referenced row.
+type Dependent struct {
+	Table  string
+	Column string
+}
+
+// Definition describes one master table.
+type Definition struct {
+	Name  string // Route segment, e.g. "marital-statuses"
+	Key   string // Batch API type, e.g. "marital_statuses"
+	Table string // e.g. "mst_marital_statuses"
+
+	UniqueColumns []string              // Unique among live rows, e.g. code
+	ForeignKeys   []Reference           // Checked on create and update
+	Parent        *Reference            // Lists nested under the parent, e.g. /provinces/:id/districts
+	Versioning    *effective.Versioning // Rows are dated versions; lists accept ?as_of=
+	Dependents    []Dependent           // Tables outside the registry that reference this one
+	DerivedCaches []string              // Redis key patterns computed from the table, deleted on every write
+	SortOrder     bool                  // Has a sort_order column
+	Order         string                // Default list order
+	Query         query.Allowlist       // Columns exposed to the list query grammar
+
+	newModel func() any
+	newSlice func() any
+}
+
+// Entity starts the definition of the master table behind T.
+func Entity[T schema.Tabler](name string) *Definition {
+	var model T
+	return &Definition{
+		Name:     name,
+		Key:      strings.ReplaceAll(name, "-", "_"),
+		Table:    model.TableName(),
+		Order:    "created_at ASC, id ASC",
+		Query:    query.For(&model).OrderBy("created_at ASC, id ASC"),
+		newModel: func() any { return new(T) },
+		newSlice: func() any { return &[]T{} },
+	}
+}
+
+// Unique adds columns that must be unique among live rows.
+func (d *Definition) Unique(columns ...string) *Definition {
+	d.UniqueColumns = append(d.UniqueColumns, columns...)
+	return d
+}
+
+// References adds a foreign key column pointing at table's id.
+func (d *Definition) References(column, table string) *Definition {
+	d.ForeignKeys = append(d.ForeignKeys, Reference{Column: column, Table: table})
+	return d
+}
+
+// ChildOf adds a foreign key to the parent table and serves the table's
+// rows under each parent row as well.
+func (d *Definition) ChildOf(column, table string) *Definition {
+	d.References(column, table)
+	d.Parent = &Reference{Column: column, Table: table}
+	return d
+}
+
+// EffectiveDated marks rows as dated versions of a record: lists accept
+// ?as_of= and writes are checked against the other versions.
+func (d *Definition) EffectiveDated(v *effective.Versioning) *Definition {
+	d.Versioning = v
+	return d
+}
+
+// ReferencedBy adds a table outside the registry whose column points at
+// this table's id. Tables in the registry are found through their foreign keys.
+func (d *Definition) ReferencedBy(table, column string) *Definition {
+	d.Dependents = append(d.Dependents, Dependent{Table: table, Column: column})
+	return d
+}
+
+// Derives adds Redis key patterns, e.g. "auth:branch_scope:*", whose
+// values other modules compute from this table.
+func (d *Definition) Derives(patterns ...string) *Definition {
+	d.DerivedCaches = append(d.DerivedCaches, patterns...)
+	return d
+}
+
+// Searchable sets the text columns matched by the list ?q= parameter.
+func (d *Definition) Searchable(columns ...string) *Definition {
+	d.Query.Search = append(d.Query.Search, columns...)
+	return d
+}
+
+// Sorted marks a table with a sort_order column: lists follow it and new
+// rows without one are appended at the end.
+func (d *Definition) Sorted() *Definition {
+	d.SortOrder = true
+	d.Order = "sort_order ASC, id ASC"
+	d.Query.Order = d.Order
+	return d
+}
+
+// OrderBy overrides the default list order.
+func (d *Definition) OrderBy(order string) *Definition {
+	d.Order = order
+	d.Query.Order = order
+	return d
+}
+
+// NewModel returns a pointer to a new zero entity.
+func (d *Definition) NewModel() any {
+	return d.newModel()
+}
+
+// BaseOf returns the embedded sharedentity.Base of a model returned by
+// NewModel.
+func BaseOf(model any) *sharedentity.Base {
+	return reflect.ValueOf(model).Elem().FieldByName("Base").Addr().Interface().(*sharedentity.Base)
+}
+
+// NewSlice returns a pointer to an empty entity slice.
+func (d *Definition) NewSlice() any {
+	return d.newSlice()
+}
+
+// Registry holds the master table definitions in registration order.
+type Registry struct {
+	definitions []*Definition
+	byName      map[string]*Definition
+	byKey       map[string]*Definition
+}
+
+// New builds a registry. Names and keys must be unique.
+func New(definitions ...*Definition) *Registry {
+	r := &Registry{
+		definitions: definitions,
+		byName:      make(map[string]*Definition, len(definitions)),
+		byKey:       make(map[string]*Definition, len(definitions)),
+	}
+	for _, def := range definitions {
+		if _, exists := r.byName[def.Name]; exists {
+			panic("registry: duplicate master table " + def.Name)
+		}
+		r.byName[def.Name] = def
+		r.byKey[def.Key] = def
+	}
+	return r
+}
+
+// All returns every definition in registration order.
+func (r *Registry) All() []*Definition {
+	return r.definitions
+}
+
+// Models returns a new model of every registered table.
+func (r *Registry) Models() []any {
+	models := make([]any, len(r.definitions))
+	for i, def := range r.definitions {
+		models[i] = def.NewModel()
+	}
+	return models
+}
+
+// ByName returns the definition served at the route segment.
+func (r *Registry) ByName(name string) (*Definition, bool) {
+	def, ok := r.byName[name]
+	return def, ok
+}
+
+// ByKey returns the definition for a batch API type.
+func (r *Registry) ByKey(key string) (*Definition, bool) {
+	def, ok := r.byKey[key]
+	return def, ok
+}
+
+// ChildrenOf returns the definitions nested under def.
+func (r *Registry) ChildrenOf(def *Definition) []*Definition {
+	var children []*Definition
+	for _, other := range r.definitions {
+		if other.Parent != nil && other.Parent.Table == def.Table {
+			children = append(children, other)
+		}
+	}
+	return children
+}
+
+// DependentsOf returns every table and column referencing def's rows: the
+// registered tables with a foreign key to it plus its own Dependents.
+func (r *Registry) DependentsOf(def *Definition) []Dependent {
+	var dependents []Dependent
+	for _, other := range r.definitions {
+		for _, ref := range other.ForeignKeys {
+			if ref.Table == def.Table {
+				dependents = append(dependents, Dependent{Table: other.Table, Column: ref.Column})
+			}
+		}
+	}
+	return append(dependents, def.Dependents...)
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/master/dto"
+	"github.com/user/go-boilerplate/internal/modules/master/registry"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// baseColumns are managed by the server and never written from a payload.
+var baseColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+	"created_by": true,
+	"updated_by": true,
+}
+
+// MasterRepository provides data access for every registered master table.
+// Models are the pointers returned by Definition.NewModel.
+type MasterRepository interface {
+	List(ctx context.Context, def *registry.Definition, params *query.Params, scopes ...func(*gorm.DB) *gorm.DB) (any, int64, error)
+	ListAll(ctx context.Context, def *registry.Definition) (any, error)
+	// Version reads the row count and latest change of the table.
+	Version(ctx context.Context, def *registry.Definition) (*dto.TableVersion, error)
+	// Changes lists the live rows updated after since and the rows
+	// soft-deleted after it.
+	Changes(ctx context.Context, def *registry.Definition, since time.Time) (any, []dto.Tombstone, error)
+	Get(ctx context.Context, def *registry.Definition, id string) (any, error)
+	// GetBy loads the live row whose column equals value.
+	GetBy(ctx context.Context, def *registry.Definition, column, value string) (any, error)
+	// GetVersion loads the live row of an effective-dated table holding the
+	// model's place: same natural key, start and row key.
+	GetVersion(ctx context.Context, def *registry.Definition, model any) (any, error)
+	// Create inserts the model without the omitted columns, so they take
+	// their database defaults. appendSort sets sort_order past the last row.
+	// On effective-dated tables it closes the open version first and
+	// returns effective.ErrOverlap if the model overlaps another version.
+	Create(ctx context.Context, def *registry.Definition, model any, omit []string, appendSort bool) error
+	// Update returns effective.ErrOverlap like Create.
+	Update(ctx context.Context, def *registry.Definition, model any) error
+	// Import creates and updates the models in one transaction; created
+	// models are handled as by Create.
+	Import(ctx context.Context, def *registry.Definition, creates, updates []any, omit []string, appendSort bool) error
+	Delete(ctx context.Context, def *registry.Definition, id, deletedBy string) error
+	// Reorder sets sort_order to the position of each ID in ids.
+	Reorder(ctx context.Context, def *registry.Definition, ids []string, updatedBy string) error
+
+	// WritableColumns maps the JSON names clients may send to their columns.
+	WritableColumns(def *registry.Definition) (map[string]string, error)
+	// DuplicateColumn returns the first unique column whose value is taken
+	// by another live row, or "".
+	DuplicateColumn(ctx context.Context, def *registry.Definition, model any, excludeID string) (string, error)
+	// MissingReferences returns the foreign key columns naming no live row.
+	MissingReferences(ctx context.Context, def *registry.Definition, model any) ([]string, error)
+	// ReferencingTable returns the first table with live rows pointing at
+	// the row, or "".
+	ReferencingTable(ctx context.Context, dependents []registry.Dependent, id string) (string, error)
+}
+
+type masterRepository struct {
+	db      *gorm.DB
+	schemas sync.Map
+}
+
+// NewMasterRepository creates a new master data repository.
+func NewMasterRepository(db *gorm.DB) MasterRepository {
+	return &masterRepository{db: db}
+}
+
+func (r *masterRepository) List(ctx context.Context, def *registry.Definition, params *query.Params, scopes ...func(*gorm.DB) *gorm.DB) (any, int64, error) {
+	var total int64
+	if err := params.Count(r.db.WithContext(ctx).Model(def.NewModel()).Scopes(scopes...), &total); err != nil {
+		return nil, 0, err
+	}
+
+	items := def.NewSlice()
+	if err := params.Find(r.db.WithContext(ctx).Scopes(scopes...), items); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *masterRepository) ListAll(ctx context.Context, def *registry.Definition) (any, error) {
+	items := def.NewSlice()
+	if err := r.db.WithContext(ctx).Order(def.Order).Find(items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *masterRepository) Version(ctx context.Context, def *registry.Definition) (*dto.TableVersion, error) {
+	var row struct {
+		RowCount    int64
+		LastUpdate  *time.Time
+		LastDeleted *time.Time
+	}
+	err := r.db.WithContext(ctx).Unscoped().Model(def.NewModel()).
+		Select("COUNT(*) AS row_count, MAX(updated_at) AS last_update, MAX(deleted_at) AS last_deleted").
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	version := &dto.TableVersion{Rows: row.RowCount}
+	for _, t := range []*time.Time{row.LastUpdate, row.LastDeleted} {
+		if t != nil && t.After(version.Modified) {
+			version.Modified = *t
+		}
+	}
+	return version, nil
+}
+
+func (r *masterRepository) Changes(ctx context.Context, def *registry.Definition, since time.Time) (any, []dto.Tombstone, error) {
+	items := def.NewSlice()
+	if err := r.db.WithContext(ctx).Where("updated_at > ?", since).Order(def.Order).Find(items).Error; err != nil {
+		return nil, nil, err
+	}
+
+	deleted := []dto.Tombstone{}
+	err := r.db.WithContext(ctx).Unscoped().Model(def.NewModel()).
+		Select("id, deleted_at").
+		Where("deleted_at > ?", since).
+		Order("deleted_at").
+		Scan(&deleted).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, deleted, nil
+}
+
+func (r *masterRepository) Get(ctx context.Context, def *registry.Definition, id string) (any, error) {
+	model := def.NewModel()
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(model).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (r *masterRepository) GetBy(ctx context.Context, def *registry.Definition, column, value string) (any, error) {
+	model := def.NewModel()
+	if err := r.db.WithContext(ctx).Where(clause.Eq{Column: clause.Column{Name: column}, Value: value}).First(model).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (r *masterRepository) GetVersion(ctx context.Context, def *registry.Definition, model any) (any, error) {
+	existing := def.NewModel()
+	if err := def.Versioning.Find(r.db.WithContext(ctx), model, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (r *masterRepository) Create(ctx context.Context, def *registry.Definition, model any, omit []string, appendSort bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.create(ctx, tx, def, model, omit, appendSort)
+	})
+}
+
+func (r *masterRepository) create(ctx context.Context, tx *gorm.DB, def *registry.Definition, model any, omit []string, appendSort bool) error {
+	if v := def.Versioning; v != nil {
+		if err := v.Close(tx, model); err != nil {
+			return err
+		}
+		if err := v.Check(tx, model, ""); err != nil {
+			return err
+		}
+	}
+	if appendSort {
+		var next int
+		if err := tx.Model(def.NewModel()).Select("COALESCE(MAX(sort_order), 0) + 1").Scan(&next).Error; err != nil {
+			return err
+		}
+		if err := r.setColumn(ctx, def, model, "sort_order", next); err != nil {
+			return err
+		}
+	}
+	return tx.Omit(omit...).Create(model).Error
+}
+
+func (r *masterRepository) Update(ctx context.Context, def *registry.Definition, model any) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.update(tx, def, model)
+	})
+}
+
+func (r *masterRepository) update(tx *gorm.DB, def *registry.Definition, model any) error {
+	if v := def.Versioning; v != nil {
+		id := reflect.Indirect(reflect.ValueOf(model)).FieldByName("ID").String()
+		if err := v.Check(tx, model, id); err != nil {
+			return err
+		}
+	}
+	return tx.Save(model).Error
+}
+
+func (r *masterRepository) Import(ctx context.Context, def *registry.Definition, creates, updates []any, omit []string, appendSort bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range creates {
+			if err := r.create(ctx, tx, def, model, omit, appendSort); err != nil {
+				return err
+			}
+		}
+		for _, model := range updates {
+			if err := r.update(tx, def, model); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete soft-deletes the row, stamping updated_by with the caller.
+func (r *masterRepository) Delete(ctx context.Context, def *registry.Definition, id, deletedBy string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(def.NewModel()).Where("id = ?", id).Update("updated_by", deletedBy).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(def.NewModel()).Error
+	})
+}
+
+// Reorder returns gorm.ErrRecordNotFound if an ID names no live row.
+func (r *masterRepository) Reorder(ctx context.Context, def *registry.Definition, ids []string, updatedBy string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			result := tx.Model(def.NewModel()).Where("id = ?", id).Updates(map[string]any{
+				"sort_order": i + 1,
+				"updated_by": updatedBy,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+func (r *masterRepository) WritableColumns(def *registry.Definition) (map[string]string, error) {
+	s, err := r.schema(def)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]string, len(s.Fields))
+	for _, field := range s.Fields {
+		if field.DBName == "" || baseColumns[field.DBName] {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		columns[name] = field.DBName
+	}
+	return columns, nil
+}
+
+func (r *masterRepository) DuplicateColumn(ctx context.Context, def *registry.Definition, model any, excludeID string) (string, error) {
+	for _, column := range def.UniqueColumns {
+		value, err := r.columnValue(ctx, def, model, column)
+		if err != nil {
+			return "", err
+		}
+		if value == nil {
+			continue
+		}
+
+		query := r.db.WithContext(ctx).Model(def.NewModel()).Where(column+" = ?", value)
+		if excludeID != "" {
+			query = query.Where("id <> ?", excludeID)
+		}
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return column, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *masterRepository) MissingReferences(ctx context.Context, def *registry.Definition, model any) ([]string, error) {
+	var missing []string
+	for _, ref := range def.ForeignKeys {
+		value, err := r.columnValue(ctx, def, model, ref.Column)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+
+		var count int64
+		if err := r.db.WithContext(ctx).Table(ref.Table).Where("id = ? AND deleted_at IS NULL", value).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			missing = append(missing, ref.Column)
+		}
+	}
+	return missing, nil
+}
+
+func (r *masterRepository) ReferencingTable(ctx context.Context, dependents []registry.Dependent, id string) (string, error) {
+	for _, dep := range dependents {
+		var count int64
+		if err := r.db.WithContext(ctx).Table(dep.Table).Where(dep.Column+" = ? AND deleted_at IS NULL", id).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return dep.Table, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *masterRepository) schema(def *registry.Definition) (*schema.Schema, error) {
+	return schema.Parse(def.NewModel(), &r.schemas, r.db.NamingStrategy)
+}
+
+// columnValue returns the column's value, or nil when it is empty.
+func (r *masterRepository) columnValue(ctx context.Context, def *registry.Definition, model any, column string) (any, error) {
+	s, err := r.schema(def)
+	if err != nil {
+		return nil, err
+	}
+	field := s.LookUpField(column)
+	if field == nil {
+		return nil, nil
+	}
+
+	value, zero := field.ValueOf(ctx, reflect.ValueOf(model).Elem())
+	if zero {
+		return nil, nil
+	}
+	if ptr := reflect.ValueOf(value); ptr.Kind() == reflect.Ptr {
+		value = ptr.Elem().Interface()
+	}
+	return value, nil
+}
+
+func (r *masterRepository) setColumn(ctx context.Context, def *registry.Definition, model any, column string, value any) error {
+	s, err := r.schema(def)
+	if err != nil {
+		return err
+	}
+	field := s.LookUpField(column)
+	if field == nil {
+		return nil
+	}
+	return field.Set(ctx, reflect.ValueOf(model).Elem(), value)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// RegionRepository looks up rows of the region tables (mst_provinces,
+// mst_districts, mst_sub_districts, mst_villages) by their official code.
+// Models are pointers to the region entities.
+type RegionRepository interface {
+	FindByCode(ctx context.Context, model any, code string) error
+	// FindParent loads the parent row by its id, or by the legacy code
+	// column when the id is unset. It returns gorm.ErrRecordNotFound when
+	// both are unset.
+	FindParent(ctx context.Context, model any, id, code *string) error
+}
+
+type regionRepository struct {
+	db *gorm.DB
+}
+
+// NewRegionRepository creates a new region repository.
+func NewRegionRepository(db *gorm.DB) RegionRepository {
+	return &regionRepository{db: db}
+}
+
+func (r *regionRepository) FindByCode(ctx context.Context, model any, code string) error {
+	return r.db.WithContext(ctx).Where("code = ?", code).First(model).Error
+}
+
+func (r *regionRepository) FindParent(ctx context.Context, model any, id, code *string) error {
+	switch {
+	case id != nil && *id != "":
+		return r.db.WithContext(ctx).Where("id = ?", *id).First(model).Error
+	case code != nil && *code != "":
+		return r.FindByCode(ctx, model, *code)
+	default:
+		return gorm.ErrRecordNotFound
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/master/dto"
+	"github.com/user/go-boilerplate/internal/modules/master/registry"
+	"github.com/user/go-boilerplate/internal/modules/master/repository"
+	"github.com/user/go-boilerplate/pkg/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// CacheKeyPrefix prefixes the Redis keys of each master table: its
+	// generation, e.g. master:generation:banks, and its rows at that
+	// generation, e.g. master:banks:3.
+	CacheKeyPrefix = "master:"
+	// CacheTTL bounds how long a table stays in Redis.
+	CacheTTL = 1 * time.Hour
+	// InvalidationChannel carries the batch key of every written table to
+	// all API instances.
+	InvalidationChannel = "master:invalidate"
+
+	// localTTL bounds the in-process copy, in case an invalidation is
+	// missed while the subscription reconnects.
+	localTTL = 1 * time.Minute
+)
+
+// CacheService serves whole master tables for the batch API from an
+// in-process copy, then Redis, then the database, and the table versions
+// behind the ETag of master responses. Concurrent misses of one table share
+// a single load.
+type CacheService interface {
+	// Load returns every row of the table.
+	Load(ctx context.Context, def *registry.Definition) (any, error)
+	// Version returns the table's version, kept in-process like the rows.
+	Version(ctx context.Context, def *registry.Definition) (*dto.TableVersion, error)
+	// Invalidate drops the table, and the caches derived from it, from
+	// Redis and from the in-process copy of every instance. Call it after
+	// each committed write.
+	Invalidate(ctx context.Context, def *registry.Definition) error
+	// Listen evicts the tables invalidated by other instances until ctx is
+	// cancelled.
+	Listen(ctx context.Context)
+	// Warm loads the tables from the database into Redis.
+	Warm(ctx context.Context, defs []*registry.Definition) error
+}
+
+type localEntry struct {
+	value   any
+	expires time.Time
+}
+
+type cacheService struct {
+	repo  repository.MasterRepository
+	cache *cache.Client // nil disables Redis and pub/sub
+	group singleflight.Group
+
+	mu       sync.Mutex
+	local    map[string]localEntry
+	versions map[string]localEntry
+	// generation counts the invalidations of each table, so a load that
+	// raced an invalidation does not store what it read before the write.
+	generation map[string]uint64
+}
+
+// NewCacheService creates a new master cache service.
+func NewCacheService(repo repository.MasterRepository, client *cache.Client) CacheService {
+	return &cacheService{
+		repo:       repo,
+		cache:      client,
+		local:      make(map[string]localEntry),
+		versions:   make(map[string]localEntry),
+		generation: make(map[string]uint64),
+	}
+}
+
+func (s *cacheService) Load(ctx context.Context, def *registry.Definition) (any, error) {
+	if value, ok := s.fromLocal(s.local, def.Key); ok {
+		return value, nil
+	}
+
+	// The load outlives the caller that started it: the others share it.
+	ctx = context.WithoutCancel(ctx)
+	value, err, _ := s.group.Do(def.Key, func() (any, error) {
+		gen := s.currentGeneration(def.Key)
+
+		// The Redis generation is read before the database, so rows read
+		// before a write on any instance are stored where no read after the
+		// write looks.
+		var key string
+		if s.cache != nil {
+			if k, err := s.currentRowsKey(ctx, def.Key); err == nil {
+				key = k
+				var cached any
+				if found, err := s.cache.Get(ctx, key, &cached); err == nil && found {
+					s.store(s.local, def.Key, gen, cached)
+					return cached, nil
+				}
+			}
+		}
+
+		items, err := s.repo.ListAll(ctx, def)
+		if err != nil {
+			return nil, err
+		}
+		if s.store(s.local, def.Key, gen, items) && key != "" {
+			s.cache.Set(ctx, key, items, CacheTTL)
+		}
+		return items, nil
+	})
+	return value, err
+}
+
+func (s *cacheService) Version(ctx context.Context, def *registry.Definition) (*dto.TableVersion, error) {
+	if value, ok := s.fromLocal(s.versions, def.Key); ok {
+		return value.(*dto.TableVersion), nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	value, err, _ := s.group.Do("version:"+def.Key, func() (any, error) {
+		gen := s.currentGeneration(def.Key)
+		version, err := s.repo.Version(ctx, def)
+		if err != nil {
+			return nil, err
+		}
+		s.store(s.versions, def.Key, gen, version)
+		return version, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*dto.TableVersion), nil
+}
+
+func (s *cacheService) Invalidate(ctx context.Context, def *registry.Definition) error {
+	s.evict(def.Key)
+	if s.cache == nil {
+		return nil
+	}
+
+	// Every step is attempted so one failure leaves as little stale as possible.
+	var errs []error
+	if gen, err := s.cache.Incr(ctx, generationKey(def.Key), 0); err != nil {
+		errs = append(errs, err)
+	} else {
+		errs = append(errs, s.cache.Delete(ctx, rowsKey(def.Key, gen-1)))
+	}
+	for _, pattern := range def.DerivedCaches {
+		errs = append(errs, s.cache.DeleteByPattern(ctx, pattern))
+	}
+	errs = append(errs, s.cache.Publish(ctx, InvalidationChannel, def.Key))
+	return errors.Join(errs...)
+}
+
+func (s *cacheService) Listen(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.Subscribe(ctx, InvalidationChannel, s.evict)
+}
+
+func (s *cacheService) Warm(ctx context.Context, defs []*registry.Definition) error {
+	if s.cache == nil {
+		return errors.New("master cache: redis is not configured")
+	}
+	for _, def := range defs {
+		key, err := s.currentRowsKey(ctx, def.Key)
+		if err != nil {
+			return err
+		}
+		items, err := s.repo.ListAll(ctx, def)
+		if err != nil {
+			return err
+		}
+		if err := s.cache.Set(ctx, key, items, CacheTTL); err != nil {
+			return err
+		}
+		// Running instances drop their older in-process copies.
+		if err := s.cache.Publish(ctx, InvalidationChannel, def.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentRowsKey returns the Redis key of the table's rows at its current
+// generation.
+func (s *cacheService) currentRowsKey(ctx context.Context, key string) (string, error) {
+	var gen int64
+	if _, err := s.cache.Get(ctx, generationKey(key), &gen); err != nil {
+		return "", err
+	}
+	return rowsKey(key, gen), nil
+}
+
+func rowsKey(key string, gen int64) string {
+	return fmt.Sprintf("%s%s:%d", CacheKeyPrefix, key, gen)
+}
+
+// generationKey counts the invalidations of the table across instances.
+func generationKey(key string) string {
+	return CacheKeyPrefix + "generation:" + key
+}
+
+func (s *cacheService) fromLocal(entries map[string]localEntry, key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (s *cacheService) currentGeneration(key string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generation[key]
+}
+
+// store keeps value unless the table was invalidated since gen was read.
+func (s *cacheService) store(entries map[string]localEntry, key string, gen uint64, value any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation[key] != gen {
+		return false
+	}
+	entries[key] = localEntry{value: value, expires: time.Now().Add(localTTL)}
+	return true
+}
+
+func (s *cacheService) evict(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation[key]++
+	delete(s.local, key)
+	delete(s.versions, key)
+}
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/go-boilerplate/internal/modules/master/dto"
+	"github.com/user/go-boilerplate/internal/modules/master/registry"
+	"github.com/user/go-boilerplate/internal/modules/master/repository"
+	"github.com/user/go-boilerplate/internal/shared/effective"
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// MaxImportRows caps the data rows of one import file.
+const MaxImportRows = 10000
+
+// Date cells may be written in any of these layouts.
+var importDateLayouts = []string{"2006-01-02", time.RFC3339, "2006-01-02 15:04:05"}
+
+// ImportService loads master tables from spreadsheets. The header row names
+// the JSON fields of the entity. A row updates the row named by its id
+// column, or else by the table's first unique column, and inserts otherwise.
+// On effective-dated tables the row of the same natural key, start date and
+// row key is updated instead.
+type ImportService interface {
+	// Template returns the header row of def's import file.
+	Template(def *registry.Definition) ([]string, error)
+	// Import diffs rows (header first) against the table and, unless
+	// dryRun, applies the diff in one transaction. Nothing is written when
+	// a row is rejected.
+	Import(ctx context.Context, def *registry.Definition, rows [][]string, userID string, dryRun bool) (*dto.ImportResult, error)
+}
+
+type importService struct {
+	repo repository.MasterRepository
+}
+
+// NewImportService creates a new master data import service.
+func NewImportService(repo repository.MasterRepository) ImportService {
+	return &importService{repo: repo}
+}
+
+// importPlan is the diff of one file together with the models to write.
+type importPlan struct {
+	result  *dto.ImportResult
+	creates []any
+	updates []any
+	columns map[string]string // JSON name to column of every writable field
+	header  []string
+	match   []string   // JSON names identifying existing rows besides id
+	keys    []matchKey // Values rows may not repeat
+}
+
+// matchKey records the rows that used each value of one or more columns.
+// Rows with the first column blank are not recorded.
+type matchKey struct {
+	names []string
+	seen  map[string]int
+}
+
+func (s *importService) Template(def *registry.Definition) ([]string, error) {
+	columns, err := s.repo.WritableColumns(def)
+	if err != nil {
+		return nil, err
+	}
+
+	// The columns identifying existing rows first.
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	match := matchNames(def, columns)
+	header := append([]string(nil), match...)
+	if len(match) == 0 {
+		header = append(header, "id")
+	}
+	for _, name := range names {
+		if !slices.Contains(match, name) {
+			header = append(header, name)
+		}
+	}
+	return header, nil
+}
+
+func (s *importService) Import(ctx context.Context, def *registry.Definition, rows [][]string, userID string, dryRun bool) (*dto.ImportResult, error) {
+	plan, err := s.newPlan(def, rows)
+	if err != nil {
+		return nil, err
+	}
+	plan.result.DryRun = dryRun
+
+	for i, cells := range rows[1:] {
+		if blank(cells) {
+			continue
+		}
+		if err := s.planRow(ctx, def, plan, i+2, cells, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun || len(plan.result.Rejected) > 0 {
+		return plan.result, nil
+	}
+
+	var omit []string
+	appendSort := def.SortOrder && !slices.Contains(plan.header, "sort_order")
+	for name, column := range plan.columns {
+		if !slices.Contains(plan.header, name) && !(appendSort && column == "sort_order") {
+			omit = append(omit, column)
+		}
+	}
+	if err := s.repo.Import(ctx, def, plan.creates, plan.updates, omit, appendSort); err != nil {
+		if errors.Is(err, effective.ErrOverlap) {
+			return nil, apperror.Conflict("A row's validity window overlaps another version")
+		}
+		if errors.Is(err, effective.ErrNoStart) {
+			return nil, apperror.BadRequest("Every row needs " + def.Versioning.Start)
+		}
+		return nil, err
+	}
+	return plan.result, nil
+}
+
+// newPlan checks the header row.
+func (s *importService) newPlan(def *registry.Definition, rows [][]string) (*importPlan, error) {
+	if len(rows) == 0 {
+		return nil, apperror.BadRequest("The file is empty")
+	}
+	if len(rows)-1 > MaxImportRows {
+		return nil, apperror.BadRequest(fmt.Sprintf("The file has more than %d rows", MaxImportRows))
+	}
+
+	columns, err := s.repo.WritableColumns(def)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &importPlan{
+		result: &dto.ImportResult{
+			Inserts:   []dto.ImportRow{},
+			Updates:   []dto.ImportRow{},
+			Unchanged: []dto.ImportRow{},
+			Rejected:  []dto.ImportRow{},
+		},
+		columns: columns,
+	}
+	for _, cell := range rows[0] {
+		// Excel prefixes UTF-8 CSV files with a byte order mark.
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff")))
+		if _, ok := columns[name]; !ok && name != "id" {
+			return nil, apperror.BadRequest(fmt.Sprintf("Unknown column %q", cell))
+		}
+		if slices.Contains(plan.header, name) {
+			return nil, apperror.BadRequest(fmt.Sprintf("Column %q appears twice", name))
+		}
+		plan.header = append(plan.header, name)
+	}
+	plan.match = matchNames(def, columns)
+	plan.keys = append(plan.keys, matchKey{names: []string{"id"}, seen: map[string]int{}})
+	for _, name := range columnNames(columns, def.UniqueColumns) {
+		plan.keys = append(plan.keys, matchKey{names: []string{name}, seen: map[string]int{}})
+	}
+	if def.Versioning != nil {
+		plan.keys = append(plan.keys, matchKey{names: plan.match, seen: map[string]int{}})
+	}
+	return plan, nil
+}
+
+// planRow classifies one data row as insert, update, unchanged or rejected.
+func (s *importService) planRow(ctx context.Context, def *registry.Definition, plan *importPlan, row int, cells []string, userID string) error {
+	values := make(map[string]string, len(plan.header))
+	for i, name := range plan.header {
+		if i < len(cells) {
+			values[name] = strings.TrimSpace(cells[i])
+		} else {
+			values[name] = ""
+		}
+	}
+	reject := func(reasons ...string) error {
+		plan.result.Rejected = append(plan.result.Rejected, dto.ImportRow{Row: row, Data: anyMap(values), Errors: reasons})
+		return nil
+	}
+
+	// Rows may not repeat an id, a unique value or a version row of an
+	// earlier row.
+	for _, key := range plan.keys {
+		if values[key.names[0]] == "" {
+			continue
+		}
+		cells := make([]string, len(key.names))
+		for i, name := range key.names {
+			cells[i] = values[name]
+		}
+		v := strings.Join(cells, ", ")
+		if first, dup := key.seen[v]; dup {
+			return reject(fmt.Sprintf("%s %q repeats row %d", strings.Join(key.names, ", "), v, first))
+		}
+		key.seen[v] = row
+	}
+
+	payload, reasons := convertRow(def.NewModel(), plan.header, values)
+	if len(reasons) > 0 {
+		return reject(reasons...)
+	}
+
+	existing, err := s.lookup(ctx, def, plan, values, payload)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return reject("id does not name an existing record")
+		}
+		return err
+	}
+
+	model := def.NewModel()
+	var before map[string]any
+	if existing != nil {
+		model = existing
+		before = toMap(existing)
+	}
+	base := registry.BaseOf(model)
+	saved := *base
+	if err := json.Unmarshal(payload, model); err != nil {
+		return reject(err.Error())
+	}
+	*base = saved
+	if existing == nil {
+		*base = sharedentity.Base{CreatedBy: &userID, UpdatedBy: &userID}
+	} else {
+		base.UpdatedBy = &userID
+	}
+
+	if reasons, err := s.check(ctx, def, model, base.ID); err != nil {
+		return err
+	} else if len(reasons) > 0 {
+		return reject(reasons...)
+	}
+	// A version without a start date would never be listed as of a date.
+	if def.Versioning != nil && len(plan.match) > 0 && toMap(model)[plan.match[0]] == nil {
+		return reject(plan.match[0] + " is required")
+	}
+
+	entry := dto.ImportRow{Row: row, ID: base.ID, Data: anyMap(values)}
+	if existing == nil {
+		plan.result.Inserts = append(plan.result.Inserts, entry)
+		plan.creates = append(plan.creates, model)
+		return nil
+	}
+
+	after := toMap(model)
+	changes := map[string]dto.ImportChange{}
+	for _, name := range plan.header {
+		if name != "id" && !reflect.DeepEqual(before[name], after[name]) {
+			changes[name] = dto.ImportChange{From: before[name], To: after[name]}
+		}
+	}
+	if len(changes) == 0 {
+		plan.result.Unchanged = append(plan.result.Unchanged, entry)
+		return nil
+	}
+	entry.Changes = changes
+	plan.result.Updates = append(plan.result.Updates, entry)
+	plan.updates = append(plan.updates, model)
+	return nil
+}
+
+// lookup returns the existing row the values name, or nil for an insert.
+func (s *importService) lookup(ctx context.Context, def *registry.Definition, plan *importPlan, values map[string]string, payload []byte) (any, error) {
+	if id := values["id"]; id != "" {
+		return s.repo.GetBy(ctx, def, "id", id)
+	}
+	if len(plan.match) == 0 || values[plan.match[0]] == "" {
+		return nil, nil
+	}
+
+	var existing any
+	var err error
+	if def.Versioning != nil {
+		// A payload that does not decode is rejected by the caller.
+		model := def.NewModel()
+		if err := json.Unmarshal(payload, model); err != nil {
+			return nil, nil
+		}
+		existing, err = s.repo.GetVersion(ctx, def, model)
+	} else {
+		existing, err = s.repo.GetBy(ctx, def, plan.columns[plan.match[0]], values[plan.match[0]])
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return existing, err
+}
+
+// check applies the same rules as the CRUD endpoints: validate tags,
+// unique columns and foreign keys.
+func (s *importService) check(ctx context.Context, def *registry.Definition, model any, excludeID string) ([]string, error) {
+	var reasons []string
+	if appErr := validator.Validate(model); appErr != nil {
+		if details, ok := appErr.Details.([]validator.ValidationError); ok {
+			for _, d := range details {
+				reasons = append(reasons, d.Field+": "+d.Message)
+			}
+		} else {
+			reasons = append(reasons, appErr.Message)
+		}
+		return reasons, nil
+	}
+
+	column, err := s.repo.DuplicateColumn(ctx, def, model, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	if column != "" {
+		reasons = append(reasons, "a record with this "+column+" already exists")
+	}
+
+	missing, err := s.repo.MissingReferences(ctx, def, model)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range missing {
+		reasons = append(reasons, column+" does not name an existing record")
+	}
+	return reasons, nil
+}
+
+// convertRow turns the cells into a JSON object typed after the entity's
+// fields. Empty cells are null for optional fields and zero otherwise.
+func convertRow(model any, header []string, values map[string]string) ([]byte, []string) {
+	types := fieldTypes(reflect.TypeOf(model).Elem())
+	object := make(map[string]any, len(header))
+	var reasons []string
+	for _, name := range header {
+		if name == "id" {
+			continue
+		}
+		value, err := convertCell(values[name], types[name])
+		if err != nil {
+			reasons = append(reasons, name+": "+err.Error())
+			continue
+		}
+		object[name] = value
+	}
+	payload, _ := json.Marshal(object)
+	return payload, reasons
+}
+
+func convertCell(cell string, t reflect.Type) (any, error) {
+	if t.Kind() == reflect.Ptr {
+		if cell == "" {
+			return nil, nil
+		}
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		if cell == "" {
+			return time.Time{}, nil
+		}
+		for _, layout := range importDateLayouts {
+			if v, err := time.Parse(layout, cell); err == nil {
+				return v, nil
+			}
+		}
+		return nil, errors.New("invalid date, expected YYYY-MM-DD")
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		switch strings.ToLower(cell) {
+		case "", "false", "0", "no", "n":
+			return false, nil
+		case "true", "1", "yes", "y":
+			return true, nil
+		}
+		return nil, errors.New("invalid boolean, expected true or false")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if cell == "" {
+			return 0, nil
+		}
+		v, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid whole number")
+		}
+		return v, nil
+	case reflect.Float32, reflect.Float64:
+		if cell == "" {
+			return 0, nil
+		}
+		v, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, errors.New("invalid number")
+		}
+		return v, nil
+	default:
+		return cell, nil
+	}
+}
+
+// fieldTypes maps the JSON names of the entity's own fields to their types.
+func fieldTypes(t reflect.Type) map[string]reflect.Type {
+	types := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			types[name] = field.Type
+		}
+	}
+	return types
+}
+
+// matchNames returns the JSON names identifying existing rows: the start,
+// natural key and row key of effective-dated tables, or else the unique
+// columns. Only the first unique column is matched on.
+func matchNames(def *registry.Definition, columns map[string]string) []string {
+	if v := def.Versioning; v != nil {
+		wanted := append([]string{v.Start}, v.Key...)
+		return columnNames(columns, append(wanted, v.RowKey...))
+	}
+	return columnNames(columns, def.UniqueColumns)
+}
+
+// columnNames returns the JSON names of the wanted columns, in order.
+func columnNames(columns map[string]string, wanted []string) []string {
+	var names []string
+	for _, want := range wanted {
+		for name, column := range columns {
+			if column == want {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func toMap(model any) map[string]any {
+	raw, _ := json.Marshal(model)
+	var m map[string]any
+	_ = json.Unmarshal(raw, &m)
+	return m
+}
+
+func anyMap(values map[string]string) map[string]any {
+	m := make(map[string]any, len(values))
+	for k, v := range values {
+		m[k] = v
+	}
+	return m
+}
+
+func blank(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/user/go-boilerplate/internal/modules/master/dto"
+	"github.com/user/go-boilerplate/internal/modules/master/entity"
+	"github.com/user/go-boilerplate/internal/modules/master/repository"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// RegionService resolves official region codes to their chain of ancestors
+// and checks that an address's province, district, sub-district and village
+// belong together.
+type RegionService interface {
+	// Resolve accepts a code of any level.
+	Resolve(ctx context.Context, code string) (*dto.RegionChain, error)
+	// Validate returns the chain of the narrowest code given.
+	Validate(ctx context.Context, req *dto.ValidateRegionRequest) (*dto.RegionChain, error)
+}
+
+type regionService struct {
+	repo repository.RegionRepository
+}
+
+// NewRegionService creates a new region service.
+func NewRegionService(repo repository.RegionRepository) RegionService {
+	return &regionService{repo: repo}
+}
+
+func (s *regionService) Resolve(ctx context.Context, code string) (*dto.RegionChain, error) {
+	// Official codes grow one segment per level, so at most one level matches.
+	for _, level := range []string{dto.RegionVillage, dto.RegionSubDistrict, dto.RegionDistrict, dto.RegionProvince} {
+		chain, err := s.find(ctx, level, code)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return chain, nil
+	}
+	return nil, apperror.NotFound("Region not found")
+}
+
+func (s *regionService) Validate(ctx context.Context, req *dto.ValidateRegionRequest) (*dto.RegionChain, error) {
+	if appErr := validator.Validate(req); appErr != nil {
+		return nil, appErr
+	}
+
+	type regionCode struct {
+		level, field, code string
+	}
+	codes := []regionCode{
+		{dto.RegionProvince, "province_code", req.ProvinceCode},
+		{dto.RegionDistrict, "district_code", req.DistrictCode},
+		{dto.RegionSubDistrict, "sub_district_code", req.SubDistrictCode},
+		{dto.RegionVillage, "village_code", req.VillageCode},
+	}
+	var narrowest *regionCode
+	for i := range codes {
+		if codes[i].code != "" {
+			narrowest = &codes[i]
+		}
+	}
+	if narrowest == nil {
+		return nil, apperror.BadRequest("At least one region code is required")
+	}
+
+	chain, err := s.find(ctx, narrowest.level, narrowest.code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.Validation("Validation failed", []validator.ValidationError{
+			{Field: narrowest.field, Message: narrowest.field + " does not name a " + levelName(narrowest.level)},
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var details []validator.ValidationError
+	for _, rc := range codes {
+		if rc.code == "" || rc.level == narrowest.level || codeAt(chain, rc.level) == rc.code {
+			continue
+		}
+		err := s.repo.FindByCode(ctx, newRegion(rc.level), rc.code)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			details = append(details, validator.ValidationError{Field: rc.field, Message: rc.field + " does not name a " + levelName(rc.level)})
+		case err != nil:
+			return nil, err
+		default:
+			details = append(details, validator.ValidationError{Field: rc.field, Message: rc.field + " is not the " + levelName(rc.level) + " of " + narrowest.field})
+		}
+	}
+	if len(details) > 0 {
+		return nil, apperror.Validation("Validation failed", details)
+	}
+	return chain, nil
+}
+
+// find loads the region of the level by code, then its ancestors. It
+// returns gorm.ErrRecordNotFound when the code names no region of the level.
+func (s *regionService) find(ctx context.Context, level, code string) (*dto.RegionChain, error) {
+	region := newRegion(level)
+	if err := s.repo.FindByCode(ctx, region, code); err != nil {
+		return nil, err
+	}
+
+	chain := &dto.RegionChain{Level: level}
+	switch r := region.(type) {
+	case *entity.Province:
+		chain.Province = r
+	case *entity.District:
+		chain.District = r
+	case *entity.SubDistrict:
+		chain.SubDistrict = r
+	case *entity.Village:
+		chain.Village = r
+	}
+	if err := s.complete(ctx, chain); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+// complete fills the ancestors above the chain's level, stopping at the
+// first one that cannot be found.
+func (s *regionService) complete(ctx context.Context, chain *dto.RegionChain) error {
+	if v := chain.Village; v != nil {
+		parent := &entity.SubDistrict{}
+		if err := s.repo.FindParent(ctx, parent, v.SubDistrictID, v.SubDistrictCode); err != nil {
+			return ignoreNotFound(err)
+		}
+		chain.SubDistrict = parent
+	}
+	if sd := chain.SubDistrict; sd != nil {
+		parent := &entity.District{}
+		if err := s.repo.FindParent(ctx, parent, sd.DistrictID, sd.DistrictCode); err != nil {
+			return ignoreNotFound(err)
+		}
+		chain.District = parent
+	}
+	if d := chain.District; d != nil {
+		parent := &entity.Province{}
+		if err := s.repo.FindParent(ctx, parent, d.ProvinceID, d.ProvinceCode); err != nil {
+			return ignoreNotFound(err)
+		}
+		chain.Province = parent
+	}
+	return nil
+}
+
+func newRegion(level string) any {
+	switch level {
+	case dto.RegionProvince:
+		return &entity.Province{}
+	case dto.RegionDistrict:
+		return &entity.District{}
+	case dto.RegionSubDistrict:
+		return &entity.SubDistrict{}
+	default:
+		return &entity.Village{}
+	}
+}
+
+// codeAt returns the code of the chain's region at level, or "".
+func codeAt(chain *dto.RegionChain, level string) string {
+	switch {
+	case level == dto.RegionProvince && chain.Province != nil:
+		return chain.Province.Code
+	case level == dto.RegionDistrict && chain.District != nil:
+		return chain.District.Code
+	case level == dto.RegionSubDistrict && chain.SubDistrict != nil:
+		return chain.SubDistrict.Code
+	case level == dto.RegionVillage && chain.Village != nil:
+		return chain.Village.Code
+	}
+	return ""
+}
+
+func levelName(level string) string {
+	return strings.ReplaceAll(level, "_", "-")
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package master
+
+import (
+	"github.com/user/go-boilerplate/internal/modules/master/entity"
+	"github.com/user/go-boilerplate/internal/modules/master/registry"
+	"github.com/user/go-boilerplate/internal/shared/effective"
+)
+
+// Tables lists the master tables served under /api/master. A new master
+// table needs its entity and one line here.
+var Tables = registry.New(
+	registry.Entity[entity.Area]("areas").Unique("code").Searchable("code", "name"),
+	registry.Entity[entity.Province]("provinces").Unique("code").Searchable("code", "description").Sorted(),
+	registry.Entity[entity.District]("districts").Unique("code").Searchable("code", "description").ChildOf("province_id", "mst_provinces").Sorted(),
+	registry.Entity[entity.SubDistrict]("sub-districts").Unique("code").Searchable("code", "description").ChildOf("district_id", "mst_districts").Sorted(),
+	registry.Entity[entity.Village]("villages").Unique("code").Searchable("code", "description").ChildOf("sub_district_id", "mst_sub_districts").Sorted(),
+	registry.Entity[entity.Bank]("banks").Unique("code").Searchable("code", "name"),
+	registry.Entity[entity.MainBranch]("main-branches").Unique("code").Searchable("code", "name").References("area_id", "mst_areas").Sorted(),
+	registry.Entity[entity.Branch]("branches").Unique("code").Searchable("code", "description").Sorted().
+		References("area_id", "mst_areas").
+		References("main_branch_id", "mst_branches").
+		ReferencedBy("sys_users", "branch_id").
+		ReferencedBy("app_batchings", "branch_id").
+		ReferencedBy("app_giro_reconciliations", "branch_id").
+		Derives("auth:branch_scope:*"),
+	registry.Entity[entity.Gender]("genders").Unique("code").Searchable("code", "description").Sorted(),
+	registry.Entity[entity.Religion]("religions").Searchable("description"),
+	registry.Entity[entity.MaritalStatus]("marital-statuses").Searchable("description").Sorted(),
+	registry.Entity[entity.Citizenship]("citizenships").Unique("code").Searchable("code", "description").Sorted(),
+	registry.Entity[entity.EducationLevel]("education-levels").Searchable("description").Sorted(),
+	registry.Entity[entity.Currency]("currencies").Unique("code").Searchable("code", "description").Sorted(),
+	registry.Entity[entity.TaxGroup]("tax-groups").Searchable("name").
+		EffectiveDated(effective.Point("effective_date", "name")),
+	registry.Entity[entity.TaxBracket]("tax-brackets").
+		EffectiveDated(effective.Point("effective_date", "effective_tax_rate_category").Rows("min_income")),
+	registry.Entity[entity.TaxBracketMinistryRegulation16]("tax-brackets-ministry-regulation-16").
+		EffectiveDated(effective.Point("effective_date", "effective_tax_rate_category").Rows("minimum_income")),
+	registry.Entity[entity.TaxBracketIncomeTaxArticle17]("tax-brackets-income-tax-article-17").
+		EffectiveDated(effective.Point("effective_date", "effective_tax_rate_category").Rows("minimum_income")),
+	registry.Entity[entity.EffectiveTaxRateLayer]("effective-tax-rate-layers").
+		References("effective_tax_rate_category_id", "mst_effective_tax_rate_categories").
+		EffectiveDated(effective.Point("effective_date", "effective_tax_rate_category_id").Rows("minimum_amount")),
+	registry.Entity[entity.SeveranceBenefit]("severance-benefits").Searchable("termination_reason").
+		EffectiveDated(effective.Point("effective_date", "termination_reason")),
+)
+
+// Entities lists the models of the master tables, checked against the
+// database by cli schema:verify.
+var Entities = Tables.Models()
@@ -0,0 +1,18 @@
+package system
+
+import "github.com/user/go-boilerplate/internal/modules/system/entity"
+
+// Entities lists the models of the system tables, checked against the
+// database by cli schema:verify.
+var Entities = []any{
+	&entity.AppInfo{},
+	&entity.Role{},
+	&entity.SubRole{},
+	&entity.Permission{},
+	&entity.RolePermission{},
+	&entity.SubMenu{},
+	&entity.BaseFee{},
+	&entity.BankFee{},
+	&entity.TransactionFee{},
+	&entity.PensionAge{},
+}
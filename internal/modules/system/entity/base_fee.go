@@ -1,12 +1,22 @@
 package entity
 
-import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+import (
+	"time"
 
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+// BaseFee is one version of a fee group's registration and administration
+// fees, valid from EffectiveStartDate until EffectiveEndDate (open if nil).
 type BaseFee struct {
 	sharedentity.Base
-	Code   string  `json:"code"`
-	Name   string  `json:"name"`
-	Amount float64 `json:"amount"`
+	IsActive           bool       `json:"is_active"`
+	IsDefault          bool       `json:"is_default"`
+	EffectiveStartDate *time.Time `json:"effective_start_date"`
+	EffectiveEndDate   *time.Time `json:"effective_end_date"`
+	GroupName          string     `json:"group_name"`
+	RegistrationFee    int64      `json:"registration_fee"`
+	AdministrationFee  int64      `json:"administration_fee"`
 }
 
 func (BaseFee) TableName() string { return "sys_base_fees" }
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+// PensionAge is the normal and early pension age from EffectiveDate until
+// the next configuration takes effect.
+type PensionAge struct {
+	sharedentity.Base
+	EffectiveDate       *time.Time `json:"effective_date"`
+	NormalPensionAge    int        `json:"normal_pension_age"`
+	EarlyPensionAge     int        `json:"early_pension_age"`
+	ReferenceRegulation *string    `json:"reference_regulation"`
+	Description         *string    `json:"description"`
+}
+
+func (PensionAge) TableName() string { return "sys_pension_ages" }
@@ -0,0 +1,21 @@
+package entity
+
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
+type Permission struct {
+	sharedentity.Base
+	Code        string `json:"code"`
+	Module      string `json:"module"`
+	Description string `json:"description"`
+}
+
+func (Permission) TableName() string { return "sys_permissions" }
+
+type RolePermission struct {
+	ID           string  `json:"id" gorm:"primaryKey"`
+	RoleID       string  `json:"role_id"`
+	SubRoleID    *string `json:"sub_role_id,omitempty"`
+	PermissionID string  `json:"permission_id"`
+}
+
+func (RolePermission) TableName() string { return "sys_role_permissions" }
@@ -1,10 +1,14 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type Role struct {
-	ID          string `json:"id" gorm:"primaryKey"`
-	Code        string `json:"code"`
-	Name        string `json:"name"`
+	sharedentity.Base
 	Description string `json:"description"`
+	IsActive    bool   `json:"is_active"`
+	RequireMFA  bool   `json:"require_mfa" gorm:"column:require_mfa"`
+	// Holders may make changes while impersonating another user
+	AllowImpersonationWrite bool `json:"allow_impersonation_write"`
 }
 
 func (Role) TableName() string { return "sys_roles" }
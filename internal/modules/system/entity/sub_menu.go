@@ -1,13 +1,19 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type SubMenu struct {
-	ID       string `json:"id" gorm:"primaryKey"`
-	ParentID string `json:"parent_id"`
-	Code     string `json:"code"`
-	Name     string `json:"name"`
-	Path     string `json:"path"`
-	Icon     string `json:"icon"`
-	Order    int    `json:"order"`
+	sharedentity.Base
+	MenuID         *string `json:"menu_id,omitempty" gorm:"type:uuid"`
+	ParentID       *string `json:"parent_id,omitempty" gorm:"type:uuid"`
+	Key            string  `json:"key"`
+	Label          string  `json:"label"`
+	Icon           *string `json:"icon,omitempty"`
+	RouterLink     *string `json:"router_link,omitempty"`
+	Path           *string `json:"path,omitempty"`
+	IsVisible      bool    `json:"is_visible"`
+	SortOrder      int     `json:"sort_order"`
+	PermissionCode *string `json:"permission_code,omitempty"`
 }
 
 func (SubMenu) TableName() string { return "sys_sub_menus" }
@@ -1,11 +1,12 @@
 package entity
 
+import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+
 type SubRole struct {
-	ID          string `json:"id" gorm:"primaryKey"`
+	sharedentity.Base
 	RoleID      string `json:"role_id"`
-	Code        string `json:"code"`
-	Name        string `json:"name"`
 	Description string `json:"description"`
+	IsActive    bool   `json:"is_active"`
 }
 
 func (SubRole) TableName() string { return "sys_sub_roles" }
@@ -1,13 +1,36 @@
 package entity
 
-import sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+import (
+	"time"
 
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+// TransactionFee is one version of a fee group's transaction fees, valid
+// from EffectiveAtStart until EffectiveAtEnd (open if nil).
 type TransactionFee struct {
 	sharedentity.Base
-	TransactionType string  `json:"transaction_type"`
-	FeeType         string  `json:"fee_type"`
-	Amount          float64 `json:"amount"`
-	Percentage      float64 `json:"percentage"`
+	GroupingName *string `json:"grouping_name"`
+	IsDefault    bool    `json:"is_default"`
+	IsActive     bool    `json:"is_active"`
+	TransactionFeeRates
+	EffectiveAtStart *time.Time `json:"effective_at_start"`
+	EffectiveAtEnd   *time.Time `json:"effective_at_end"`
+}
+
+// TransactionFeeRates are the fee amounts of a transaction fee version.
+type TransactionFeeRates struct {
+	BenefitPensionQuitWork       *float64 `json:"benefit_pension_quit_work"`
+	MovePension                  *float64 `json:"move_pension"`
+	BenefitPensionClaim          *float64 `json:"benefit_pension_claim"`
+	BenefitPensionYearlyAdmin    *float64 `json:"benefit_pension_yearly_admin"`
+	MovePackageInvestGt2y        *float64 `json:"move_package_invest_gt_2y" gorm:"column:move_package_invest_gt_2y"`
+	MovePackageInvestLt2y        *float64 `json:"move_package_invest_lt_2y" gorm:"column:move_package_invest_lt_2y"`
+	ClaimWithdrawalPartialFirst  *float64 `json:"claim_withdrawal_partial_first"`
+	ClaimWithdrawalPartialSecond *float64 `json:"claim_withdrawal_partial_second"`
+	MoveFundOutLt3y              *float64 `json:"move_fund_out_lt_3y" gorm:"column:move_fund_out_lt_3y"`
+	MoveFundOutBw3y              *float64 `json:"move_fund_out_bw_3y" gorm:"column:move_fund_out_bw_3y"`
+	MoveFundOutGt3y              *float64 `json:"move_fund_out_gt_3y" gorm:"column:move_fund_out_gt_3y"`
 }
 
 func (TransactionFee) TableName() string { return "sys_transaction_fees" }
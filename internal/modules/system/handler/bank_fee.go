@@ -5,25 +5,34 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/modules/system/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
 	"github.com/user/go-boilerplate/internal/shared/response"
-	"github.com/user/go-boilerplate/pkg/utils"
 	"gorm.io/gorm"
 )
 
 type BankFeeHandler struct{ db *gorm.DB }
 
+var bankFeeQuery = query.For(&entity.BankFee{}, "fee_type")
+
 func NewBankFeeHandler(db *gorm.DB) *BankFeeHandler { return &BankFeeHandler{db: db} }
 
 func (h *BankFeeHandler) List(c *gin.Context) {
 	var items []entity.BankFee
 	var total int64
-	params := utils.GetPaginationParams(c)
+	params, appErr := query.Parse(c, bankFeeQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
 
-	h.db.Model(&entity.BankFee{}).Count(&total)
+	if err := params.Count(h.db.Model(&entity.BankFee{}), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch bank fees", nil)
+		return
+	}
 
-	if err := h.db.Offset(params.Offset()).Limit(params.Limit).Find(&items).Error; err != nil {
+	if err := params.Find(h.db, &items); err != nil {
 		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch bank fees", nil)
 		return
 	}
-	response.Paginated(c, http.StatusOK, items, total, params.Page, params.Limit)
+	response.Paginated(c, http.StatusOK, items, total, params)
 }
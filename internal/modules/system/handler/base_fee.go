@@ -2,28 +2,99 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/modules/system/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
 	"github.com/user/go-boilerplate/internal/shared/response"
-	"github.com/user/go-boilerplate/pkg/utils"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
 	"gorm.io/gorm"
 )
 
 type BaseFeeHandler struct{ db *gorm.DB }
 
+var baseFeeQuery = query.For(&entity.BaseFee{}, "group_name")
+
+// CreateBaseFeeRequest is the payload for a new base fee version. Without an
+// end date it stays valid until the next version of the group starts.
+type CreateBaseFeeRequest struct {
+	GroupName          string     `json:"group_name" validate:"required,max=255"`
+	IsActive           *bool      `json:"is_active"`
+	IsDefault          bool       `json:"is_default"`
+	EffectiveStartDate *time.Time `json:"effective_start_date" validate:"required"`
+	EffectiveEndDate   *time.Time `json:"effective_end_date"`
+	RegistrationFee    int64      `json:"registration_fee" validate:"min=0"`
+	AdministrationFee  int64      `json:"administration_fee" validate:"min=0"`
+}
+
 func NewBaseFeeHandler(db *gorm.DB) *BaseFeeHandler { return &BaseFeeHandler{db: db} }
 
+// List returns base fee versions; ?as_of= keeps the ones valid on a date.
 func (h *BaseFeeHandler) List(c *gin.Context) {
 	var items []entity.BaseFee
 	var total int64
-	params := utils.GetPaginationParams(c)
+	params, appErr := query.Parse(c, baseFeeQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+	asOf, appErr := asOfScope(c, baseFeeVersions)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
 
-	h.db.Model(&entity.BaseFee{}).Count(&total)
+	if err := params.Count(h.db.Model(&entity.BaseFee{}).Scopes(asOf), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch base fees", nil)
+		return
+	}
 
-	if err := h.db.Offset(params.Offset()).Limit(params.Limit).Find(&items).Error; err != nil {
+	if err := params.Find(h.db.Scopes(asOf), &items); err != nil {
 		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch base fees", nil)
 		return
 	}
-	response.Paginated(c, http.StatusOK, items, total, params.Page, params.Limit)
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
+
+// Create adds a new version of a fee group. The group's open version is
+// closed at the new start date; windows overlapping another version of the
+// group are rejected.
+func (h *BaseFeeHandler) Create(c *gin.Context) {
+	var req CreateBaseFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+	if appErr := checkWindow("effective_end_date", req.EffectiveStartDate, req.EffectiveEndDate); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	item := entity.BaseFee{
+		IsActive:           req.IsActive == nil || *req.IsActive,
+		IsDefault:          req.IsDefault,
+		EffectiveStartDate: req.EffectiveStartDate,
+		EffectiveEndDate:   req.EffectiveEndDate,
+		GroupName:          req.GroupName,
+		RegistrationFee:    req.RegistrationFee,
+		AdministrationFee:  req.AdministrationFee,
+	}
+	item.CreatedBy, item.UpdatedBy = &userID, &userID
+
+	if err := insertVersion(h.db, baseFeeVersions, &item); err != nil {
+		if appErr := versionError(err); appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to create base fee", nil)
+		return
+	}
+	response.Success(c, http.StatusCreated, "Base fee created", item)
 }
@@ -1,29 +1,353 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"sort"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/go-boilerplate/internal/middleware"
 	"github.com/user/go-boilerplate/internal/modules/system/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
 	"github.com/user/go-boilerplate/internal/shared/response"
-	"github.com/user/go-boilerplate/pkg/utils"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
 	"gorm.io/gorm"
 )
 
 type MenuHandler struct{ db *gorm.DB }
 
+var menuQuery = query.For(&entity.SubMenu{}, "key", "label").OrderBy(menuOrder)
+
 func NewMenuHandler(db *gorm.DB) *MenuHandler { return &MenuHandler{db: db} }
 
+// menuOrder is the stable sibling order used by every menu endpoint.
+const menuOrder = "sort_order ASC, label ASC, id ASC"
+
+// MenuNode is a sub-menu with its resolved children.
+type MenuNode struct {
+	ID         string      `json:"id"`
+	MenuID     *string     `json:"menu_id,omitempty"`
+	Key        string      `json:"key"`
+	Label      string      `json:"label"`
+	Icon       *string     `json:"icon,omitempty"`
+	RouterLink *string     `json:"router_link,omitempty"`
+	Path       *string     `json:"path,omitempty"`
+	SortOrder  int         `json:"sort_order"`
+	Children   []*MenuNode `json:"children"`
+}
+
+// CreateMenuRequest is the payload for creating a menu node.
+type CreateMenuRequest struct {
+	MenuID         *string `json:"menu_id" validate:"omitempty,uuid"`
+	ParentID       *string `json:"parent_id" validate:"omitempty,uuid"`
+	Key            string  `json:"key" validate:"required,max=255"`
+	Label          string  `json:"label" validate:"required,max=255"`
+	Icon           *string `json:"icon" validate:"omitempty,max=255"`
+	RouterLink     *string `json:"router_link" validate:"omitempty,max=255"`
+	Path           *string `json:"path" validate:"omitempty,max=255"`
+	IsVisible      *bool   `json:"is_visible"`
+	SortOrder      int     `json:"sort_order"`
+	PermissionCode *string `json:"permission_code" validate:"omitempty,max=100"`
+}
+
+// MoveMenuRequest re-parents and/or re-orders a menu node.
+// A null parent_id moves the node to the top level.
+type MoveMenuRequest struct {
+	ParentID  *string `json:"parent_id" validate:"omitempty,uuid"`
+	SortOrder int     `json:"sort_order"`
+}
+
+// MenuVisibilityRequest shows or hides a menu node.
+type MenuVisibilityRequest struct {
+	IsVisible *bool `json:"is_visible" validate:"required"`
+}
+
 func (h *MenuHandler) List(c *gin.Context) {
 	var items []entity.SubMenu
 	var total int64
-	params := utils.GetPaginationParams(c)
+	params, appErr := query.Parse(c, menuQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
 
-	h.db.Model(&entity.SubMenu{}).Count(&total)
+	if err := params.Count(h.db.Model(&entity.SubMenu{}), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch menus", nil)
+		return
+	}
 
-	if err := h.db.Order("\"order\" ASC").Offset(params.Offset()).Limit(params.Limit).Find(&items).Error; err != nil {
+	if err := params.Find(h.db, &items); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch menus", nil)
+		return
+	}
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
+
+// Tree returns the visible navigation tree for the caller.
+// Hidden nodes and nodes gated by a permission the caller lacks are dropped
+// together with their subtrees. Optional filter: ?menu_id=<uuid>
+func (h *MenuHandler) Tree(c *gin.Context) {
+	query := h.db.Where("is_visible = ?", true)
+	if menuID := c.Query("menu_id"); menuID != "" {
+		if _, err := uuid.Parse(menuID); err != nil {
+			respondError(c, apperror.BadRequest("Invalid menu_id"))
+			return
+		}
+		query = query.Where("menu_id = ?", menuID)
+	}
+
+	var items []entity.SubMenu
+	if err := query.Order(menuOrder).Find(&items).Error; err != nil {
 		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch menus", nil)
 		return
 	}
-	response.Paginated(c, http.StatusOK, items, total, params.Page, params.Limit)
+
+	visible := items[:0]
+	for _, item := range items {
+		if item.PermissionCode == nil || middleware.HasPermission(c, *item.PermissionCode) {
+			visible = append(visible, item)
+		}
+	}
+
+	response.Success(c, http.StatusOK, "Success", buildMenuTree(visible))
+}
+
+// Create adds a new menu node.
+func (h *MenuHandler) Create(c *gin.Context) {
+	var req CreateMenuRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	if req.ParentID != nil {
+		var parent entity.SubMenu
+		if err := h.db.First(&parent, "id = ?", *req.ParentID).Error; err != nil {
+			respondError(c, apperror.Validation("Validation failed", []validator.ValidationError{
+				{Field: "parent_id", Message: "Parent menu not found"},
+			}))
+			return
+		}
+		if req.MenuID == nil {
+			req.MenuID = parent.MenuID
+		}
+	}
+
+	userID := c.GetString("user_id")
+	item := entity.SubMenu{
+		MenuID:         req.MenuID,
+		ParentID:       req.ParentID,
+		Key:            req.Key,
+		Label:          req.Label,
+		Icon:           req.Icon,
+		RouterLink:     req.RouterLink,
+		Path:           req.Path,
+		IsVisible:      req.IsVisible == nil || *req.IsVisible,
+		SortOrder:      req.SortOrder,
+		PermissionCode: req.PermissionCode,
+	}
+	item.CreatedBy, item.UpdatedBy = &userID, &userID
+
+	// The unique index on key, not a prior lookup, settles concurrent creates.
+	if err := h.db.Create(&item).Error; err != nil {
+		if isDuplicateKey(h.db, err) {
+			respondError(c, apperror.Conflict("Menu key already exists"))
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to create menu", nil)
+		return
+	}
+	response.Success(c, http.StatusCreated, "Menu created", item)
+}
+
+// Move re-parents and re-orders a menu node, rejecting cycles. Under a new
+// parent the node and its subtree take the parent's menu_id.
+func (h *MenuHandler) Move(c *gin.Context) {
+	var req MoveMenuRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var item entity.SubMenu
+	if err := h.db.First(&item, "id = ?", c.Param("id")).Error; err != nil {
+		respondError(c, apperror.NotFound("Menu not found"))
+		return
+	}
+
+	updates := map[string]any{
+		"parent_id":  req.ParentID,
+		"sort_order": req.SortOrder,
+		"updated_by": c.GetString("user_id"),
+	}
+	subtree := []string{item.ID}
+	if req.ParentID != nil {
+		var all []entity.SubMenu
+		if err := h.db.Select("id", "parent_id", "menu_id").Find(&all).Error; err != nil {
+			response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch menus", nil)
+			return
+		}
+
+		parents := make(map[string]*string, len(all))
+		children := make(map[string][]string, len(all))
+		var parent *entity.SubMenu
+		for i, m := range all {
+			parents[m.ID] = m.ParentID
+			if m.ParentID != nil {
+				children[*m.ParentID] = append(children[*m.ParentID], m.ID)
+			}
+			if m.ID == *req.ParentID {
+				parent = &all[i]
+			}
+		}
+		if parent == nil {
+			respondError(c, apperror.Validation("Validation failed", []validator.ValidationError{
+				{Field: "parent_id", Message: "Parent menu not found"},
+			}))
+			return
+		}
+
+		// Walk up from the new parent; reaching the node itself means a cycle.
+		// The step bound guards against cycles already present in the data.
+		for cur, steps := req.ParentID, 0; cur != nil && steps <= len(parents); cur, steps = parents[*cur], steps+1 {
+			if *cur == item.ID {
+				respondError(c, apperror.Validation("Validation failed", []validator.ValidationError{
+					{Field: "parent_id", Message: "A menu cannot be moved under itself or its descendants"},
+				}))
+				return
+			}
+		}
+
+		// The size bound guards against cycles already present below the node.
+		for i := 0; i < len(subtree) && len(subtree) <= len(all); i++ {
+			subtree = append(subtree, children[subtree[i]]...)
+		}
+		updates["menu_id"] = parent.MenuID
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&item).Updates(updates).Error; err != nil {
+			return err
+		}
+		if menuID, ok := updates["menu_id"]; ok && len(subtree) > 1 {
+			return tx.Model(&entity.SubMenu{}).Where("id IN ?", subtree[1:]).Update("menu_id", menuID).Error
+		}
+		return nil
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to move menu", nil)
+		return
+	}
+
+	if err := h.db.First(&item, "id = ?", item.ID).Error; err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch menu", nil)
+		return
+	}
+	response.Success(c, http.StatusOK, "Menu moved", item)
+}
+
+// SetVisibility shows or hides a menu node (and therefore its subtree).
+func (h *MenuHandler) SetVisibility(c *gin.Context) {
+	var req MenuVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var item entity.SubMenu
+	if err := h.db.First(&item, "id = ?", c.Param("id")).Error; err != nil {
+		respondError(c, apperror.NotFound("Menu not found"))
+		return
+	}
+
+	userID := c.GetString("user_id")
+	err := h.db.Model(&item).Updates(map[string]any{
+		"is_visible": *req.IsVisible,
+		"updated_by": userID,
+	}).Error
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to update menu", nil)
+		return
+	}
+
+	if err := h.db.First(&item, "id = ?", item.ID).Error; err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch menu", nil)
+		return
+	}
+	response.Success(c, http.StatusOK, "Menu updated", item)
+}
+
+// buildMenuTree nests items by parent_id. Items whose parent is absent from
+// the slice (hidden, filtered or deleted) are dropped with their subtrees.
+func buildMenuTree(items []entity.SubMenu) []*MenuNode {
+	nodes := make(map[string]*MenuNode, len(items))
+	for _, item := range items {
+		nodes[item.ID] = &MenuNode{
+			ID:         item.ID,
+			MenuID:     item.MenuID,
+			Key:        item.Key,
+			Label:      item.Label,
+			Icon:       item.Icon,
+			RouterLink: item.RouterLink,
+			Path:       item.Path,
+			SortOrder:  item.SortOrder,
+			Children:   []*MenuNode{},
+		}
+	}
+
+	roots := []*MenuNode{}
+	for _, item := range items {
+		node := nodes[item.ID]
+		if item.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		if parent, ok := nodes[*item.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	sortMenuNodes(roots)
+	return roots
+}
+
+func sortMenuNodes(nodes []*MenuNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].SortOrder != nodes[j].SortOrder {
+			return nodes[i].SortOrder < nodes[j].SortOrder
+		}
+		if nodes[i].Label != nodes[j].Label {
+			return nodes[i].Label < nodes[j].Label
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+	for _, n := range nodes {
+		sortMenuNodes(n.Children)
+	}
+}
+
+// isDuplicateKey reports whether err violates a unique index.
+func isDuplicateKey(db *gorm.DB, err error) bool {
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+func respondError(c *gin.Context, appErr *apperror.AppError) {
+	response.Error(c, appErr.HTTPStatus, string(appErr.Code), appErr.Message, appErr.Details)
 }
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/system/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"gorm.io/gorm"
+)
+
+type PensionAgeHandler struct{ db *gorm.DB }
+
+var pensionAgeQuery = query.For(&entity.PensionAge{}, "reference_regulation", "description")
+
+func NewPensionAgeHandler(db *gorm.DB) *PensionAgeHandler { return &PensionAgeHandler{db: db} }
+
+// List returns pension age configurations; ?as_of= keeps the one in effect
+// on a date.
+func (h *PensionAgeHandler) List(c *gin.Context) {
+	var items []entity.PensionAge
+	var total int64
+	params, appErr := query.Parse(c, pensionAgeQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+	asOf, appErr := asOfScope(c, pensionAgeVersions)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	if err := params.Count(h.db.Model(&entity.PensionAge{}).Scopes(asOf), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch pension ages", nil)
+		return
+	}
+
+	if err := params.Find(h.db.Scopes(asOf), &items); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch pension ages", nil)
+		return
+	}
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/modules/system/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"gorm.io/gorm"
+)
+
+type PermissionHandler struct{ db *gorm.DB }
+
+var permissionQuery = query.For(&entity.Permission{}, "code", "description").OrderBy("code ASC")
+
+func NewPermissionHandler(db *gorm.DB) *PermissionHandler { return &PermissionHandler{db: db} }
+
+func (h *PermissionHandler) List(c *gin.Context) {
+	var items []entity.Permission
+	var total int64
+	params, appErr := query.Parse(c, permissionQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	if err := params.Count(h.db.Model(&entity.Permission{}), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch permissions", nil)
+		return
+	}
+
+	if err := params.Find(h.db, &items); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch permissions", nil)
+		return
+	}
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
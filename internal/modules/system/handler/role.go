@@ -5,25 +5,113 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/modules/system/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
 	"github.com/user/go-boilerplate/internal/shared/response"
-	"github.com/user/go-boilerplate/pkg/utils"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
 	"gorm.io/gorm"
 )
 
 type RoleHandler struct{ db *gorm.DB }
 
+var roleQuery = query.For(&entity.Role{}, "description").OrderBy("description ASC")
+
 func NewRoleHandler(db *gorm.DB) *RoleHandler { return &RoleHandler{db: db} }
 
+// RoleMFAPolicyRequest sets whether a role must use two-factor authentication.
+type RoleMFAPolicyRequest struct {
+	RequireMFA *bool `json:"require_mfa" validate:"required"`
+}
+
+// RoleImpersonationPolicyRequest sets whether a role may make changes while
+// impersonating another user.
+type RoleImpersonationPolicyRequest struct {
+	AllowWrite *bool `json:"allow_write" validate:"required"`
+}
+
 func (h *RoleHandler) List(c *gin.Context) {
 	var items []entity.Role
 	var total int64
-	params := utils.GetPaginationParams(c)
+	params, appErr := query.Parse(c, roleQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
 
-	h.db.Model(&entity.Role{}).Count(&total)
+	if err := params.Count(h.db.Model(&entity.Role{}), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch roles", nil)
+		return
+	}
 
-	if err := h.db.Offset(params.Offset()).Limit(params.Limit).Find(&items).Error; err != nil {
+	if err := params.Find(h.db, &items); err != nil {
 		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch roles", nil)
 		return
 	}
-	response.Paginated(c, http.StatusOK, items, total, params.Page, params.Limit)
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
+
+// SetMFAPolicy forces (or stops forcing) two-factor authentication for every
+// user holding the role. It takes effect at their next login.
+func (h *RoleHandler) SetMFAPolicy(c *gin.Context) {
+	var req RoleMFAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var item entity.Role
+	if err := h.db.First(&item, "id = ?", c.Param("id")).Error; err != nil {
+		respondError(c, apperror.NotFound("Role not found"))
+		return
+	}
+
+	userID := c.GetString("user_id")
+	err := h.db.Model(&item).Updates(map[string]any{
+		"require_mfa": *req.RequireMFA,
+		"updated_by":  userID,
+	}).Error
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to update role", nil)
+		return
+	}
+
+	h.db.First(&item, "id = ?", item.ID)
+	response.Success(c, http.StatusOK, "Role updated", item)
+}
+
+// SetImpersonationPolicy lets (or stops letting) holders of the role make
+// changes while impersonating. It applies to impersonations started afterwards.
+func (h *RoleHandler) SetImpersonationPolicy(c *gin.Context) {
+	var req RoleImpersonationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	var item entity.Role
+	if err := h.db.First(&item, "id = ?", c.Param("id")).Error; err != nil {
+		respondError(c, apperror.NotFound("Role not found"))
+		return
+	}
+
+	userID := c.GetString("user_id")
+	err := h.db.Model(&item).Updates(map[string]any{
+		"allow_impersonation_write": *req.AllowWrite,
+		"updated_by":                userID,
+	}).Error
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to update role", nil)
+		return
+	}
+
+	h.db.First(&item, "id = ?", item.ID)
+	response.Success(c, http.StatusOK, "Role updated", item)
 }
@@ -5,25 +5,34 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/modules/system/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
 	"github.com/user/go-boilerplate/internal/shared/response"
-	"github.com/user/go-boilerplate/pkg/utils"
 	"gorm.io/gorm"
 )
 
 type SubRoleHandler struct{ db *gorm.DB }
 
+var subRoleQuery = query.For(&entity.SubRole{}, "description")
+
 func NewSubRoleHandler(db *gorm.DB) *SubRoleHandler { return &SubRoleHandler{db: db} }
 
 func (h *SubRoleHandler) List(c *gin.Context) {
 	var items []entity.SubRole
 	var total int64
-	params := utils.GetPaginationParams(c)
+	params, appErr := query.Parse(c, subRoleQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
 
-	h.db.Model(&entity.SubRole{}).Count(&total)
+	if err := params.Count(h.db.Model(&entity.SubRole{}), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch sub-roles", nil)
+		return
+	}
 
-	if err := h.db.Offset(params.Offset()).Limit(params.Limit).Find(&items).Error; err != nil {
+	if err := params.Find(h.db, &items); err != nil {
 		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch sub-roles", nil)
 		return
 	}
-	response.Paginated(c, http.StatusOK, items, total, params.Page, params.Limit)
+	response.Paginated(c, http.StatusOK, items, total, params)
 }
@@ -2,28 +2,99 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/modules/system/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
 	"github.com/user/go-boilerplate/internal/shared/response"
-	"github.com/user/go-boilerplate/pkg/utils"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
 	"gorm.io/gorm"
 )
 
 type TransactionFeeHandler struct{ db *gorm.DB }
 
+var transactionFeeQuery = query.For(&entity.TransactionFee{}, "grouping_name")
+
+// CreateTransactionFeeRequest is the payload for a new transaction fee
+// version. Without an end date it stays valid until the next version of the
+// group starts.
+type CreateTransactionFeeRequest struct {
+	GroupingName     *string    `json:"grouping_name" validate:"omitempty,max=255"`
+	IsActive         *bool      `json:"is_active"`
+	IsDefault        bool       `json:"is_default"`
+	EffectiveAtStart *time.Time `json:"effective_at_start" validate:"required"`
+	EffectiveAtEnd   *time.Time `json:"effective_at_end"`
+	entity.TransactionFeeRates
+}
+
 func NewTransactionFeeHandler(db *gorm.DB) *TransactionFeeHandler { return &TransactionFeeHandler{db: db} }
 
+// List returns transaction fee versions; ?as_of= keeps the ones valid on a
+// date.
 func (h *TransactionFeeHandler) List(c *gin.Context) {
 	var items []entity.TransactionFee
 	var total int64
-	params := utils.GetPaginationParams(c)
+	params, appErr := query.Parse(c, transactionFeeQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+	asOf, appErr := asOfScope(c, transactionFeeVersions)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
 
-	h.db.Model(&entity.TransactionFee{}).Count(&total)
+	if err := params.Count(h.db.Model(&entity.TransactionFee{}).Scopes(asOf), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch transaction fees", nil)
+		return
+	}
 
-	if err := h.db.Offset(params.Offset()).Limit(params.Limit).Find(&items).Error; err != nil {
+	if err := params.Find(h.db.Scopes(asOf), &items); err != nil {
 		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch transaction fees", nil)
 		return
 	}
-	response.Paginated(c, http.StatusOK, items, total, params.Page, params.Limit)
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
+
+// Create adds a new version of a fee group. The group's open version is
+// closed at the new start date; windows overlapping another version of the
+// group are rejected.
+func (h *TransactionFeeHandler) Create(c *gin.Context) {
+	var req CreateTransactionFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperror.BadRequest("Invalid request body"))
+		return
+	}
+	if appErr := validator.Validate(&req); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+	if appErr := checkWindow("effective_at_end", req.EffectiveAtStart, req.EffectiveAtEnd); appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	item := entity.TransactionFee{
+		GroupingName:        req.GroupingName,
+		IsDefault:           req.IsDefault,
+		IsActive:            req.IsActive == nil || *req.IsActive,
+		TransactionFeeRates: req.TransactionFeeRates,
+		EffectiveAtStart:    req.EffectiveAtStart,
+		EffectiveAtEnd:      req.EffectiveAtEnd,
+	}
+	item.CreatedBy, item.UpdatedBy = &userID, &userID
+
+	if err := insertVersion(h.db, transactionFeeVersions, &item); err != nil {
+		if appErr := versionError(err); appErr != nil {
+			respondError(c, appErr)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to create transaction fee", nil)
+		return
+	}
+	response.Success(c, http.StatusCreated, "Transaction fee created", item)
 }
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/shared/effective"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// Versions of the effective-dated system tables.
+var (
+	baseFeeVersions        = effective.Window("effective_start_date", "effective_end_date", "group_name")
+	transactionFeeVersions = effective.Window("effective_at_start", "effective_at_end", "grouping_name")
+	pensionAgeVersions     = effective.Point("effective_date")
+)
+
+// asOfScope reads ?as_of= into a scope keeping the versions valid on that
+// date; without it every version is listed.
+func asOfScope(c *gin.Context, v *effective.Versioning) (func(*gorm.DB) *gorm.DB, *apperror.AppError) {
+	asOf, appErr := effective.ParseAsOf(c)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if asOf == nil {
+		return func(db *gorm.DB) *gorm.DB { return db }, nil
+	}
+	return v.AsOf(*asOf), nil
+}
+
+// insertVersion closes the open version of the model's key and inserts the
+// model as the next one, in one transaction.
+func insertVersion(db *gorm.DB, v *effective.Versioning, model any) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := v.Close(tx, model); err != nil {
+			return err
+		}
+		if err := v.Check(tx, model, ""); err != nil {
+			return err
+		}
+		return tx.Create(model).Error
+	})
+}
+
+// versionError maps the errors of insertVersion that the caller can fix.
+func versionError(err error) *apperror.AppError {
+	if errors.Is(err, effective.ErrOverlap) {
+		return apperror.Conflict("The validity window overlaps another version")
+	}
+	return nil
+}
+
+// checkWindow rejects a window whose end is not after its start.
+func checkWindow(field string, start, end *time.Time) *apperror.AppError {
+	if end != nil && !end.After(*start) {
+		return apperror.Validation("Validation failed", []validator.ValidationError{
+			{Field: field, Message: field + " must be after the start date"},
+		})
+	}
+	return nil
+}
@@ -0,0 +1,2 @@
+-- Drop sys_permissions table
+DROP TABLE IF EXISTS sys_permissions;
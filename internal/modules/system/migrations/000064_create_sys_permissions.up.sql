@@ -0,0 +1,22 @@
+-- Create sys_permissions table
+-- Catalog of permission codes checked by the API (synced from code by the seeder)
+CREATE TABLE IF NOT EXISTS sys_permissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    
+    -- Main data
+    code VARCHAR(100) NOT NULL,     -- Permission code in <module>.<resource>.<action> form (e.g., system.settings.read)
+    module VARCHAR(50) NOT NULL,    -- Owning module (e.g., system, master)
+    description VARCHAR(255),       -- Human readable description of what the permission allows
+    
+    -- Audit fields
+    created_by UUID,    -- User who created this record
+    updated_by UUID,    -- User who last updated this record
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, -- Record creation timestamp
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, -- Record update timestamp
+    deleted_at TIMESTAMP WITH TIME ZONE -- Soft deletion timestamp
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sys_permissions_code ON sys_permissions(code);
+CREATE INDEX IF NOT EXISTS idx_sys_permissions_module ON sys_permissions(module) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_sys_permissions_deleted_at ON sys_permissions(deleted_at);
@@ -0,0 +1,2 @@
+-- Drop sys_role_permissions table
+DROP TABLE IF EXISTS sys_role_permissions;
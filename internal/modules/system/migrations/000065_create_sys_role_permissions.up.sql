@@ -0,0 +1,21 @@
+-- Create sys_role_permissions table
+-- Grants permissions to a role, optionally narrowed to one of its sub-roles
+CREATE TABLE IF NOT EXISTS sys_role_permissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Primary key using UUID
+    role_id UUID NOT NULL REFERENCES sys_roles(id) ON DELETE CASCADE,             -- Role receiving the grant
+    sub_role_id UUID REFERENCES sys_sub_roles(id) ON DELETE CASCADE,              -- Sub-role receiving the grant (NULL = every sub-role of the role)
+    permission_id UUID NOT NULL REFERENCES sys_permissions(id) ON DELETE CASCADE, -- Granted permission
+    
+    -- Audit fields
+    created_by UUID,    -- User who created this record
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP -- Record creation timestamp
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sys_role_permissions_unique ON sys_role_permissions(
+    role_id,
+    COALESCE(sub_role_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    permission_id
+);
+CREATE INDEX IF NOT EXISTS idx_sys_role_permissions_sub_role_id ON sys_role_permissions(sub_role_id);
+CREATE INDEX IF NOT EXISTS idx_sys_role_permissions_permission_id ON sys_role_permissions(permission_id);
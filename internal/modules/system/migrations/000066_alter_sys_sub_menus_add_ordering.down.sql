@@ -0,0 +1,5 @@
+-- Revert sys_sub_menus ordering columns
+DROP INDEX IF EXISTS idx_sys_sub_menus_sort_order;
+ALTER TABLE sys_sub_menus
+    DROP COLUMN IF EXISTS permission_code,
+    DROP COLUMN IF EXISTS sort_order;
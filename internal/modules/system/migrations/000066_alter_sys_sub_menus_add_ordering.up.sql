@@ -0,0 +1,8 @@
+-- Alter sys_sub_menus table
+-- Adds explicit sibling ordering and an optional permission gate for the navigation tree
+ALTER TABLE sys_sub_menus
+    ADD COLUMN IF NOT EXISTS sort_order INTEGER NOT NULL DEFAULT 0, -- Position among siblings (ascending)
+    ADD COLUMN IF NOT EXISTS permission_code VARCHAR(100);         -- Permission required to see the item (NULL = visible to every role)
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_sys_sub_menus_sort_order ON sys_sub_menus(parent_id, sort_order) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Revert sys_roles two-factor policy
+ALTER TABLE sys_roles
+    DROP COLUMN IF EXISTS require_mfa;
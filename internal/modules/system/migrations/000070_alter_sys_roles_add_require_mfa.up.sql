@@ -0,0 +1,4 @@
+-- Alter sys_roles table
+-- Adds the per-role two-factor policy (e.g. mandatory for approver roles)
+ALTER TABLE sys_roles
+    ADD COLUMN IF NOT EXISTS require_mfa BOOLEAN NOT NULL DEFAULT false; -- Users with this role must enroll TOTP before they can sign in
@@ -0,0 +1,3 @@
+-- Revert sys_roles impersonation policy
+ALTER TABLE sys_roles
+    DROP COLUMN IF EXISTS allow_impersonation_write;
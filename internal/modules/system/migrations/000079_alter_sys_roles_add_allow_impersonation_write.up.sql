@@ -0,0 +1,4 @@
+-- Alter sys_roles table
+-- Adds the per-role impersonation policy (e.g. support leads who fix data as the user)
+ALTER TABLE sys_roles
+    ADD COLUMN IF NOT EXISTS allow_impersonation_write BOOLEAN NOT NULL DEFAULT false; -- Holders may make changes while impersonating; read-only otherwise
@@ -3,7 +3,9 @@ package system
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/config"
+	"github.com/user/go-boilerplate/internal/middleware"
 	"github.com/user/go-boilerplate/internal/modules/system/handler"
+	"github.com/user/go-boilerplate/internal/shared/permission"
 	"gorm.io/gorm"
 )
 
@@ -12,9 +14,11 @@ type Module struct {
 	settingsHandler       *handler.SettingsHandler
 	roleHandler           *handler.RoleHandler
 	subRoleHandler        *handler.SubRoleHandler
+	permissionHandler     *handler.PermissionHandler
 	bankFeeHandler        *handler.BankFeeHandler
 	baseFeeHandler        *handler.BaseFeeHandler
 	transactionFeeHandler *handler.TransactionFeeHandler
+	pensionAgeHandler     *handler.PensionAgeHandler
 	menuHandler           *handler.MenuHandler
 }
 
@@ -24,9 +28,11 @@ func New(db *gorm.DB, cfg *config.Config) *Module {
 		settingsHandler:       handler.NewSettingsHandler(db),
 		roleHandler:           handler.NewRoleHandler(db),
 		subRoleHandler:        handler.NewSubRoleHandler(db),
+		permissionHandler:     handler.NewPermissionHandler(db),
 		bankFeeHandler:        handler.NewBankFeeHandler(db),
 		baseFeeHandler:        handler.NewBaseFeeHandler(db),
 		transactionFeeHandler: handler.NewTransactionFeeHandler(db),
+		pensionAgeHandler:     handler.NewPensionAgeHandler(db),
 		menuHandler:           handler.NewMenuHandler(db),
 	}
 }
@@ -34,11 +40,21 @@ func New(db *gorm.DB, cfg *config.Config) *Module {
 // RegisterRoutes registers system routes.
 func (m *Module) RegisterRoutes(api *gin.RouterGroup) {
 	system := api.Group("/system")
-	system.GET("/settings", m.settingsHandler.Get)
-	system.GET("/roles", m.roleHandler.List)
-	system.GET("/sub-roles", m.subRoleHandler.List)
-	system.GET("/bank-fees", m.bankFeeHandler.List)
-	system.GET("/base-fees", m.baseFeeHandler.List)
-	system.GET("/transaction-fees", m.transactionFeeHandler.List)
-	system.GET("/menus", m.menuHandler.List)
+	system.GET("/settings", middleware.RequirePermission(permission.SystemSettingsRead), m.settingsHandler.Get)
+	system.GET("/roles", middleware.RequirePermission(permission.SystemRolesRead), m.roleHandler.List)
+	system.PATCH("/roles/:id/mfa-policy", middleware.RequirePermission(permission.SystemRolesManage), m.roleHandler.SetMFAPolicy)
+	system.PATCH("/roles/:id/impersonation-policy", middleware.RequirePermission(permission.SystemRolesManage), m.roleHandler.SetImpersonationPolicy)
+	system.GET("/sub-roles", middleware.RequirePermission(permission.SystemRolesRead), m.subRoleHandler.List)
+	system.GET("/permissions", middleware.RequirePermission(permission.SystemRolesRead), m.permissionHandler.List)
+	system.GET("/bank-fees", middleware.RequirePermission(permission.SystemFeesRead), m.bankFeeHandler.List)
+	system.GET("/base-fees", middleware.RequirePermission(permission.SystemFeesRead), m.baseFeeHandler.List)
+	system.POST("/base-fees", middleware.RequirePermission(permission.SystemFeesManage), m.baseFeeHandler.Create)
+	system.GET("/transaction-fees", middleware.RequirePermission(permission.SystemFeesRead), m.transactionFeeHandler.List)
+	system.POST("/transaction-fees", middleware.RequirePermission(permission.SystemFeesManage), m.transactionFeeHandler.Create)
+	system.GET("/pension-ages", middleware.RequirePermission(permission.SystemFeesRead), m.pensionAgeHandler.List)
+	system.GET("/menus", middleware.RequirePermission(permission.SystemMenusRead), m.menuHandler.List)
+	system.GET("/menus/tree", m.menuHandler.Tree)
+	system.POST("/menus", middleware.RequirePermission(permission.SystemMenusManage), m.menuHandler.Create)
+	system.PATCH("/menus/:id/move", middleware.RequirePermission(permission.SystemMenusManage), m.menuHandler.Move)
+	system.PATCH("/menus/:id/visibility", middleware.RequirePermission(permission.SystemMenusManage), m.menuHandler.SetVisibility)
 }
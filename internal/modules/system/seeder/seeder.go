@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/user/go-boilerplate/internal/shared/permission"
 	"github.com/user/go-boilerplate/pkg/logger"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -56,6 +57,45 @@ func (s *Seeder) Seed() error {
 		logger.Log.Debug("Seeded", zap.String("table", tableName))
 	}
 
+	if err := s.seedPermissions(); err != nil {
+		return err
+	}
+
 	logger.Log.Info("System data seeded")
 	return nil
 }
+
+// adminRoleID is the "Admin" role from seeders/sys_roles.sql.
+const adminRoleID = "16087e36-6a42-54d4-845d-048fc1e73ada"
+
+// seedPermissions syncs the permission catalog into sys_permissions and
+// grants every permission to the Admin role.
+func (s *Seeder) seedPermissions() error {
+	for _, def := range permission.Catalog {
+		err := s.db.Exec(`INSERT INTO sys_permissions (code, module, description) VALUES (?, ?, ?)
+			ON CONFLICT (code) DO UPDATE SET module = EXCLUDED.module, description = EXCLUDED.description,
+			updated_at = CURRENT_TIMESTAMP, deleted_at = NULL`, def.Code, def.Module, def.Description).Error
+		if err != nil {
+			logger.Log.Error("Failed to seed permission", zap.String("code", def.Code), zap.Error(err))
+			return err
+		}
+	}
+
+	var roles int64
+	s.db.Table("sys_roles").Where("id = ?", adminRoleID).Count(&roles)
+	if roles == 0 {
+		logger.Log.Warn("Admin role not found, skipping permission grants")
+		return nil
+	}
+
+	err := s.db.Exec(`INSERT INTO sys_role_permissions (role_id, permission_id)
+		SELECT ?, id FROM sys_permissions WHERE deleted_at IS NULL
+		ON CONFLICT DO NOTHING`, adminRoleID).Error
+	if err != nil {
+		logger.Log.Error("Failed to grant admin permissions", zap.Error(err))
+		return err
+	}
+
+	logger.Log.Debug("Seeded", zap.String("table", "sys_permissions"))
+	return nil
+}
@@ -0,0 +1,11 @@
+package transaction
+
+import "github.com/user/go-boilerplate/internal/modules/transaction/entity"
+
+// Entities lists the models of the transaction tables, checked against the
+// database by cli schema:verify.
+var Entities = []any{
+	&entity.Batching{},
+	&entity.GiroReconciliation{},
+	&entity.GiroReconciliationDetail{},
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+// Batching is a batch of membership and financial updates.
+type Batching struct {
+	sharedentity.Base
+	MembershipBatchingID   *string    `json:"membership_batching_id,omitempty" gorm:"type:uuid"`
+	BatchingPurposeID      *string    `json:"batching_purpose_id,omitempty" gorm:"type:uuid"`
+	BatchingDetailID       *string    `json:"batching_detail_id,omitempty" gorm:"type:uuid"`
+	BranchID               *string    `json:"branch_id,omitempty" gorm:"type:uuid"`
+	BatchingCode           string     `json:"batching_code"`
+	Description            *string    `json:"description,omitempty"`
+	IsActive               bool       `json:"is_active"`
+	InvestproStoredStatus  string     `json:"investpro_stored_status"`
+	InvestproStoredMessage *string    `json:"investpro_stored_message,omitempty"`
+	NABDate                *time.Time `json:"nab_date,omitempty" gorm:"column:nab_date;type:date"`
+	CashDate               *time.Time `json:"cash_date,omitempty" gorm:"type:date"`
+	AUMDate                *time.Time `json:"aum_date,omitempty" gorm:"column:aum_date;type:date"`
+	TransactionDate        *time.Time `json:"transaction_date,omitempty" gorm:"type:date"`
+	ClosingAt              *time.Time `json:"closing_at,omitempty"`
+	ClosingBy              *string    `json:"closing_by,omitempty" gorm:"type:uuid"`
+}
+
+// TableName returns the database table name.
+func (Batching) TableName() string {
+	return "app_batchings"
+}
+
+// BranchColumn opts the table into branch-scoped data access.
+func (Batching) BranchColumn() string {
+	return "branch_id"
+}
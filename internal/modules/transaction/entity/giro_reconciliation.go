@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	sharedentity "github.com/user/go-boilerplate/internal/shared/entity"
+)
+
+// GiroReconciliation reconciles a giro document with a system batch.
+type GiroReconciliation struct {
+	sharedentity.Base
+	BatchID         *string   `json:"batch_id,omitempty" gorm:"type:uuid"`
+	LegacyBatchID   *int64    `json:"legacy_batch_id,omitempty"`
+	BranchID        *string   `json:"branch_id,omitempty" gorm:"type:uuid"`
+	GiroNumber      string    `json:"giro_number"`
+	GiroDescription *string   `json:"giro_description,omitempty"`
+	GiroDate        time.Time `json:"giro_date" gorm:"type:date"`
+	PaymentType     int       `json:"payment_type"`
+	Status          int       `json:"status"`
+}
+
+// TableName returns the database table name.
+func (GiroReconciliation) TableName() string {
+	return "app_giro_reconciliations"
+}
+
+// BranchColumn opts the table into branch-scoped data access.
+func (GiroReconciliation) BranchColumn() string {
+	return "branch_id"
+}
+
+// GiroReconciliationDetail is one transaction line of a giro reconciliation.
+type GiroReconciliationDetail struct {
+	sharedentity.Base
+	GiroID            *string    `json:"giro_id,omitempty" gorm:"type:uuid"`
+	BatchID           *string    `json:"batch_id,omitempty" gorm:"type:uuid"`
+	BranchID          *string    `json:"branch_id,omitempty" gorm:"type:uuid"`
+	TransactionDate   time.Time  `json:"transaction_date"`
+	UploadDate        time.Time  `json:"upload_date"`
+	GiroNumber        *string    `json:"giro_number,omitempty"`
+	ApacNo            *string    `json:"apac_no,omitempty"`
+	ReferenceNumber   *string    `json:"reference_number,omitempty"`
+	Amount            float64    `json:"amount"`
+	AmountTransfer    float64    `json:"amount_transfer"`
+	LumpsumAmount     float64    `json:"lumpsum_amount"`
+	AnnuityAmount     float64    `json:"annuity_amount"`
+	BankFee           float64    `json:"bank_fee"`
+	DPLKIncome        float64    `json:"dplk_income" gorm:"column:dplk_income"`
+	BankName          *string    `json:"bank_name,omitempty"`
+	BankAccountNumber *string    `json:"bank_account_number,omitempty"`
+	BankAccountName   *string    `json:"bank_account_name,omitempty"`
+	Status            int        `json:"status"`
+	FeeBurdenType     *int       `json:"fee_burden_type,omitempty"`
+	RealizationDate   *time.Time `json:"realization_date,omitempty"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	Notes             *string    `json:"notes,omitempty"`
+}
+
+// TableName returns the database table name.
+func (GiroReconciliationDetail) TableName() string {
+	return "app_giro_reconciliation_details"
+}
+
+// BranchColumn opts the table into branch-scoped data access.
+func (GiroReconciliationDetail) BranchColumn() string {
+	return "branch_id"
+}
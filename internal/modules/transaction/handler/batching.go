@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/go-boilerplate/internal/modules/transaction/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+// BatchingHandler serves batches. Rows are limited to the caller's data scope.
+type BatchingHandler struct{ db *gorm.DB }
+
+var batchingQuery = query.For(&entity.Batching{}, "batching_code", "description").OrderBy("created_at DESC")
+
+// NewBatchingHandler creates a new batching handler.
+func NewBatchingHandler(db *gorm.DB) *BatchingHandler { return &BatchingHandler{db: db} }
+
+// List handles GET /api/transactions/batchings requests.
+func (h *BatchingHandler) List(c *gin.Context) {
+	var items []entity.Batching
+	var total int64
+	params, appErr := query.Parse(c, batchingQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+	db := h.db.WithContext(c.Request.Context())
+
+	if err := params.Count(db.Model(&entity.Batching{}), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch batches", nil)
+		return
+	}
+
+	if err := params.Find(db, &items); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch batches", nil)
+		return
+	}
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
+
+// Get handles GET /api/transactions/batchings/:id requests.
+// Batches of other branches are reported as not found.
+func (h *BatchingHandler) Get(c *gin.Context) {
+	var item entity.Batching
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		response.Error(c, http.StatusNotFound, "NOT_FOUND", "Batch not found", nil)
+		return
+	}
+	err := h.db.WithContext(c.Request.Context()).Where("id = ?", c.Param("id")).Take(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		response.Error(c, http.StatusNotFound, "NOT_FOUND", "Batch not found", nil)
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch batch", nil)
+		return
+	}
+	response.Success(c, http.StatusOK, "Success", item)
+}
+
+func respondError(c *gin.Context, appErr *apperror.AppError) {
+	response.Error(c, appErr.HTTPStatus, string(appErr.Code), appErr.Message, appErr.Details)
+}
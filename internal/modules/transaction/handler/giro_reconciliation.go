@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/go-boilerplate/internal/modules/transaction/entity"
+	"github.com/user/go-boilerplate/internal/shared/query"
+	"github.com/user/go-boilerplate/internal/shared/response"
+	"gorm.io/gorm"
+)
+
+// GiroReconciliationHandler serves giro reconciliations and their details.
+// Rows are limited to the caller's data scope.
+type GiroReconciliationHandler struct{ db *gorm.DB }
+
+var giroReconciliationQuery = query.For(&entity.GiroReconciliation{}, "giro_number", "giro_description").OrderBy("giro_date DESC, id")
+var giroReconciliationDetailQuery = query.For(&entity.GiroReconciliationDetail{}, "giro_number", "reference_number", "bank_account_name", "notes").OrderBy("transaction_date, id")
+
+// NewGiroReconciliationHandler creates a new giro reconciliation handler.
+func NewGiroReconciliationHandler(db *gorm.DB) *GiroReconciliationHandler {
+	return &GiroReconciliationHandler{db: db}
+}
+
+// List handles GET /api/transactions/giro-reconciliations requests.
+func (h *GiroReconciliationHandler) List(c *gin.Context) {
+	var items []entity.GiroReconciliation
+	var total int64
+	params, appErr := query.Parse(c, giroReconciliationQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+	db := h.db.WithContext(c.Request.Context())
+
+	if err := params.Count(db.Model(&entity.GiroReconciliation{}), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch giro reconciliations", nil)
+		return
+	}
+
+	if err := params.Find(db, &items); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch giro reconciliations", nil)
+		return
+	}
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
+
+// Get handles GET /api/transactions/giro-reconciliations/:id requests.
+func (h *GiroReconciliationHandler) Get(c *gin.Context) {
+	var item entity.GiroReconciliation
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		response.Error(c, http.StatusNotFound, "NOT_FOUND", "Giro reconciliation not found", nil)
+		return
+	}
+	err := h.db.WithContext(c.Request.Context()).Where("id = ?", c.Param("id")).Take(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		response.Error(c, http.StatusNotFound, "NOT_FOUND", "Giro reconciliation not found", nil)
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch giro reconciliation", nil)
+		return
+	}
+	response.Success(c, http.StatusOK, "Success", item)
+}
+
+// ListDetails handles GET /api/transactions/giro-reconciliations/:id/details requests.
+func (h *GiroReconciliationHandler) ListDetails(c *gin.Context) {
+	var items []entity.GiroReconciliationDetail
+	var total int64
+	params, appErr := query.Parse(c, giroReconciliationDetailQuery)
+	if appErr != nil {
+		respondError(c, appErr)
+		return
+	}
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		response.Error(c, http.StatusNotFound, "NOT_FOUND", "Giro reconciliation not found", nil)
+		return
+	}
+	db := h.db.WithContext(c.Request.Context())
+
+	if err := params.Count(db.Model(&entity.GiroReconciliationDetail{}).Where("giro_id = ?", c.Param("id")), &total); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch giro reconciliation details", nil)
+		return
+	}
+
+	if err := params.Find(db.Where("giro_id = ?", c.Param("id")), &items); err != nil {
+		response.Error(c, http.StatusInternalServerError, "DB_ERROR", "Failed to fetch giro reconciliation details", nil)
+		return
+	}
+	response.Paginated(c, http.StatusOK, items, total, params)
+}
@@ -0,0 +1,9 @@
+-- Revert transaction table branch ownership
+ALTER TABLE app_giro_reconciliation_details
+    DROP COLUMN IF EXISTS branch_id;
+
+ALTER TABLE app_giro_reconciliations
+    DROP COLUMN IF EXISTS branch_id;
+
+ALTER TABLE app_batchings
+    DROP COLUMN IF EXISTS branch_id;
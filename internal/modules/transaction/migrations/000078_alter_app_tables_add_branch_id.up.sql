@@ -0,0 +1,16 @@
+-- Alter transaction tables
+-- Adds the owning branch used for branch-scoped data access (see internal/shared/datascope)
+-- Existing rows keep a NULL branch and are only visible to head office
+ALTER TABLE app_batchings
+    ADD COLUMN IF NOT EXISTS branch_id UUID REFERENCES mst_branches(id); -- Branch that owns the batch
+
+ALTER TABLE app_giro_reconciliations
+    ADD COLUMN IF NOT EXISTS branch_id UUID REFERENCES mst_branches(id); -- Branch that owns the reconciliation
+
+ALTER TABLE app_giro_reconciliation_details
+    ADD COLUMN IF NOT EXISTS branch_id UUID REFERENCES mst_branches(id); -- Copied from the parent reconciliation
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_app_batchings_branch_id ON app_batchings(branch_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_app_giro_reconciliations_branch_id ON app_giro_reconciliations(branch_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_app_giro_reconciliation_details_branch_id ON app_giro_reconciliation_details(branch_id) WHERE deleted_at IS NULL;
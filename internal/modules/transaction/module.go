@@ -3,24 +3,34 @@ package transaction
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/user/go-boilerplate/internal/config"
+	"github.com/user/go-boilerplate/internal/middleware"
+	"github.com/user/go-boilerplate/internal/modules/transaction/handler"
+	"github.com/user/go-boilerplate/internal/shared/permission"
 	"gorm.io/gorm"
 )
 
 // Module represents the transaction module.
 type Module struct {
-	db *gorm.DB
+	batchingHandler *handler.BatchingHandler
+	giroHandler     *handler.GiroReconciliationHandler
 }
 
 // New creates a new transaction module.
 func New(db *gorm.DB, cfg *config.Config) *Module {
 	return &Module{
-		db: db,
+		batchingHandler: handler.NewBatchingHandler(db),
+		giroHandler:     handler.NewGiroReconciliationHandler(db),
 	}
 }
 
 // RegisterRoutes registers transaction routes.
+// Every table served here is branch-scoped (see internal/shared/datascope).
 func (m *Module) RegisterRoutes(api *gin.RouterGroup) {
-	// TODO: Implement transaction handlers and routes
-	// transaction := api.Group("/transactions")
-	// transaction.GET("/", m.handler.List)
+	transactions := api.Group("/transactions")
+	transactions.Use(middleware.RequirePermission(permission.TransactionRead))
+	transactions.GET("/batchings", m.batchingHandler.List)
+	transactions.GET("/batchings/:id", m.batchingHandler.Get)
+	transactions.GET("/giro-reconciliations", m.giroHandler.List)
+	transactions.GET("/giro-reconciliations/:id", m.giroHandler.Get)
+	transactions.GET("/giro-reconciliations/:id/details", m.giroHandler.ListDetails)
 }
@@ -0,0 +1,82 @@
+// Package datascope restricts rows to the branches the caller may see.
+//
+// A Scope is resolved once per request (see middleware.DataScope) and
+// travels in the request context. Entities opt in by implementing
+// BranchScoped; the GORM Plugin then filters every query, update and
+// delete on their tables and checks the branch of inserted rows.
+//
+// LEVELS:
+// - Head office: every branch (All)
+// - Area: every branch in its area (data.scope.area permission)
+// - Main branch: its own branch and the branches below it
+// - Branch: its own branch only
+//
+// Context-free queries on scoped tables fail with ErrNoScope instead of
+// silently returning every branch. Background jobs use Unrestricted.
+//
+// USAGE:
+//
+//	db.Use(datascope.Plugin{})
+//	ctx = datascope.WithScope(ctx, &datascope.Scope{BranchIDs: ids, HomeBranchID: id})
+//	db.WithContext(ctx).Find(&batchings) // WHERE app_batchings.branch_id IN (...)
+package datascope
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNoScope is returned for queries on scoped tables without a Scope in
+	// the context.
+	ErrNoScope = errors.New("datascope: no data scope in context")
+	// ErrOutOfScope is returned when a row is written to a branch outside
+	// the caller's scope.
+	ErrOutOfScope = errors.New("datascope: branch is outside the caller's data scope")
+)
+
+type contextKey struct{}
+
+// Scope is the set of branches a caller may read and write.
+type Scope struct {
+	All          bool     // Head office or an override: no filtering
+	BranchIDs    []string // Visible branches when All is false
+	HomeBranchID string   // Stamped on inserted rows without a branch
+	Override     bool     // All was granted through the head-office override
+}
+
+// BranchScoped is implemented by entities whose table is filtered by branch.
+type BranchScoped interface {
+	// BranchColumn returns the column holding the owning branch ID.
+	BranchColumn() string
+}
+
+// WithScope returns a context carrying the scope.
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, contextKey{}, scope)
+}
+
+// FromContext returns the scope carried by the context, if any.
+func FromContext(ctx context.Context) (*Scope, bool) {
+	scope, ok := ctx.Value(contextKey{}).(*Scope)
+	return scope, ok && scope != nil
+}
+
+// Unrestricted returns a context that sees every branch, for system work
+// such as seeders and scheduled jobs.
+func Unrestricted(ctx context.Context) context.Context {
+	return WithScope(ctx, &Scope{All: true})
+}
+
+// Allows reports whether the scope covers the branch.
+func (s *Scope) Allows(branchID string) bool {
+	if s.All {
+		return true
+	}
+	for _, id := range s.BranchIDs {
+		if id == branchID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package datascope
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Plugin registers the branch filter on a *gorm.DB.
+type Plugin struct{}
+
+// Name implements gorm.Plugin.
+func (Plugin) Name() string {
+	return "datascope"
+}
+
+// Initialize implements gorm.Plugin.
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("datascope:query", filter); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("datascope:row", filter); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("datascope:update", filterUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("datascope:delete", filter); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("datascope:create", stampCreate)
+}
+
+// branchField returns the branch column of an opted-in model, or nil.
+func branchField(stmt *gorm.Statement) *schema.Field {
+	if stmt.Schema == nil {
+		return nil
+	}
+	scoped, ok := reflect.New(stmt.Schema.ModelType).Interface().(BranchScoped)
+	if !ok {
+		return nil
+	}
+	return stmt.Schema.LookUpField(scoped.BranchColumn())
+}
+
+func filter(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	field := branchField(db.Statement)
+	if field == nil {
+		return
+	}
+
+	scope, ok := FromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(ErrNoScope)
+		return
+	}
+	if scope.All {
+		return
+	}
+
+	column := clause.Column{Table: db.Statement.Table, Name: field.DBName}
+	if len(scope.BranchIDs) == 0 {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "1 = 0"}}})
+		return
+	}
+	values := make([]interface{}, len(scope.BranchIDs))
+	for i, id := range scope.BranchIDs {
+		values[i] = id
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+}
+
+// filterUpdate restricts the rows like filter and rejects moving a row to
+// a branch outside the scope.
+func filterUpdate(db *gorm.DB) {
+	filter(db)
+	if db.Error != nil {
+		return
+	}
+	field := branchField(db.Statement)
+	if field == nil {
+		return
+	}
+	scope, _ := FromContext(db.Statement.Context)
+
+	var target string
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]interface{}:
+		if value, ok := dest[field.DBName]; ok {
+			target = branchValue(value)
+		} else if value, ok := dest[field.Name]; ok {
+			target = branchValue(value)
+		}
+	default:
+		value := reflect.Indirect(reflect.ValueOf(dest))
+		if value.Kind() == reflect.Struct && value.Type() == db.Statement.Schema.ModelType {
+			fieldValue, zero := field.ValueOf(db.Statement.Context, value)
+			if !zero {
+				target = branchValue(fieldValue)
+			}
+		}
+	}
+
+	if target != "" && !scope.Allows(target) {
+		_ = db.AddError(ErrOutOfScope)
+	}
+}
+
+// stampCreate fills in the caller's home branch on rows without one and
+// rejects rows for branches outside the scope.
+func stampCreate(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	field := branchField(db.Statement)
+	if field == nil {
+		return
+	}
+
+	scope, ok := FromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(ErrNoScope)
+		return
+	}
+
+	stamp := func(row reflect.Value) {
+		row = reflect.Indirect(row)
+		value, zero := field.ValueOf(db.Statement.Context, row)
+		branchID := branchValue(value)
+		if zero || branchID == "" {
+			if scope.HomeBranchID == "" {
+				if !scope.All {
+					_ = db.AddError(ErrOutOfScope)
+				}
+				return
+			}
+			home := scope.HomeBranchID
+			var err error
+			if field.FieldType.Kind() == reflect.Ptr {
+				err = field.Set(db.Statement.Context, row, &home)
+			} else {
+				err = field.Set(db.Statement.Context, row, home)
+			}
+			if err != nil {
+				_ = db.AddError(err)
+			}
+			return
+		}
+		if !scope.Allows(branchID) {
+			_ = db.AddError(ErrOutOfScope)
+		}
+	}
+
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			stamp(db.Statement.ReflectValue.Index(i))
+		}
+	case reflect.Struct:
+		stamp(db.Statement.ReflectValue)
+	}
+}
+
+func branchValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case *string:
+		if v != nil {
+			return *v
+		}
+	}
+	return ""
+}
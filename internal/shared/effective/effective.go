@@ -0,0 +1,249 @@
+// Package effective serves tables whose rows are versions of a record, each
+// valid from an effective date.
+//
+// Two layouts are supported:
+//   - Point: only a start column; a version applies until the next version of
+//     the same natural key starts (mst_tax_brackets.effective_date)
+//   - Window: start and end columns; a version applies from its start until
+//     its end, or indefinitely when the end is NULL (sys_base_fees)
+//
+// A version of a point table can be several rows (e.g. all the brackets of
+// one TER category dated the same day); as of a date, every row dated with
+// the latest effective date on or before it is returned. Rows names the
+// columns telling those rows apart, which Find matches on.
+//
+// USAGE:
+//
+//	v := effective.Window("effective_start_date", "effective_end_date", "group_name")
+//	db.Scopes(v.AsOf(date)).Find(&fees)
+//
+//	// Creating a version: close the open one, then refuse overlaps
+//	v.Close(tx, fee)
+//	if overlaps, _ := v.Overlaps(tx, fee, ""); overlaps { ... }
+package effective
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrOverlap is returned when a version's validity window overlaps
+	// another version of the same natural key.
+	ErrOverlap = errors.New("effective: validity window overlaps another version")
+	// ErrNoStart is returned when a version has no start date, which AsOf
+	// would never return.
+	ErrNoStart = errors.New("effective: version has no start date")
+)
+
+// asOfLayouts are accepted by ?as_of=; a plain date means its midnight UTC.
+var asOfLayouts = []string{"2006-01-02", time.RFC3339}
+
+// Versioning describes how the rows of a table are dated.
+type Versioning struct {
+	Key    []string // Natural key: rows sharing it are versions of one record
+	Start  string   // Column the version takes effect on
+	End    string   // Column the version stops applying on (exclusive), "" for point tables
+	RowKey []string // Columns telling apart the rows of one version, e.g. a bracket's lower bound
+}
+
+// Point describes a table whose versions apply until the next one starts.
+func Point(start string, key ...string) *Versioning {
+	return &Versioning{Key: key, Start: start}
+}
+
+// Window describes a table whose versions carry their own end.
+func Window(start, end string, key ...string) *Versioning {
+	return &Versioning{Key: key, Start: start, End: end}
+}
+
+// Rows sets the columns telling apart the rows of one version, for point
+// tables whose versions are several rows.
+func (v *Versioning) Rows(columns ...string) *Versioning {
+	v.RowKey = columns
+	return v
+}
+
+// ParseAsOf reads the ?as_of= parameter. It returns nil when absent.
+func ParseAsOf(c *gin.Context) (*time.Time, *apperror.AppError) {
+	raw := c.Query("as_of")
+	if raw == "" {
+		return nil, nil
+	}
+	for _, layout := range asOfLayouts {
+		if date, err := time.Parse(layout, raw); err == nil {
+			return &date, nil
+		}
+	}
+	return nil, apperror.BadRequest("as_of must be a date (YYYY-MM-DD)")
+}
+
+// AsOf returns a scope keeping the versions valid on date. Rows without a
+// start date are never returned.
+func (v *Versioning) AsOf(date time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		start := clause.Column{Table: clause.CurrentTable, Name: v.Start}
+		if v.End != "" {
+			end := clause.Column{Table: clause.CurrentTable, Name: v.End}
+			return db.Where("? <= ? AND (? IS NULL OR ? > ?)", start, date, end, end, date)
+		}
+
+		// The latest start on or before date among the rows of the same key
+		sql := "? <= ? AND ? = (SELECT MAX(?) FROM ? WHERE ? IS NULL AND ? <= ?"
+		vars := []any{
+			start, date, start,
+			clause.Column{Table: "v", Name: v.Start},
+			clause.Table{Name: clause.CurrentTable, Alias: "v"},
+			clause.Column{Table: "v", Name: "deleted_at"},
+			clause.Column{Table: "v", Name: v.Start}, date,
+		}
+		for _, key := range v.Key {
+			sql += " AND ? IS NOT DISTINCT FROM ?"
+			vars = append(vars, clause.Column{Table: "v", Name: key}, clause.Column{Table: clause.CurrentTable, Name: key})
+		}
+		return db.Where(sql+")", vars...)
+	}
+}
+
+// Close ends the open version (no end) of the model's natural key that
+// started before the model, at the model's start. Point tables need no
+// closing. Call it in the transaction inserting the model.
+func (v *Versioning) Close(tx *gorm.DB, model any) error {
+	values, err := v.values(tx, model)
+	if err != nil {
+		return err
+	}
+	if values.start == nil {
+		return ErrNoStart
+	}
+	if v.End == "" {
+		return nil
+	}
+
+	stmt := tx.Table(values.table).Where("deleted_at IS NULL")
+	for i, key := range v.Key {
+		stmt = stmt.Where(clause.Eq{Column: clause.Column{Name: key}, Value: values.key[i]})
+	}
+	return stmt.
+		Where(clause.Eq{Column: clause.Column{Name: v.End}, Value: nil}).
+		Where(clause.Lt{Column: clause.Column{Name: v.Start}, Value: values.start}).
+		Updates(map[string]any{v.End: values.start, "updated_at": time.Now()}).Error
+}
+
+// Overlaps reports whether the model's validity window overlaps another
+// live version of its natural key; excludeID is the model's own row when
+// updating. Point table versions cannot overlap.
+func (v *Versioning) Overlaps(tx *gorm.DB, model any, excludeID string) (bool, error) {
+	values, err := v.values(tx, model)
+	if err != nil {
+		return false, err
+	}
+	if values.start == nil {
+		return false, ErrNoStart
+	}
+	if v.End == "" {
+		return false, nil
+	}
+
+	stmt := tx.Table(values.table).Where("deleted_at IS NULL")
+	for i, key := range v.Key {
+		stmt = stmt.Where(clause.Eq{Column: clause.Column{Name: key}, Value: values.key[i]})
+	}
+	if excludeID != "" {
+		stmt = stmt.Where("id <> ?", excludeID)
+	}
+	end := clause.Column{Name: v.End}
+	stmt = stmt.Where("(? IS NULL OR ? > ?)", end, end, values.start)
+	if values.end != nil {
+		stmt = stmt.Where(clause.Lt{Column: clause.Column{Name: v.Start}, Value: values.end})
+	}
+
+	var count int64
+	if err := stmt.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Check returns ErrOverlap if the model overlaps another version, like
+// Overlaps.
+func (v *Versioning) Check(tx *gorm.DB, model any, excludeID string) error {
+	overlaps, err := v.Overlaps(tx, model, excludeID)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return ErrOverlap
+	}
+	return nil
+}
+
+// Find loads into dest the live row holding the model's place: the row of
+// the same natural key, start and row key. It returns gorm.ErrRecordNotFound
+// when there is none.
+func (v *Versioning) Find(tx *gorm.DB, model, dest any) error {
+	values, err := v.values(tx, model)
+	if err != nil {
+		return err
+	}
+
+	stmt := tx.Where(clause.Eq{Column: clause.Column{Name: v.Start}, Value: values.start})
+	for i, key := range v.Key {
+		stmt = stmt.Where(clause.Eq{Column: clause.Column{Name: key}, Value: values.key[i]})
+	}
+	for i, key := range v.RowKey {
+		stmt = stmt.Where(clause.Eq{Column: clause.Column{Name: key}, Value: values.row[i]})
+	}
+	return stmt.First(dest).Error
+}
+
+type versionValues struct {
+	table      string
+	key, row   []any
+	start, end any // nil when NULL
+}
+
+// values reads the table, natural key, row key and window of a model.
+func (v *Versioning) values(tx *gorm.DB, model any) (*versionValues, error) {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	row := reflect.Indirect(reflect.ValueOf(model))
+
+	read := func(column string) any {
+		field := stmt.Schema.LookUpField(column)
+		if field == nil {
+			return nil
+		}
+		value, _ := field.ValueOf(tx.Statement.Context, row)
+		if ptr := reflect.ValueOf(value); ptr.Kind() == reflect.Ptr {
+			if ptr.IsNil() {
+				return nil
+			}
+			value = ptr.Elem().Interface()
+		}
+		if t, ok := value.(time.Time); ok && t.IsZero() {
+			return nil
+		}
+		return value
+	}
+
+	values := &versionValues{table: stmt.Schema.Table, start: read(v.Start)}
+	if v.End != "" {
+		values.end = read(v.End)
+	}
+	for _, key := range v.Key {
+		values.key = append(values.key, read(key))
+	}
+	for _, key := range v.RowKey {
+		values.row = append(values.row, read(key))
+	}
+	return values, nil
+}
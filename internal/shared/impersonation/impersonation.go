@@ -0,0 +1,37 @@
+// Package impersonation carries an admin impersonation through a request.
+//
+// While an administrator acts as another user, the request's user_id (and
+// so every created_by/updated_by stamped from it) is the impersonated
+// subject. The Session in the request context names the real actor, and
+// the GORM Plugin journals every row written under it to
+// sys_impersonation_writes, so each stamped change can be traced back to
+// the person who made it.
+//
+// USAGE:
+//
+//	db.Use(impersonation.Plugin{})
+//	ctx = impersonation.WithSession(ctx, &impersonation.Session{ID: id, ActorID: actor, SubjectID: subject})
+//	db.WithContext(ctx).Save(&user) // also inserts a sys_impersonation_writes row
+package impersonation
+
+import "context"
+
+type contextKey struct{}
+
+// Session identifies an impersonation: ID is its sys_user_impersonations row.
+type Session struct {
+	ID        string
+	ActorID   string // The administrator
+	SubjectID string // The impersonated user
+}
+
+// WithSession returns a context carrying the impersonation.
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, contextKey{}, session)
+}
+
+// FromContext returns the impersonation carried by the context, if any.
+func FromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(contextKey{}).(*Session)
+	return session, ok && session != nil
+}
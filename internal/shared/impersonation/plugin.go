@@ -0,0 +1,123 @@
+package impersonation
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/user/go-boilerplate/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// WritesTable is the journal of rows written while impersonating.
+const WritesTable = "sys_impersonation_writes"
+
+// Journaled operations.
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// Plugin journals writes made under an impersonation on a *gorm.DB.
+// The journal row is inserted in the same transaction as the write, so a
+// write that cannot be journaled is rolled back. Raw Exec statements are
+// not journaled.
+type Plugin struct{}
+
+// Name implements gorm.Plugin.
+func (Plugin) Name() string {
+	return "impersonation"
+}
+
+// Initialize implements gorm.Plugin.
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:after_create").Before("gorm:commit_or_rollback_transaction").
+		Register("impersonation:create", journal(OperationCreate)); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:after_update").Before("gorm:commit_or_rollback_transaction").
+		Register("impersonation:update", journal(OperationUpdate)); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:after_delete").Before("gorm:commit_or_rollback_transaction").
+		Register("impersonation:delete", journal(OperationDelete))
+}
+
+func journal(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.RowsAffected == 0 {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" || table == WritesTable {
+			return
+		}
+		session, ok := FromContext(db.Statement.Context)
+		if !ok {
+			return
+		}
+
+		requestID, _ := db.Statement.Context.Value(logger.RequestIDKey).(string)
+		now := time.Now()
+		entry := func(rowID *string) map[string]interface{} {
+			return map[string]interface{}{
+				"impersonation_id": session.ID,
+				"actor_id":         session.ActorID,
+				"subject_id":       session.SubjectID,
+				"target_table":     table,
+				"operation":        operation,
+				"row_id":           rowID,
+				"rows_affected":    db.RowsAffected,
+				"request_id":       requestID,
+				"created_at":       now,
+			}
+		}
+
+		// Writes by condition (e.g. Where("id = ?", id).Update) have no
+		// primary key on the model and are journaled without a row ID
+		ids := primaryKeys(db)
+		entries := make([]map[string]interface{}, 0, len(ids)+1)
+		for i := range ids {
+			entries = append(entries, entry(&ids[i]))
+		}
+		if len(entries) == 0 {
+			entries = append(entries, entry(nil))
+		}
+
+		if err := db.Session(&gorm.Session{NewDB: true}).Table(WritesTable).Create(entries).Error; err != nil {
+			_ = db.AddError(fmt.Errorf("impersonation: journal write: %w", err))
+		}
+	}
+}
+
+// primaryKeys returns the non-zero primary keys of the written model(s).
+func primaryKeys(db *gorm.DB) []string {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil
+	}
+	field := stmt.Schema.PrioritizedPrimaryField
+
+	var ids []string
+	collect := func(row reflect.Value) {
+		row = reflect.Indirect(row)
+		if row.Kind() != reflect.Struct || row.Type() != stmt.Schema.ModelType {
+			return
+		}
+		if value, zero := field.ValueOf(stmt.Context, row); !zero {
+			ids = append(ids, fmt.Sprint(reflect.Indirect(reflect.ValueOf(value)).Interface()))
+		}
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			collect(stmt.ReflectValue.Index(i))
+		}
+	case reflect.Struct:
+		collect(stmt.ReflectValue)
+	}
+	return ids
+}
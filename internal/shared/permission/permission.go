@@ -0,0 +1,84 @@
+// Package permission defines the permission catalog enforced by the API.
+// Codes follow the <module>.<resource>.<action> convention and are synced
+// into sys_permissions by the system seeder; roles and sub-roles receive
+// them through sys_role_permissions.
+package permission
+
+// Permission codes checked by middleware.RequirePermission.
+const (
+	// System module
+	SystemSettingsRead = "system.settings.read"
+	SystemRolesRead    = "system.roles.read"
+	SystemRolesManage  = "system.roles.manage"
+	SystemFeesRead     = "system.fees.read"
+	SystemFeesManage   = "system.fees.manage"
+	SystemMenusRead    = "system.menus.read"
+	SystemMenusManage  = "system.menus.manage"
+	SystemUsersRead    = "system.users.read"
+	SystemUsersManage  = "system.users.manage"
+
+	SystemUsersImpersonate = "system.users.impersonate"
+
+	SystemServiceAccountsRead   = "system.service_accounts.read"
+	SystemServiceAccountsManage = "system.service_accounts.manage"
+
+	// Security (auth module)
+	SecurityEventsRead     = "security.events.read"
+	SecurityLockoutsManage = "security.lockouts.manage"
+
+	// Row-level data scope
+	DataScopeArea     = "data.scope.area"
+	DataScopeOverride = "data.scope.override"
+
+	// Master module
+	MasterRead  = "master.read"
+	MasterWrite = "master.write"
+
+	// Transaction module
+	TransactionRead = "transaction.read"
+
+	// File module
+	FileRead  = "file.read"
+	FileWrite = "file.write"
+)
+
+// Definition describes a single permission in the catalog.
+type Definition struct {
+	Code        string
+	Module      string
+	Description string
+}
+
+// Catalog lists every permission known to the application.
+var Catalog = []Definition{
+	{SystemSettingsRead, "system", "View application settings"},
+	{SystemRolesRead, "system", "View roles, sub-roles and permissions"},
+	{SystemRolesManage, "system", "Change role policies such as mandatory two-factor authentication"},
+	{SystemFeesRead, "system", "View bank, base and transaction fees and pension ages"},
+	{SystemFeesManage, "system", "Create new base and transaction fee versions"},
+	{SystemMenusRead, "system", "View the menu structure"},
+	{SystemMenusManage, "system", "Create, move and hide menu items"},
+	{SystemUsersRead, "system", "Search and view user accounts"},
+	{SystemUsersManage, "system", "Create, update, deactivate and delete user accounts"},
+	{SystemUsersImpersonate, "system", "Act as another user to see what they see (audited)"},
+	{SystemServiceAccountsRead, "system", "View service accounts and their API keys"},
+	{SystemServiceAccountsManage, "system", "Create service accounts and issue, rotate and revoke API keys"},
+	{SecurityEventsRead, "security", "View the security event log"},
+	{SecurityLockoutsManage, "security", "Unlock accounts locked by failed logins"},
+	{DataScopeArea, "data", "See the records of every branch in the own area"},
+	{DataScopeOverride, "data", "See the records of every branch with X-Data-Scope: all (head office, audited)"},
+	{MasterRead, "master", "View master/reference data"},
+	{MasterWrite, "master", "Create, update, reorder and delete master/reference data"},
+	{TransactionRead, "transaction", "View batches and giro reconciliations"},
+	{FileRead, "file", "Export and download files"},
+	{FileWrite, "file", "Upload and delete files"},
+}
+
+// Codes returns every permission code in the catalog.
+func Codes() []string {
+	codes := make([]string, len(Catalog))
+	for i, def := range Catalog {
+		codes[i] = def.Code
+	}
+	return codes
+}
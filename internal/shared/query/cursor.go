@@ -0,0 +1,241 @@
+package query
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Cursor mode replaces OFFSET with a keyset seek, so deep pages cost the same
+// as the first one:
+//
+//	?cursor=&limit=50                 first page
+//	?cursor=<next_cursor>&limit=50    following page
+//	?cursor=<prev_cursor>&limit=50    preceding page
+//	&include_total=true               also count the matching rows
+//
+// The keyset is the requested sort, or the allowlist's default order when it
+// is a plain column list, always ending with id. A cursor carries the keyset
+// values of the row it continues from and is only valid for the same sort.
+// NULL keys sort after every value in both directions, as Postgres orders
+// them by default (NULLS LAST ascending, NULLS FIRST descending).
+
+// cursor is the decoded form of a next_cursor or prev_cursor token.
+type cursor struct {
+	Key    string    `json:"k"` // Keyset signature, e.g. "-name,id"
+	Values []*string `json:"v"` // Keyset values of the row continued from; nil is NULL
+	Before bool      `json:"b"` // Seek towards the start
+}
+
+var orderTerm = regexp.MustCompile(`^(?i)([a-z_][a-z0-9_]*)(\s+(asc|desc))?$`)
+
+// keysetOf returns the cursor mode sort: the requested sort, else the
+// default order if it is a plain column list, else id.
+func keysetOf(sorts []Sort, order string) []Sort {
+	keyset := append([]Sort(nil), sorts...)
+	if len(keyset) == 0 && order != "" {
+		for _, term := range strings.Split(order, ",") {
+			m := orderTerm.FindStringSubmatch(strings.TrimSpace(term))
+			if m == nil {
+				keyset = nil
+				break
+			}
+			keyset = append(keyset, Sort{Column: m[1], Desc: strings.EqualFold(m[3], "desc")})
+		}
+	}
+
+	for _, s := range keyset {
+		if s.Column == "id" {
+			return keyset
+		}
+	}
+	return append(keyset, Sort{Column: "id"})
+}
+
+func signature(keyset []Sort) string {
+	terms := make([]string, len(keyset))
+	for i, s := range keyset {
+		terms[i] = s.Column
+		if s.Desc {
+			terms[i] = "-" + s.Column
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+func encodeCursor(cur *cursor) string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(token string, keyset []Sort) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cur cursor
+	if err := json.Unmarshal(raw, &cur); err != nil {
+		return nil, err
+	}
+	if cur.Key != signature(keyset) || len(cur.Values) != len(keyset) {
+		return nil, errors.New("cursor does not match the sort")
+	}
+	return &cur, nil
+}
+
+// seek orders by the keyset, reversed when paging backwards, and fetches
+// one row past the page to learn whether another page follows.
+func (p *Params) seek(db *gorm.DB) *gorm.DB {
+	before := p.cursor != nil && p.cursor.Before
+
+	if p.cursor != nil {
+		// (a > x) OR (a = x AND b > y) OR ... with > flipped per direction
+		var or []clause.Expression
+		for i, s := range p.keyset {
+			var and []clause.Expression
+			for j := 0; j < i; j++ {
+				and = append(and, clause.Eq{Column: clause.Column{Name: p.keyset[j].Column}, Value: keyValue(p.cursor.Values[j])})
+			}
+			var term clause.Expression
+			if s.Desc != before {
+				term = keyBefore(clause.Column{Name: s.Column}, p.cursor.Values[i])
+			} else {
+				term = keyAfter(clause.Column{Name: s.Column}, p.cursor.Values[i])
+			}
+			if term == nil {
+				continue
+			}
+			or = append(or, clause.And(append(and, term)...))
+		}
+		if len(or) == 0 {
+			// Nothing sorts past the cursor
+			db = db.Where("1 = 0")
+		} else {
+			db = db.Where(clause.Or(or...))
+		}
+	}
+
+	for _, s := range p.keyset {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc != before})
+	}
+	return db.Limit(p.Limit + 1)
+}
+
+// keyValue turns a NULL key into nil so clause.Eq renders IS NULL.
+func keyValue(value *string) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// keyAfter matches the rows sorting after value in ascending order, NULLs
+// last. It returns nil when nothing does, i.e. value is NULL.
+func keyAfter(column clause.Column, value *string) clause.Expression {
+	if value == nil {
+		return nil
+	}
+	return clause.Or(clause.Gt{Column: column, Value: *value}, clause.Eq{Column: column, Value: nil})
+}
+
+// keyBefore matches the rows sorting before value in ascending order, NULLs
+// last, so every non-NULL value sorts before a NULL.
+func keyBefore(column clause.Column, value *string) clause.Expression {
+	if value == nil {
+		return clause.Neq{Column: column, Value: nil}
+	}
+	return clause.Lt{Column: column, Value: *value}
+}
+
+// window drops the extra row fetched by seek, restores the order of a
+// backward page and records the cursors of its first and last rows.
+func (p *Params) window(dest any) error {
+	items := reflect.ValueOf(dest).Elem()
+	more := items.Len() > p.Limit
+	if more {
+		items.Set(items.Slice(0, p.Limit))
+	}
+
+	before := p.cursor != nil && p.cursor.Before
+	if before {
+		for i, j := 0, items.Len()-1; i < j; i, j = i+1, j-1 {
+			a, b := items.Index(i).Interface(), items.Index(j).Interface()
+			items.Index(i).Set(reflect.ValueOf(b))
+			items.Index(j).Set(reflect.ValueOf(a))
+		}
+	}
+
+	p.next, p.prev = "", ""
+	if items.Len() == 0 {
+		return nil
+	}
+	hasNext := (!before && more) || (before && p.cursor != nil)
+	hasPrev := (before && more) || (!before && p.cursor != nil)
+
+	if hasNext {
+		values, err := p.keyValues(items.Index(items.Len() - 1))
+		if err != nil {
+			return err
+		}
+		p.next = encodeCursor(&cursor{Key: signature(p.keyset), Values: values})
+	}
+	if hasPrev {
+		values, err := p.keyValues(items.Index(0))
+		if err != nil {
+			return err
+		}
+		p.prev = encodeCursor(&cursor{Key: signature(p.keyset), Values: values, Before: true})
+	}
+	return nil
+}
+
+// keyValues reads the keyset columns of a row as text, which Postgres
+// converts back to the column types, and NULLs as nil.
+func (p *Params) keyValues(row reflect.Value) ([]*string, error) {
+	row = reflect.Indirect(row)
+	s, err := schema.Parse(row.Addr().Interface(), &schemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*string, len(p.keyset))
+	for i, key := range p.keyset {
+		field := s.LookUpField(key.Column)
+		if field == nil {
+			return nil, fmt.Errorf("query: %s has no column %s", s.Name, key.Column)
+		}
+		value, _ := field.ValueOf(context.Background(), row)
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				continue
+			}
+			value = v.Elem().Interface()
+		}
+		text := fmt.Sprint(value)
+		if t, ok := value.(time.Time); ok {
+			text = t.Format(time.RFC3339Nano)
+		}
+		values[i] = &text
+	}
+	return values, nil
+}
+
+// NextCursor returns the cursor of the following page, or "" on the last.
+func (p *Params) NextCursor() string {
+	return p.next
+}
+
+// PrevCursor returns the cursor of the preceding page, or "" on the first.
+func (p *Params) PrevCursor() string {
+	return p.prev
+}
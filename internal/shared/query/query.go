@@ -0,0 +1,413 @@
+// Package query parses the query grammar shared by every list endpoint into
+// GORM scopes:
+//
+//	?page=2&limit=20
+//	&filter[is_sharia]=true             equality
+//	&filter[code]=in:BCA,BNI            operator prefix: eq, in, gte, lte, like
+//	&filter[created_at]=gte:2024-01-01  repeat a field to combine operators
+//	&sort=-name,code                    "-" sorts descending
+//	&q=mandiri                          case-insensitive match on the search columns
+//	&fields=id,code,name                sparse fieldsets
+//	&cursor=                            keyset pagination, see cursor.go
+//
+// Every name must be in the endpoint's Allowlist and every filter value must
+// parse as the column's type; anything else is rejected with 400 before a
+// query is built, and column names are always quoted identifiers.
+package query
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/go-boilerplate/pkg/apperror"
+	"github.com/user/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// maxInValues caps the values of one in: filter.
+const maxInValues = 100
+
+// Type is the type of a filterable column; filter values must parse as it.
+type Type int
+
+const (
+	Text Type = iota
+	Bool
+	Number
+	Time
+	UUID
+)
+
+// Operator is a filter comparison.
+type Operator string
+
+const (
+	OpEq   Operator = "eq"
+	OpIn   Operator = "in"
+	OpGte  Operator = "gte"
+	OpLte  Operator = "lte"
+	OpLike Operator = "like" // Case-insensitive substring match, text columns only
+)
+
+// Allowlist names the columns a list endpoint exposes to the grammar.
+type Allowlist struct {
+	Filter map[string]Type // Columns usable in filter[...]
+	Sort   []string        // Columns usable in sort
+	Search []string        // Text columns matched by q; q is ignored when empty
+	Fields []string        // JSON fields usable in fields; id is always kept
+	Order  string          // Default ORDER BY when the request has no sort
+}
+
+// OrderBy returns a copy of the allowlist with the default order set.
+func (a Allowlist) OrderBy(order string) Allowlist {
+	a.Order = order
+	return a
+}
+
+// Filter is one parsed filter[...] condition.
+type Filter struct {
+	Column   string
+	Operator Operator
+	Values   []any
+}
+
+// Sort is one parsed sort column.
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Params is a parsed list query.
+type Params struct {
+	utils.PaginationParams
+	Filters []Filter
+	Sorts   []Sort
+	Search  string
+	Fields  []string
+
+	// Cursor mode, enabled by a cursor parameter
+	CursorMode   bool
+	IncludeTotal bool // Count the matching rows in cursor mode too
+
+	searchColumns []string
+	order         string  // Default order of page mode
+	keyset        []Sort  // Sort of cursor mode, always ending with id
+	cursor        *cursor // Position to continue from; nil on the first page
+	next, prev    string  // Cursors of the page, set by Find
+}
+
+// Parse reads the list query of the request against the allowlist.
+func Parse(c *gin.Context, allow Allowlist) (*Params, *apperror.AppError) {
+	return ParseValues(c.Request.URL.Query(), utils.GetPaginationParams(c), allow)
+}
+
+// ParseValues parses query values; Parse is the usual entry point.
+func ParseValues(values url.Values, page utils.PaginationParams, allow Allowlist) (*Params, *apperror.AppError) {
+	p := &Params{PaginationParams: page}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		column := key[len("filter[") : len(key)-1]
+		typ, ok := allow.Filter[column]
+		if !ok {
+			return nil, apperror.BadRequest("Cannot filter by " + column)
+		}
+		for _, raw := range values[key] {
+			filter, appErr := parseFilter(column, typ, raw)
+			if appErr != nil {
+				return nil, appErr
+			}
+			p.Filters = append(p.Filters, filter)
+		}
+	}
+
+	if raw := values.Get("sort"); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			name = strings.TrimSpace(name)
+			desc := strings.HasPrefix(name, "-")
+			name = strings.TrimPrefix(name, "-")
+			if !contains(allow.Sort, name) {
+				return nil, apperror.BadRequest("Cannot sort by " + name)
+			}
+			p.Sorts = append(p.Sorts, Sort{Column: name, Desc: desc})
+		}
+	}
+
+	if len(allow.Search) > 0 {
+		p.Search = strings.TrimSpace(values.Get("q"))
+		p.searchColumns = allow.Search
+	}
+
+	p.order = allow.Order
+	p.keyset = keysetOf(p.Sorts, allow.Order)
+	if _, ok := values["cursor"]; ok {
+		p.CursorMode = true
+		p.IncludeTotal, _ = strconv.ParseBool(values.Get("include_total"))
+		if raw := values.Get("cursor"); raw != "" {
+			cur, err := decodeCursor(raw, p.keyset)
+			if err != nil {
+				return nil, apperror.BadRequest("Invalid cursor")
+			}
+			p.cursor = cur
+		}
+	}
+
+	if raw := values.Get("fields"); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			name = strings.TrimSpace(name)
+			if name != "id" && !contains(allow.Fields, name) {
+				return nil, apperror.BadRequest("Unknown field " + name)
+			}
+			p.Fields = append(p.Fields, name)
+		}
+	}
+
+	return p, nil
+}
+
+func parseFilter(column string, typ Type, raw string) (Filter, *apperror.AppError) {
+	op, value := OpEq, raw
+	if prefix, rest, found := strings.Cut(raw, ":"); found {
+		switch Operator(prefix) {
+		case OpEq, OpIn, OpGte, OpLte, OpLike:
+			op, value = Operator(prefix), rest
+		}
+	}
+
+	invalid := apperror.BadRequest("Invalid filter on " + column)
+	switch op {
+	case OpLike:
+		if typ != Text {
+			return Filter{}, invalid
+		}
+	case OpGte, OpLte:
+		if typ == Bool || typ == UUID {
+			return Filter{}, invalid
+		}
+	}
+
+	raws := []string{value}
+	if op == OpIn {
+		raws = strings.Split(value, ",")
+		if len(raws) > maxInValues {
+			return Filter{}, apperror.BadRequest("Too many values in filter on " + column)
+		}
+	}
+
+	values := make([]any, len(raws))
+	for i, r := range raws {
+		v, ok := convert(typ, strings.TrimSpace(r))
+		if !ok {
+			return Filter{}, invalid
+		}
+		values[i] = v
+	}
+	return Filter{Column: column, Operator: op, Values: values}, nil
+}
+
+// convert parses a filter value as the column type.
+func convert(typ Type, raw string) (any, bool) {
+	switch typ {
+	case Bool:
+		v, err := strconv.ParseBool(raw)
+		return v, err == nil
+	case Number:
+		_, err := strconv.ParseFloat(raw, 64)
+		return raw, err == nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, true
+		}
+		t, err := time.Parse("2006-01-02", raw)
+		return t, err == nil
+	case UUID:
+		_, err := uuid.Parse(raw)
+		return raw, err == nil
+	default:
+		return raw, true
+	}
+}
+
+// Where applies the filters and search. Use it for both the count and the
+// page query.
+func (p *Params) Where(db *gorm.DB) *gorm.DB {
+	for _, f := range p.Filters {
+		column := clause.Column{Name: f.Column}
+		switch f.Operator {
+		case OpIn:
+			db = db.Where(clause.IN{Column: column, Values: f.Values})
+		case OpGte:
+			db = db.Where(clause.Gte{Column: column, Value: f.Values[0]})
+		case OpLte:
+			db = db.Where(clause.Lte{Column: column, Value: f.Values[0]})
+		case OpLike:
+			db = db.Where("? ILIKE ?", column, "%"+escapeLike(f.Values[0].(string))+"%")
+		default:
+			db = db.Where(clause.Eq{Column: column, Value: f.Values[0]})
+		}
+	}
+
+	if p.Search != "" {
+		like := "%" + escapeLike(p.Search) + "%"
+		exprs := make([]clause.Expression, len(p.searchColumns))
+		for i, name := range p.searchColumns {
+			exprs[i] = clause.Expr{SQL: "? ILIKE ?", Vars: []any{clause.Column{Name: name}, like}}
+		}
+		db = db.Where(clause.Or(exprs...))
+	}
+	return db
+}
+
+// Count counts the rows matching the filters into total. It skips the
+// count in cursor mode unless include_total is set.
+func (p *Params) Count(db *gorm.DB, total *int64) error {
+	if p.CursorMode && !p.IncludeTotal {
+		return nil
+	}
+	return db.Scopes(p.Where).Count(total).Error
+}
+
+// Find loads the page into dest, a pointer to a slice of entities. In
+// cursor mode it also records the page's cursors for the response.
+func (p *Params) Find(db *gorm.DB, dest any) error {
+	if err := db.Scopes(p.Where, p.Paginate).Find(dest).Error; err != nil {
+		return err
+	}
+	if p.CursorMode {
+		return p.window(dest)
+	}
+	return nil
+}
+
+// Paginate applies the sort and the page window. Page mode uses the
+// requested sort, or the allowlist's default order; requested sorts end
+// with id so pages are stable. Cursor mode uses the keyset instead.
+func (p *Params) Paginate(db *gorm.DB) *gorm.DB {
+	if p.CursorMode {
+		return p.seek(db)
+	}
+
+	if len(p.Sorts) == 0 {
+		if p.order != "" {
+			db = db.Order(p.order)
+		}
+	} else {
+		byID := false
+		for _, s := range p.Sorts {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc})
+			byID = byID || s.Column == "id"
+		}
+		if !byID {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+		}
+	}
+	return db.Offset(p.Offset()).Limit(p.Limit)
+}
+
+// Project trims each item of data to the requested fields. Data is returned
+// unchanged without a fields parameter.
+func (p *Params) Project(data any) (any, error) {
+	if len(p.Fields) == 0 {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+
+	projected := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		kept := make(map[string]json.RawMessage, len(p.Fields)+1)
+		if id, ok := item["id"]; ok {
+			kept["id"] = id
+		}
+		for _, name := range p.Fields {
+			if value, ok := item[name]; ok {
+				kept[name] = value
+			}
+		}
+		projected[i] = kept
+	}
+	return projected, nil
+}
+
+var schemas sync.Map
+
+// For builds the allowlist of an entity: every column may be filtered,
+// sorted and selected, and search covers the given text columns. Use an
+// explicit Allowlist when some columns must stay hidden. For panics if the
+// model cannot be parsed, so call it while wiring routes.
+func For(model any, search ...string) Allowlist {
+	s, err := schema.Parse(model, &schemas, schema.NamingStrategy{})
+	if err != nil {
+		panic("query: " + err.Error())
+	}
+
+	allow := Allowlist{Filter: make(map[string]Type), Search: search}
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.DBName == "deleted_at" {
+			continue
+		}
+		allow.Filter[field.DBName] = typeOf(field)
+		allow.Sort = append(allow.Sort, field.DBName)
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			allow.Fields = append(allow.Fields, name)
+		}
+	}
+	return allow
+}
+
+func typeOf(field *schema.Field) Type {
+	if strings.EqualFold(field.TagSettings["TYPE"], "uuid") {
+		return UUID
+	}
+	switch field.DataType {
+	case schema.Bool:
+		return Bool
+	case schema.Int, schema.Uint, schema.Float:
+		return Number
+	case schema.Time:
+		return Time
+	}
+	if field.FieldType.Kind() == reflect.Ptr && field.FieldType.Elem() == reflect.TypeOf(time.Time{}) {
+		return Time
+	}
+	return Text
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
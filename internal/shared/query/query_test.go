@@ -0,0 +1,250 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/user/go-boilerplate/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type row struct {
+	ID   string
+	Name *string
+}
+
+var allow = Allowlist{Sort: []string{"name", "id"}, Order: "created_at ASC, id ASC"}
+
+func ptr(s string) *string { return &s }
+
+// parse runs ParseValues in cursor mode with the given sort and cursor.
+func parse(t *testing.T, sort, token string) *Params {
+	t.Helper()
+	values := url.Values{"cursor": {token}}
+	if sort != "" {
+		values.Set("sort", sort)
+	}
+	p, appErr := ParseValues(values, utils.PaginationParams{Page: 1, Limit: 2}, allow)
+	if appErr != nil {
+		t.Fatalf("ParseValues: %v", appErr)
+	}
+	return p
+}
+
+// seekSQL renders the statement of p.seek without a database.
+func seekSQL(t *testing.T, p *Params) (string, []any) {
+	t.Helper()
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt := db.Model(&row{}).Scopes(p.seek).Find(&[]row{}).Statement
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestKeysetOf(t *testing.T) {
+	cases := []struct {
+		name  string
+		sorts []Sort
+		order string
+		want  []Sort
+	}{
+		{"default order", nil, "created_at ASC, id ASC", []Sort{{Column: "created_at"}, {Column: "id"}}},
+		{"descending default", nil, "sort_order DESC", []Sort{{Column: "sort_order", Desc: true}, {Column: "id"}}},
+		{"expression order", nil, "LOWER(name) ASC", []Sort{{Column: "id"}}},
+		{"requested sort", []Sort{{Column: "name", Desc: true}}, "created_at ASC", []Sort{{Column: "name", Desc: true}, {Column: "id"}}},
+		{"sort ending with id", []Sort{{Column: "id", Desc: true}}, "", []Sort{{Column: "id", Desc: true}}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keysetOf(tt.sorts, tt.order); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keysetOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeek(t *testing.T) {
+	cases := []struct {
+		name     string
+		sort     string
+		values   []*string
+		before   bool
+		wantSQL  string
+		wantVars []any
+	}{
+		{
+			name:     "ascending, forward",
+			sort:     "name",
+			values:   []*string{ptr("b"), ptr("2")},
+			wantSQL:  "SELECT * FROM `rows` WHERE ((`name` > ? OR `name` IS NULL) OR (`name` = ? AND (`id` > ? OR `id` IS NULL))) ORDER BY `name`,`id` LIMIT 3",
+			wantVars: []any{"b", "b", "2"},
+		},
+		{
+			name:     "ascending, backward",
+			sort:     "name",
+			values:   []*string{ptr("b"), ptr("2")},
+			before:   true,
+			wantSQL:  "SELECT * FROM `rows` WHERE (`name` < ? OR (`name` = ? AND `id` < ?)) ORDER BY `name` DESC,`id` DESC LIMIT 3",
+			wantVars: []any{"b", "b", "2"},
+		},
+		{
+			name:     "NULL key, ascending, forward",
+			sort:     "name",
+			values:   []*string{nil, ptr("2")},
+			wantSQL:  "SELECT * FROM `rows` WHERE (`name` IS NULL AND (`id` > ? OR `id` IS NULL)) ORDER BY `name`,`id` LIMIT 3",
+			wantVars: []any{"2"},
+		},
+		{
+			name:     "NULL key, ascending, backward",
+			sort:     "name",
+			values:   []*string{nil, ptr("2")},
+			before:   true,
+			wantSQL:  "SELECT * FROM `rows` WHERE (`name` IS NOT NULL OR (`name` IS NULL AND `id` < ?)) ORDER BY `name` DESC,`id` DESC LIMIT 3",
+			wantVars: []any{"2"},
+		},
+		{
+			name:     "NULL key, descending, forward",
+			sort:     "-name",
+			values:   []*string{nil, ptr("2")},
+			wantSQL:  "SELECT * FROM `rows` WHERE (`name` IS NOT NULL OR (`name` IS NULL AND (`id` > ? OR `id` IS NULL))) ORDER BY `name` DESC,`id` LIMIT 3",
+			wantVars: []any{"2"},
+		},
+		{
+			name:     "NULL key, descending, backward",
+			sort:     "-name",
+			values:   []*string{nil, ptr("2")},
+			before:   true,
+			wantSQL:  "SELECT * FROM `rows` WHERE (`name` IS NULL AND `id` < ?) ORDER BY `name`,`id` DESC LIMIT 3",
+			wantVars: []any{"2"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			keyset := keysetOf(parse(t, tt.sort, "").Sorts, allow.Order)
+			token := encodeCursor(&cursor{Key: signature(keyset), Values: tt.values, Before: tt.before})
+
+			sql, vars := seekSQL(t, parse(t, tt.sort, token))
+			if sql != tt.wantSQL {
+				t.Errorf("SQL = %s\nwant  %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(vars, tt.wantVars) {
+				t.Errorf("vars = %v, want %v", vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestParseValuesRejectsCursor(t *testing.T) {
+	nameID := []Sort{{Column: "name"}, {Column: "id"}}
+	cases := []struct {
+		name  string
+		sort  string
+		token string
+	}{
+		{"other sort", "-name", encodeCursor(&cursor{Key: signature(nameID), Values: []*string{ptr("b"), ptr("2")}})},
+		{"missing values", "name", encodeCursor(&cursor{Key: signature(nameID), Values: []*string{ptr("b")}})},
+		{"not base64", "name", "not a cursor!"},
+		{"not JSON", "name", "bm90IGpzb24"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			values := url.Values{"cursor": {tt.token}, "sort": {tt.sort}}
+			_, appErr := ParseValues(values, utils.PaginationParams{Page: 1, Limit: 2}, allow)
+			if appErr == nil {
+				t.Fatal("ParseValues accepted the cursor")
+			}
+			if appErr.HTTPStatus != http.StatusBadRequest || appErr.Message != "Invalid cursor" {
+				t.Errorf("ParseValues error = %d %q, want 400 \"Invalid cursor\"", appErr.HTTPStatus, appErr.Message)
+			}
+		})
+	}
+}
+
+func TestWindowCursors(t *testing.T) {
+	cases := []struct {
+		name      string
+		token     func(keyset []Sort) string
+		rows      []row
+		wantNames []*string
+		wantNext  []*string
+		wantPrev  []*string
+	}{
+		{
+			name:      "first page with a NULL key",
+			token:     func([]Sort) string { return "" },
+			rows:      []row{{ID: "1", Name: ptr("a")}, {ID: "2"}, {ID: "3"}},
+			wantNames: []*string{ptr("a"), nil},
+			wantNext:  []*string{nil, ptr("2")},
+		},
+		{
+			name: "backward page is restored to ascending order",
+			token: func(keyset []Sort) string {
+				return encodeCursor(&cursor{Key: signature(keyset), Values: []*string{nil, ptr("3")}, Before: true})
+			},
+			rows:      []row{{ID: "2"}, {ID: "1", Name: ptr("a")}},
+			wantNames: []*string{ptr("a"), nil},
+			wantNext:  []*string{nil, ptr("2")},
+		},
+		{
+			name: "middle page",
+			token: func(keyset []Sort) string {
+				return encodeCursor(&cursor{Key: signature(keyset), Values: []*string{ptr("a"), ptr("1")}})
+			},
+			rows:      []row{{ID: "2", Name: ptr("b")}, {ID: "3"}, {ID: "4"}},
+			wantNames: []*string{ptr("b"), nil},
+			wantNext:  []*string{nil, ptr("3")},
+			wantPrev:  []*string{ptr("b"), ptr("2")},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parse(t, "name", tt.token(keysetOf([]Sort{{Column: "name"}}, "")))
+			rows := append([]row(nil), tt.rows...)
+			if err := p.window(&rows); err != nil {
+				t.Fatalf("window: %v", err)
+			}
+
+			var names []*string
+			for _, r := range rows {
+				names = append(names, r.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("names = %v, want %v", show(names), show(tt.wantNames))
+			}
+			if got := cursorValues(t, p, p.NextCursor()); !reflect.DeepEqual(got, tt.wantNext) {
+				t.Errorf("next cursor = %v, want %v", show(got), show(tt.wantNext))
+			}
+			if got := cursorValues(t, p, p.PrevCursor()); !reflect.DeepEqual(got, tt.wantPrev) {
+				t.Errorf("prev cursor = %v, want %v", show(got), show(tt.wantPrev))
+			}
+		})
+	}
+}
+
+// cursorValues decodes the keyset values of a cursor, nil for "".
+func cursorValues(t *testing.T, p *Params, token string) []*string {
+	t.Helper()
+	if token == "" {
+		return nil
+	}
+	cur, err := decodeCursor(token, p.keyset)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	return cur.Values
+}
+
+func show(values []*string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = "NULL"
+		if v != nil {
+			out[i] = *v
+		}
+	}
+	return out
+}
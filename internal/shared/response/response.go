@@ -2,7 +2,10 @@
 package response
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/user/go-boilerplate/internal/shared/query"
 )
 
 // ErrorDetail contains structured error information.
@@ -32,6 +35,16 @@ type PaginatedResponse struct {
 	TotalPages int   `json:"total_pages"`
 }
 
+// CursorPaginatedResponse is used instead of PaginatedResponse when the
+// request asks for cursor pagination (?cursor=).
+type CursorPaginatedResponse struct {
+	Data       any     `json:"data"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+	PerPage    int     `json:"per_page"`
+	Total      *int64  `json:"total,omitempty"` // Only with include_total=true
+}
+
 // Success sends a success response.
 func Success(c *gin.Context, status int, message string, data any) {
 	c.JSON(status, SuccessResponse{
@@ -51,17 +64,45 @@ func Error(c *gin.Context, status int, code string, message string, details any)
 	})
 }
 
-// Paginated sends a paginated response.
-func Paginated(c *gin.Context, status int, data any, total int64, page, perPage int) {
-	totalPages := int(total) / perPage
-	if int(total)%perPage > 0 {
+// Paginated sends a paginated response, trimming the items to the fields
+// requested in params. Cursor mode requests get a CursorPaginatedResponse.
+func Paginated(c *gin.Context, status int, data any, total int64, params *query.Params) {
+	data, err := params.Project(data)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to encode response", nil)
+		return
+	}
+
+	if params.CursorMode {
+		resp := CursorPaginatedResponse{
+			Data:       data,
+			NextCursor: optional(params.NextCursor()),
+			PrevCursor: optional(params.PrevCursor()),
+			PerPage:    params.Limit,
+		}
+		if params.IncludeTotal {
+			resp.Total = &total
+		}
+		c.JSON(status, resp)
+		return
+	}
+
+	totalPages := int(total) / params.Limit
+	if int(total)%params.Limit > 0 {
 		totalPages++
 	}
 	c.JSON(status, PaginatedResponse{
 		Data:       data,
 		Total:      total,
-		Page:       page,
-		PerPage:    perPage,
+		Page:       params.Page,
+		PerPage:    params.Limit,
 		TotalPages: totalPages,
 	})
 }
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -14,6 +14,11 @@ const (
 	ErrCodeForbidden        ErrorCode = "FORBIDDEN"
 	ErrCodeTokenExpired     ErrorCode = "TOKEN_EXPIRED"
 	ErrCodeInvalidToken     ErrorCode = "INVALID_TOKEN"
+	ErrCodeTokenRevoked     ErrorCode = "TOKEN_REVOKED"
+	ErrCodeAccountLocked    ErrorCode = "ACCOUNT_LOCKED"
+	ErrCodeInvalidMFACode   ErrorCode = "INVALID_MFA_CODE"
+	ErrCodePasswordChangeRequired ErrorCode = "PASSWORD_CHANGE_REQUIRED"
+	ErrCodeImpersonationReadOnly ErrorCode = "IMPERSONATION_READ_ONLY"
 
 	// Validation errors
 	ErrCodeValidation       ErrorCode = "VALIDATION_ERROR"
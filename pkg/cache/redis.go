@@ -8,6 +8,8 @@
 // - JSON serialization/deserialization
 // - TTL support
 // - Health checking
+// - Pub/sub for cross-instance notifications
+// - Singleflight loading (Group)
 //
 // USAGE:
 //
@@ -120,6 +122,34 @@ func (c *Client) Set(ctx context.Context, key string, value interface{}, ttl tim
 	return nil
 }
 
+// SetNX stores a value only if the key does not already exist.
+// The check and the write happen atomically on the Redis side, which makes
+// it suitable for one-time markers (e.g. consumed tokens).
+//
+// PARAMETERS:
+// - ctx: Context for cancellation
+// - key: Cache key
+// - value: Value to cache (will be JSON encoded)
+// - ttl: Time to live (0 = no expiration)
+//
+// RETURNS:
+// - bool: true if the key was set, false if it already existed
+// - error: nil on success
+func (c *Client) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	ok, err := c.rdb.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		logger.Log.Warn("Redis SETNX failed", zap.String("key", key), zap.Error(err))
+		return false, err
+	}
+
+	return ok, nil
+}
+
 // Get retrieves a value from Redis and unmarshals it into the target.
 //
 // PARAMETERS:
@@ -168,6 +198,34 @@ func (c *Client) Delete(ctx context.Context, keys ...string) error {
 	return nil
 }
 
+// Incr atomically increments an integer counter and returns the new value.
+// The TTL is applied when the counter is created, so the window starts at
+// the first increment and is not extended by later ones.
+//
+// PARAMETERS:
+// - ctx: Context for cancellation
+// - key: Counter key
+// - ttl: Time to live for a newly created counter (0 = no expiration)
+//
+// RETURNS:
+// - int64: Counter value after the increment
+// - error: nil on success
+func (c *Client) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	n, err := c.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		logger.Log.Warn("Redis INCR failed", zap.String("key", key), zap.Error(err))
+		return 0, err
+	}
+
+	if n == 1 && ttl > 0 {
+		if err := c.rdb.Expire(ctx, key, ttl).Err(); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
 // Exists checks if a key exists in Redis.
 //
 // PARAMETERS:
@@ -250,6 +308,58 @@ func (c *Client) GetTTL(ctx context.Context, key string) (time.Duration, error)
 	return c.rdb.TTL(ctx, key).Result()
 }
 
+// ============================================================================
+// PUB/SUB
+// ============================================================================
+
+// Publish sends a message to every subscriber of a channel, on any instance
+// connected to the same Redis.
+//
+// PARAMETERS:
+// - ctx: Context for cancellation
+// - channel: Channel name
+// - message: Payload
+//
+// RETURNS:
+// - error: nil on success
+func (c *Client) Publish(ctx context.Context, channel, message string) error {
+	if err := c.rdb.Publish(ctx, channel, message).Err(); err != nil {
+		logger.Log.Warn("Redis PUBLISH failed", zap.String("channel", channel), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Subscribe calls handler with the payload of every message published to a
+// channel until ctx is cancelled. The subscription is re-established after
+// connection errors; messages published meanwhile are lost.
+//
+// PARAMETERS:
+// - ctx: Context ending the subscription
+// - channel: Channel name
+// - handler: Called for each message, one at a time
+//
+// EXAMPLE:
+//
+//	go cache.Subscribe(ctx, "master:invalidate", func(key string) { ... })
+func (c *Client) Subscribe(ctx context.Context, channel string, handler func(message string)) {
+	pubsub := c.rdb.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			handler(msg.Payload)
+		}
+	}
+}
+
 // ============================================================================
 // CACHE KEYS HELPER
 // ============================================================================
@@ -0,0 +1,282 @@
+// Package directory authenticates users against an LDAP server such as
+// OpenLDAP or Active Directory.
+//
+// FLOW:
+// 1. Bind with the service account (or anonymously when BindDN is empty)
+// 2. Search BaseDN with UserFilter to find exactly one entry for the login
+// 3. Bind as that entry with the user's password
+// 4. Read the mapped attributes and group memberships
+//
+// USAGE:
+//
+//	client, err := directory.New(directory.Config{URL: "ldaps://ldap.example.com", ...})
+//	entry, err := client.Authenticate(ctx, "jane@example.com", "secret")
+package directory
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	// ErrNotFound means no single directory entry matches the login.
+	ErrNotFound = errors.New("directory: user not found")
+	// ErrInvalidCredentials means the entry exists but the password is wrong.
+	ErrInvalidCredentials = errors.New("directory: invalid credentials")
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Config holds LDAP connection, search and attribute mapping settings.
+type Config struct {
+	URL string // ldap://host:389 or ldaps://host:636
+
+	// TLS
+	StartTLS           bool   // Upgrade an ldap:// connection
+	CACertFile         string // Optional: PEM bundle trusted in addition to the system roots
+	ServerName         string // Optional: overrides the host name checked in the certificate
+	InsecureSkipVerify bool   // Local development only
+
+	// Service account used for the user search
+	BindDN       string
+	BindPassword string
+
+	BaseDN     string
+	UserFilter string // "%s" is replaced by the escaped login, e.g. (mail=%s)
+
+	// Attribute mapping
+	EmailAttribute          string
+	FullNameAttribute       string
+	EmployeeNumberAttribute string
+	DivisionAttribute       string
+	GroupAttribute          string // Multi-valued group DNs, e.g. memberOf
+
+	// Optional group search for servers without a memberOf attribute.
+	// "%s" is replaced by the escaped user DN.
+	GroupBaseDN string
+	GroupFilter string
+
+	Timeout time.Duration
+}
+
+// Entry is an authenticated directory user.
+type Entry struct {
+	DN             string
+	Email          string
+	FullName       string
+	EmployeeNumber string
+	DivisionName   string
+	// Groups holds each group's DN and its first RDN value (e.g. the cn), so
+	// role mappings can name either.
+	Groups []string
+}
+
+// Client authenticates users against an LDAP server. It opens a new
+// connection per call.
+type Client struct {
+	config Config
+	tls    *tls.Config
+}
+
+// New validates the configuration and prepares the TLS settings.
+//
+// RETURNS: Client ready for use, or an error for an incomplete configuration
+func New(cfg Config) (*Client, error) {
+	if cfg.URL == "" || cfg.BaseDN == "" || cfg.UserFilter == "" {
+		return nil, errors.New("directory: URL, base DN and user filter are required")
+	}
+	if !strings.Contains(cfg.UserFilter, "%s") {
+		return nil, errors.New("directory: user filter must contain %s")
+	}
+	if cfg.GroupFilter != "" && cfg.GroupBaseDN == "" {
+		cfg.GroupBaseDN = cfg.BaseDN
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // Opt-in for local development
+		MinVersion:         tls.VersionTLS12,
+	}
+	if cfg.CACertFile != "" {
+		pem, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("directory: read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("directory: CA file contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &Client{config: cfg, tls: tlsConfig}, nil
+}
+
+// Authenticate verifies the login and password and returns the user's
+// mapped attributes.
+//
+// RETURNS: ErrNotFound, ErrInvalidCredentials, or a connection error
+func (c *Client) Authenticate(ctx context.Context, login, password string) (*Entry, error) {
+	// An empty password is an unauthenticated bind, which servers accept
+	if password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := c.bindService(conn); err != nil {
+		return nil, err
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, int(c.config.Timeout.Seconds()), false,
+		replaceFilter(c.config.UserFilter, login),
+		c.attributes(), nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("directory: search user: %w", err)
+	}
+	// Ambiguous logins are rejected rather than guessing an entry
+	if len(result.Entries) != 1 {
+		return nil, ErrNotFound
+	}
+	user := result.Entries[0]
+
+	if err := conn.Bind(user.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("directory: bind user: %w", err)
+	}
+
+	entry := &Entry{
+		DN:             user.DN,
+		Email:          user.GetAttributeValue(c.config.EmailAttribute),
+		FullName:       user.GetAttributeValue(c.config.FullNameAttribute),
+		EmployeeNumber: user.GetAttributeValue(c.config.EmployeeNumberAttribute),
+		DivisionName:   user.GetAttributeValue(c.config.DivisionAttribute),
+	}
+
+	groupDNs := user.GetAttributeValues(c.config.GroupAttribute)
+	if c.config.GroupFilter != "" {
+		// Group entries may only be readable by the service account
+		if err := c.bindService(conn); err != nil {
+			return nil, err
+		}
+		found, err := c.searchGroups(conn, user.DN)
+		if err != nil {
+			return nil, err
+		}
+		groupDNs = append(groupDNs, found...)
+	}
+	entry.Groups = groupNames(groupDNs)
+
+	return entry, nil
+}
+
+func (c *Client) dial(ctx context.Context) (*ldap.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.config.Timeout}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+
+	conn, err := ldap.DialURL(c.config.URL, ldap.DialWithTLSConfig(c.tls), ldap.DialWithDialer(dialer))
+	if err != nil {
+		return nil, fmt.Errorf("directory: connect: %w", err)
+	}
+	conn.SetTimeout(c.config.Timeout)
+
+	if c.config.StartTLS {
+		if err := conn.StartTLS(c.tls); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("directory: start TLS: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (c *Client) bindService(conn *ldap.Conn) error {
+	if c.config.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(c.config.BindDN, c.config.BindPassword); err != nil {
+		return fmt.Errorf("directory: bind service account: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) searchGroups(conn *ldap.Conn, userDN string) ([]string, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.config.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(c.config.Timeout.Seconds()), false,
+		replaceFilter(c.config.GroupFilter, userDN),
+		[]string{"1.1"}, nil, // No attributes, DNs only
+	))
+	if err != nil {
+		return nil, fmt.Errorf("directory: search groups: %w", err)
+	}
+
+	dns := make([]string, 0, len(result.Entries))
+	for _, group := range result.Entries {
+		dns = append(dns, group.DN)
+	}
+	return dns, nil
+}
+
+func (c *Client) attributes() []string {
+	var attrs []string
+	for _, attr := range []string{
+		c.config.EmailAttribute,
+		c.config.FullNameAttribute,
+		c.config.EmployeeNumberAttribute,
+		c.config.DivisionAttribute,
+		c.config.GroupAttribute,
+	} {
+		if attr != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+// replaceFilter substitutes every "%s" with the escaped value.
+func replaceFilter(filter, value string) string {
+	return strings.ReplaceAll(filter, "%s", ldap.EscapeFilter(value))
+}
+
+// groupNames returns each group DN followed by its first RDN value.
+func groupNames(dns []string) []string {
+	names := make([]string, 0, len(dns)*2)
+	for _, dn := range dns {
+		names = append(names, dn)
+		parsed, err := ldap.ParseDN(dn)
+		if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+			continue
+		}
+		names = append(names, parsed.RDNs[0].Attributes[0].Value)
+	}
+	return names
+}
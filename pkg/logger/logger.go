@@ -14,6 +14,7 @@ type ContextKey string
 const (
 	RequestIDKey ContextKey = "request_id"
 	UserIDKey    ContextKey = "user_id"
+	ActorIDKey   ContextKey = "actor_id" // Administrator impersonating user_id
 )
 
 var Log *zap.Logger
@@ -48,7 +49,7 @@ func InitLogger(level, env string) {
 	Log = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
 }
 
-// WithContext returns a logger with context values (request_id, user_id)
+// WithContext returns a logger with context values (request_id, user_id, actor_id)
 func WithContext(ctx context.Context) *zap.Logger {
 	logger := Log
 	if requestID, ok := ctx.Value(RequestIDKey).(string); ok {
@@ -57,6 +58,9 @@ func WithContext(ctx context.Context) *zap.Logger {
 	if userID, ok := ctx.Value(UserIDKey).(string); ok {
 		logger = logger.With(zap.String("user_id", userID))
 	}
+	if actorID, ok := ctx.Value(ActorIDKey).(string); ok {
+		logger = logger.With(zap.String("actor_id", actorID))
+	}
 	return logger
 }
 
@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileSender writes each message to an .eml file instead of sending it.
+type FileSender struct {
+	from string
+	dir  string
+}
+
+// NewFileSender creates a sender that writes to the given outbox directory.
+func NewFileSender(from, dir string) *FileSender {
+	if dir == "" {
+		dir = "storage/outbox"
+	}
+	return &FileSender{from: from, dir: dir}
+}
+
+// Send writes the message to <dir>/<timestamp>-<random>.eml.
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), randomSuffix())
+	path := filepath.Join(s.dir, name)
+
+	if err := os.WriteFile(path, compose(s.from, msg), 0o600); err != nil {
+		return fmt.Errorf("failed to write outbox message: %w", err)
+	}
+
+	return nil
+}
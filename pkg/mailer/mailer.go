@@ -0,0 +1,111 @@
+// Package mailer provides outgoing email delivery behind a small interface.
+// Implementations are selected by configuration so application code never
+// depends on a specific transport.
+//
+// DRIVERS:
+// - smtp: delivers through an SMTP server (STARTTLS when offered)
+// - file: writes each message as an .eml file to an outbox directory (local dev/tests)
+//
+// USAGE:
+//
+//	sender, err := mailer.New(mailer.Config{Driver: "smtp", ...})
+//	sender.Send(ctx, mailer.Message{To: []string{"a@example.com"}, Subject: "Hi", Body: "..."})
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Supported drivers.
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+)
+
+// Sender delivers email messages.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Message is a plain-text email.
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Config holds mailer configuration.
+type Config struct {
+	Driver string
+	From   string
+
+	// SMTP
+	Host     string
+	Port     string
+	Username string
+	Password string
+
+	// File outbox
+	OutboxDir string
+}
+
+// New creates a Sender for the configured driver.
+//
+// RETURNS: Sender ready for use, or an error for an unknown driver
+func New(cfg Config) (Sender, error) {
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTPSender(cfg), nil
+	case DriverFile, "":
+		return NewFileSender(cfg.From, cfg.OutboxDir), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
+
+// compose renders a message as RFC 5322 bytes.
+func compose(from string, msg Message) []byte {
+	var buf bytes.Buffer
+
+	writeHeader(&buf, "From", from)
+	writeHeader(&buf, "To", strings.Join(msg.To, ", "))
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", messageID(from))
+	writeHeader(&buf, "MIME-Version", "1.0")
+	writeHeader(&buf, "Content-Type", "text/plain; charset=UTF-8")
+	writeHeader(&buf, "Content-Transfer-Encoding", "8bit")
+	buf.WriteString("\r\n")
+
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return buf.Bytes()
+}
+
+// writeHeader writes a header line, stripping CR/LF to prevent header injection.
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+	fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+	return fmt.Sprintf("<%s@%s>", randomSuffix(), domain)
+}
+
+func randomSuffix() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+)
+
+// SMTPSender delivers messages through an SMTP server.
+type SMTPSender struct {
+	addr     string
+	host     string
+	from     string
+	username string
+	password string
+}
+
+// NewSMTPSender creates a new SMTP sender.
+// Authentication is only attempted when a username is configured, so
+// unauthenticated development servers (e.g. Mailpit) work out of the box.
+func NewSMTPSender(cfg Config) *SMTPSender {
+	return &SMTPSender{
+		addr:     net.JoinHostPort(cfg.Host, cfg.Port),
+		host:     cfg.Host,
+		from:     cfg.From,
+		username: cfg.Username,
+		password: cfg.Password,
+	}
+}
+
+// Send delivers the message. STARTTLS is used automatically when the server
+// advertises it.
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	envelopeFrom := s.from
+	if addr, err := mail.ParseAddress(s.from); err == nil {
+		envelopeFrom = addr.Address
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	if err := smtp.SendMail(s.addr, auth, envelopeFrom, msg.To, compose(s.from, msg)); err != nil {
+		return fmt.Errorf("failed to send mail via %s: %w", s.addr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package password enforces the password strength policy.
+//
+// RULES:
+// - minimum length (and bcrypt's 72 byte maximum)
+// - required character classes: upper, lower, digit, symbol
+// - not on the common-password list (embedded, plus an optional local file)
+//
+// Violations are reported as validator.ValidationError entries so they can
+// be returned to clients like any other field validation failure.
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/user/go-boilerplate/pkg/validator"
+)
+
+// Character classes accepted in Config.RequiredClasses.
+const (
+	ClassUpper  = "upper"
+	ClassLower  = "lower"
+	ClassDigit  = "digit"
+	ClassSymbol = "symbol"
+)
+
+// MaxBytes is the longest password bcrypt can hash.
+const MaxBytes = 72
+
+//go:embed common.txt
+var commonList string
+
+// Config holds the policy settings.
+type Config struct {
+	MinLength       int
+	RequiredClasses []string
+	// CommonListFile optionally adds one password per line to the embedded list.
+	CommonListFile string
+}
+
+// Policy checks candidate passwords.
+type Policy struct {
+	minLength int
+	classes   map[string]bool
+	common    map[string]struct{}
+}
+
+// NewPolicy builds a policy from configuration.
+//
+// RETURNS: Policy, or an error for an unknown class or unreadable list file
+func NewPolicy(cfg Config) (*Policy, error) {
+	p := &Policy{
+		minLength: cfg.MinLength,
+		classes:   make(map[string]bool),
+		common:    make(map[string]struct{}),
+	}
+
+	for _, class := range cfg.RequiredClasses {
+		class = strings.ToLower(strings.TrimSpace(class))
+		switch class {
+		case "":
+			continue
+		case ClassUpper, ClassLower, ClassDigit, ClassSymbol:
+			p.classes[class] = true
+		default:
+			return nil, fmt.Errorf("unknown password character class %q", class)
+		}
+	}
+
+	p.addCommon(commonList)
+	if cfg.CommonListFile != "" {
+		data, err := os.ReadFile(cfg.CommonListFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read common password list: %w", err)
+		}
+		p.addCommon(string(data))
+	}
+
+	return p, nil
+}
+
+// Check returns every rule the password breaks, attributed to field.
+// It returns nil when the password is acceptable.
+func (p *Policy) Check(field, password string) []validator.ValidationError {
+	var errs []validator.ValidationError
+	fail := func(message string) {
+		errs = append(errs, validator.ValidationError{Field: field, Message: message})
+	}
+
+	if n := len([]rune(password)); n < p.minLength {
+		fail("Must be at least " + strconv.Itoa(p.minLength) + " characters")
+	}
+	if len(password) > MaxBytes {
+		fail("Must be at most " + strconv.Itoa(MaxBytes) + " bytes")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.classes[ClassUpper] && !upper {
+		fail("Must contain an uppercase letter")
+	}
+	if p.classes[ClassLower] && !lower {
+		fail("Must contain a lowercase letter")
+	}
+	if p.classes[ClassDigit] && !digit {
+		fail("Must contain a digit")
+	}
+	if p.classes[ClassSymbol] && !symbol {
+		fail("Must contain a symbol")
+	}
+
+	if _, found := p.common[strings.ToLower(password)]; found {
+		fail("Is too common, choose a less predictable password")
+	}
+
+	return errs
+}
+
+func (p *Policy) addCommon(list string) {
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.common[strings.ToLower(line)] = struct{}{}
+	}
+}
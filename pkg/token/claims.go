@@ -0,0 +1,49 @@
+// Package token signs and verifies the application's JWTs.
+// It owns the single claims type shared by the issuer (auth module) and
+// every verifier (JWT middleware, other internal services via JWKS).
+//
+// ALGORITHMS:
+// - HS256: shared secret (JWT_SECRET); cannot be published via JWKS
+// - RS256: RSA private key from a PEM file
+// - EdDSA: Ed25519 private key from a PEM file
+//
+// ROTATION:
+// Every token carries a `kid` header. Additional public keys can be loaded
+// for verification only, so a new signing key can be introduced while
+// tokens signed by the previous key are still valid.
+package token
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Token types carried in the token_type claim.
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+	TypeMFA     = "mfa"
+	TypeInvite  = "invite"
+)
+
+// Claims represents the JWT claims structure.
+type Claims struct {
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	RoleID    string `json:"role_id,omitempty"`
+	SubRoleID string `json:"sub_role_id,omitempty"`
+	BranchID  string `json:"branch_id,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	FamilyID  string `json:"fid,omitempty"`
+	// PasswordChange restricts an access token to changing the password.
+	PasswordChange bool `json:"pwd_change,omitempty"`
+	// Actor is set on impersonation tokens: UserID is then the impersonated
+	// user and Actor the administrator acting as them.
+	Actor *Actor `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Actor is the administrator behind an impersonation token (RFC 8693 "act").
+type Actor struct {
+	UserID string `json:"sub"`
+	Email  string `json:"email,omitempty"`
+	// Write allows non-read requests; impersonation is read-only otherwise.
+	Write bool `json:"write,omitempty"`
+}
@@ -0,0 +1,252 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Errors returned by Parse.
+var (
+	ErrExpired = errors.New("token has expired")
+	ErrInvalid = errors.New("invalid token")
+)
+
+// Config describes where keys come from.
+type Config struct {
+	// Algorithm is HS256, RS256 or EdDSA.
+	Algorithm string
+	// Secret is the HMAC key (HS256 only).
+	Secret string
+	// PrivateKeyFile is the PEM signing key (RS256/EdDSA).
+	PrivateKeyFile string
+	// KeyID is the kid of the signing key; derived from the public key when empty.
+	KeyID string
+	// PublicKeyFiles are extra verification-only keys as "kid=path" entries,
+	// typically the previous signing key during a rotation.
+	PublicKeyFiles []string
+	// Issuer is set as iss and required on verification.
+	Issuer string
+	// Audience is set as aud and required on verification.
+	Audience string
+}
+
+// verificationKey is a key accepted for a given kid.
+type verificationKey struct {
+	alg string
+	key any
+}
+
+// KeySet signs tokens with one key and verifies them against all known keys.
+type KeySet struct {
+	alg        string
+	kid        string
+	signingKey any
+	verify     map[string]verificationKey
+	issuer     string
+	audience   string
+}
+
+// NewKeySet loads the signing key and verification keys.
+//
+// RETURNS: KeySet ready for use, or an error if a key cannot be loaded
+func NewKeySet(cfg Config) (*KeySet, error) {
+	ks := &KeySet{
+		alg:      cfg.Algorithm,
+		verify:   make(map[string]verificationKey),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	switch cfg.Algorithm {
+	case AlgHS256, "":
+		if cfg.Secret == "" {
+			return nil, errors.New("HS256 requires a secret")
+		}
+		ks.alg = AlgHS256
+		ks.kid = cfg.KeyID
+		if ks.kid == "" {
+			ks.kid = "hs256"
+		}
+		ks.signingKey = []byte(cfg.Secret)
+		ks.verify[ks.kid] = verificationKey{alg: AlgHS256, key: ks.signingKey}
+
+	case AlgRS256, AlgEdDSA:
+		private, public, err := loadPrivateKey(cfg.Algorithm, cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		ks.signingKey = private
+		ks.kid = cfg.KeyID
+		if ks.kid == "" {
+			if ks.kid, err = thumbprint(public); err != nil {
+				return nil, err
+			}
+		}
+		ks.verify[ks.kid] = verificationKey{alg: cfg.Algorithm, key: public}
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	for _, entry := range cfg.PublicKeyFiles {
+		kid, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("invalid public key entry %q, expected kid=path", entry)
+		}
+		if _, exists := ks.verify[kid]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", kid)
+		}
+
+		alg, public, err := loadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		ks.verify[kid] = verificationKey{alg: alg, key: public}
+	}
+
+	return ks, nil
+}
+
+// Algorithm returns the signing algorithm.
+func (ks *KeySet) Algorithm() string {
+	return ks.alg
+}
+
+// Sign stamps iss/aud onto the claims and signs them with the current key.
+func (ks *KeySet) Sign(claims *Claims) (string, error) {
+	claims.Issuer = ks.issuer
+	if ks.audience != "" {
+		claims.Audience = jwt.ClaimStrings{ks.audience}
+	}
+
+	t := jwt.NewWithClaims(signingMethod(ks.alg), claims)
+	t.Header["kid"] = ks.kid
+	return t.SignedString(ks.signingKey)
+}
+
+// Parse verifies the signature (selecting the key by kid), the algorithm,
+// expiry, issuer and audience, and returns the claims.
+func (ks *KeySet) Parse(tokenString string) (*Claims, error) {
+	opts := []jwt.ParserOption{jwt.WithIssuer(ks.issuer)}
+	if ks.audience != "" {
+		opts = append(opts, jwt.WithAudience(ks.audience))
+	}
+
+	t, err := jwt.ParseWithClaims(tokenString, &Claims{}, ks.keyFunc, opts...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	claims, ok := t.Claims.(*Claims)
+	if !ok || !t.Valid || claims.ExpiresAt == nil {
+		return nil, ErrInvalid
+	}
+
+	return claims, nil
+}
+
+func (ks *KeySet) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" && ks.alg == AlgHS256 {
+		kid = ks.kid // Tokens issued before kid headers were introduced
+	}
+
+	key, ok := ks.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	// The key decides the algorithm, never the token header.
+	if t.Method.Alg() != key.alg {
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+	}
+
+	return key.key, nil
+}
+
+func signingMethod(alg string) jwt.SigningMethod {
+	switch alg {
+	case AlgRS256:
+		return jwt.SigningMethodRS256
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+func loadPrivateKey(alg, path string) (crypto.PrivateKey, crypto.PublicKey, error) {
+	if path == "" {
+		return nil, nil, fmt.Errorf("%s requires a private key file", alg)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	switch alg {
+	case AlgRS256:
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse RSA private key %s: %w", path, err)
+		}
+		return key, &key.PublicKey, nil
+	default:
+		key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse Ed25519 private key %s: %w", path, err)
+		}
+		edKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s is not an Ed25519 key", path)
+		}
+		return edKey, edKey.Public(), nil
+	}
+}
+
+// loadPublicKey reads an RSA or Ed25519 public key and reports its algorithm.
+func loadPublicKey(path string) (string, crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return AlgRS256, key, nil
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return AlgEdDSA, key, nil
+	}
+
+	return "", nil, fmt.Errorf("%s is not an RSA or Ed25519 public key", path)
+}
+
+// thumbprint derives a stable kid from the DER-encoded public key.
+func thumbprint(public crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:8]), nil
+}
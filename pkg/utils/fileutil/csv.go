@@ -17,3 +17,11 @@ func GenerateCSV(w io.Writer, data [][]string) error {
 	}
 	return nil
 }
+
+// ReadCSV reads every record of a CSV file. Records may have differing
+// numbers of fields.
+func ReadCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
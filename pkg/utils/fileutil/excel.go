@@ -26,3 +26,15 @@ func GenerateXLSX(w io.Writer, sheetName string, data [][]string) error {
 	f.SetActiveSheet(index)
 	return f.Write(w)
 }
+
+// ReadXLSX reads the rows of the active sheet of an XLSX file as formatted
+// cell text. Trailing empty cells of a row are dropped.
+func ReadXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return f.GetRows(f.GetSheetName(f.GetActiveSheetIndex()))
+}
@@ -47,6 +47,14 @@ func formatValidationMessage(fe validator.FieldError) string {
 		return "Value is too short"
 	case "max":
 		return "Value is too long"
+	case "len":
+		return "Value must be exactly " + fe.Param() + " characters"
+	case "numeric":
+		return "Value must be numeric"
+	case "required_without":
+		return "This field is required when " + toSnakeCase(fe.Param()) + " is empty"
+	case "nefield":
+		return "Value must differ from " + toSnakeCase(fe.Param())
 	default:
 		return "Invalid value"
 	}